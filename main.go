@@ -17,6 +17,8 @@ func main() {
 	const databaseProvider = "postgres"
 	database.Initialize(databaseProvider, config.GetDatabasePath())
 	user.CreateRoot()
+	manager.GetInstance().StartVoteJob()
+	manager.GetInstance().StartNotificationDigestJob()
 	api.Start()
 	blockForever()
 }
@@ -19,7 +19,7 @@ func getCurrentUser(ctx context.Context) *models.User {
 	return nil
 }
 
-func validateRole(ctx context.Context, requiredRole models.RoleEnum) error {
+func getCurrentRoles(ctx context.Context) []models.RoleEnum {
 	var roles []models.RoleEnum
 
 	roleCtxVal := ctx.Value(ContextRoles)
@@ -27,6 +27,12 @@ func validateRole(ctx context.Context, requiredRole models.RoleEnum) error {
 		roles = roleCtxVal.([]models.RoleEnum)
 	}
 
+	return roles
+}
+
+func validateRole(ctx context.Context, requiredRole models.RoleEnum) error {
+	roles := getCurrentRoles(ctx)
+
 	valid := false
 
 	for _, role := range roles {
@@ -55,6 +61,10 @@ func validateEdit(ctx context.Context) error {
 	return validateRole(ctx, models.RoleEnumEdit)
 }
 
+func validateVote(ctx context.Context) error {
+	return validateRole(ctx, models.RoleEnumVote)
+}
+
 func validateInvite(ctx context.Context) error {
 	return validateRole(ctx, models.RoleEnumInvite)
 }
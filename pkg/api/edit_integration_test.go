@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stashapp/stashdb/pkg/api"
+	"github.com/stashapp/stashdb/pkg/manager/config"
 	"github.com/stashapp/stashdb/pkg/models"
 )
 
@@ -60,6 +61,829 @@ func (s *editTestRunner) verifyCancelEdit(edit *models.Edit) {
 	s.verifyEditApplication(false, edit)
 }
 
+func (s *editTestRunner) createVoter() *testRunner {
+	return s.createUserRunner([]models.RoleEnum{
+		models.RoleEnumVote,
+	})
+}
+
+func (s *editTestRunner) vote(voter *testRunner, edit *models.Edit, voteType models.VoteTypeEnum) (*models.Edit, error) {
+	s.t.Helper()
+	input := models.EditVoteInput{
+		ID:   edit.ID.String(),
+		Type: voteType,
+	}
+
+	votedEdit, err := voter.resolver.Mutation().EditVote(voter.ctx, input)
+	if err != nil {
+		s.t.Errorf("Error voting on edit: %s", err.Error())
+		return nil, err
+	}
+
+	return votedEdit, nil
+}
+
+func (s *editTestRunner) testUnauthorisedEditVote() {
+	// requires vote so should fail
+	_, err := s.resolver.Mutation().EditVote(s.ctx, models.EditVoteInput{})
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("EditVote: got %v want %v", err, api.ErrUnauthorized)
+	}
+}
+
+func (s *editTestRunner) testEditVoteAccept() {
+	defer config.Set(config.VoteApplicationThreshold, config.GetVoteApplicationThreshold())
+	config.Set(config.VoteApplicationThreshold, 2)
+
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	votedEdit, err := s.vote(s.createVoter(), createdEdit, models.VoteTypeEnumAccept)
+	if err != nil {
+		return
+	}
+
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), votedEdit)
+	s.verifyEditApplication(false, votedEdit)
+	if votedEdit.VoteCount != 1 {
+		s.fieldMismatch(1, votedEdit.VoteCount, "VoteCount")
+	}
+
+	votedEdit, err = s.vote(s.createVoter(), createdEdit, models.VoteTypeEnumAccept)
+	if err != nil {
+		return
+	}
+
+	s.verifyEditStatus(models.VoteStatusEnumAccepted.String(), votedEdit)
+	s.verifyEditApplication(true, votedEdit)
+	if votedEdit.VoteCount != 2 {
+		s.fieldMismatch(2, votedEdit.VoteCount, "VoteCount")
+	}
+
+	votes, _ := s.resolver.Edit().Votes(s.ctx, votedEdit)
+	if len(votes) != 2 {
+		s.fieldMismatch(2, len(votes), "Votes")
+	}
+}
+
+func (s *editTestRunner) testEditVoteReject() {
+	defer config.Set(config.VoteApplicationThreshold, config.GetVoteApplicationThreshold())
+	config.Set(config.VoteApplicationThreshold, 1)
+
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	votedEdit, err := s.vote(s.createVoter(), createdEdit, models.VoteTypeEnumReject)
+	if err != nil {
+		return
+	}
+
+	s.verifyEditStatus(models.VoteStatusEnumRejected.String(), votedEdit)
+	s.verifyEditApplication(false, votedEdit)
+	if votedEdit.VoteCount != -1 {
+		s.fieldMismatch(-1, votedEdit.VoteCount, "VoteCount")
+	}
+}
+
+func (s *editTestRunner) testEditVoteChange() {
+	defer config.Set(config.VoteApplicationThreshold, config.GetVoteApplicationThreshold())
+	config.Set(config.VoteApplicationThreshold, 0)
+
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	voter := s.createVoter()
+	if _, err := s.vote(voter, createdEdit, models.VoteTypeEnumAccept); err != nil {
+		return
+	}
+	votedEdit, err := s.vote(voter, createdEdit, models.VoteTypeEnumReject)
+	if err != nil {
+		return
+	}
+
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), votedEdit)
+	if votedEdit.VoteCount != -1 {
+		s.fieldMismatch(-1, votedEdit.VoteCount, "VoteCount")
+	}
+
+	votes, _ := s.resolver.Edit().Votes(s.ctx, votedEdit)
+	if len(votes) != 1 {
+		s.fieldMismatch(1, len(votes), "Votes")
+	}
+
+	// a net count of zero replaces the previous count
+	if _, err := s.vote(voter, createdEdit, models.VoteTypeEnumAccept); err != nil {
+		return
+	}
+	if storedEdit := s.findEdit(createdEdit); storedEdit != nil && storedEdit.VoteCount != 1 {
+		s.fieldMismatch(1, storedEdit.VoteCount, "VoteCount")
+	}
+
+	if _, err := s.vote(s.createVoter(), createdEdit, models.VoteTypeEnumReject); err != nil {
+		return
+	}
+	if storedEdit := s.findEdit(createdEdit); storedEdit != nil && storedEdit.VoteCount != 0 {
+		s.fieldMismatch(0, storedEdit.VoteCount, "VoteCount")
+	}
+}
+
+func (s *editTestRunner) testOwnEditVote() {
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	input := models.EditVoteInput{
+		ID:   createdEdit.ID.String(),
+		Type: models.VoteTypeEnumAccept,
+	}
+	_, err = s.resolver.Mutation().EditVote(s.ctx, input)
+	if err == nil {
+		s.t.Error("Expected error voting on own edit")
+	}
+}
+
+func (s *editTestRunner) comment(runner *testRunner, edit *models.Edit, text string, parentID *string) *models.EditComment {
+	s.t.Helper()
+	input := models.EditCommentInput{
+		ID:       edit.ID.String(),
+		Comment:  text,
+		ParentID: parentID,
+	}
+
+	commentedEdit, err := runner.resolver.Mutation().EditComment(runner.ctx, input)
+	if err != nil {
+		s.t.Errorf("Error commenting on edit: %s", err.Error())
+		return nil
+	}
+
+	comments, _ := runner.resolver.Edit().Comments(runner.ctx, commentedEdit)
+	for _, comment := range comments {
+		if comment.Text == text {
+			return comment
+		}
+	}
+
+	s.t.Errorf("Comment not found: %s", text)
+	return nil
+}
+
+func (s *editTestRunner) testEditCommentReply() {
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	comment := s.comment(&s.testRunner, createdEdit, "comment", nil)
+	if comment == nil {
+		return
+	}
+
+	parentID := comment.ID.String()
+	reply := s.comment(&s.testRunner, createdEdit, "reply", &parentID)
+	if reply == nil {
+		return
+	}
+
+	parent, _ := s.resolver.EditComment().Parent(s.ctx, reply)
+	if parent == nil || parent.ID != comment.ID {
+		s.fieldMismatch(comment, parent, "Parent")
+	}
+
+	comments, _ := s.resolver.Edit().Comments(s.ctx, createdEdit)
+	if len(comments) != 2 {
+		s.fieldMismatch(2, len(comments), "Comments")
+	}
+}
+
+func (s *editTestRunner) testEditCommentUpdate() {
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	comment := s.comment(&s.testRunner, createdEdit, "original", nil)
+	if comment == nil {
+		return
+	}
+
+	input := models.EditCommentUpdateInput{
+		ID:      comment.ID.String(),
+		Comment: "updated",
+	}
+
+	// only the author may update the comment
+	other := s.createUserRunner([]models.RoleEnum{models.RoleEnumEdit})
+	_, err = other.resolver.Mutation().EditCommentUpdate(other.ctx, input)
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("EditCommentUpdate: got %v want %v", err, api.ErrUnauthorized)
+	}
+
+	updated, err := s.resolver.Mutation().EditCommentUpdate(s.ctx, input)
+	if err != nil {
+		s.t.Errorf("Error updating comment: %s", err.Error())
+		return
+	}
+
+	if updated.Text != input.Comment {
+		s.fieldMismatch(input.Comment, updated.Text, "Comment")
+	}
+
+	edited, _ := s.resolver.EditComment().Edited(s.ctx, updated)
+	if edited == nil {
+		s.t.Error("Expected edited time to be set")
+	}
+}
+
+func (s *editTestRunner) testEditCommentHide() {
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	comment := s.comment(&s.testRunner, createdEdit, "abusive", nil)
+	if comment == nil {
+		return
+	}
+
+	input := models.EditCommentHideInput{
+		ID:     comment.ID.String(),
+		Hidden: true,
+	}
+
+	other := s.createUserRunner([]models.RoleEnum{models.RoleEnumEdit})
+	_, err = other.resolver.Mutation().EditCommentHide(other.ctx, input)
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("EditCommentHide: got %v want %v", err, api.ErrUnauthorized)
+	}
+
+	hidden, err := s.resolver.Mutation().EditCommentHide(s.ctx, input)
+	if err != nil {
+		s.t.Errorf("Error hiding comment: %s", err.Error())
+		return
+	}
+
+	if !hidden.Hidden {
+		s.fieldMismatch(true, hidden.Hidden, "Hidden")
+	}
+
+	// the text is only visible to admins
+	text, _ := other.resolver.EditComment().Comment(other.ctx, hidden)
+	if text != "" {
+		s.fieldMismatch("", text, "Comment")
+	}
+	text, _ = s.resolver.EditComment().Comment(s.ctx, hidden)
+	if text != comment.Text {
+		s.fieldMismatch(comment.Text, text, "Comment")
+	}
+}
+
+func (s *editTestRunner) amend(runner *testRunner, edit *models.Edit, name string) (*models.Edit, error) {
+	editID := edit.ID.String()
+	input := models.TagEditInput{
+		Edit: &models.EditInput{
+			Operation: models.OperationEnumCreate,
+			EditID:    &editID,
+		},
+		Details: &models.TagEditDetailsInput{
+			Name: &name,
+		},
+	}
+
+	return runner.resolver.Mutation().TagEdit(runner.ctx, input)
+}
+
+func (s *editTestRunner) testAmendEdit() {
+	defer config.Set(config.VoteApplicationThreshold, config.GetVoteApplicationThreshold())
+	config.Set(config.VoteApplicationThreshold, 0)
+
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+	originalDetails := s.getEditTagDetails(createdEdit)
+
+	voter := s.createVoter()
+	if _, err := s.vote(voter, createdEdit, models.VoteTypeEnumAccept); err != nil {
+		return
+	}
+
+	name := s.generateTagName()
+	amendedEdit, err := s.amend(&s.testRunner, createdEdit, name)
+	if err != nil {
+		s.t.Errorf("Error amending edit: %s", err.Error())
+		return
+	}
+
+	if amendedEdit.ID != createdEdit.ID {
+		s.fieldMismatch(createdEdit.ID, amendedEdit.ID, "ID")
+	}
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), amendedEdit)
+	if amendedEdit.VoteCount != 0 {
+		s.fieldMismatch(0, amendedEdit.VoteCount, "VoteCount")
+	}
+	if storedEdit := s.findEdit(amendedEdit); storedEdit != nil && storedEdit.VoteCount != 0 {
+		s.fieldMismatch(0, storedEdit.VoteCount, "Stored VoteCount")
+	}
+
+	details := s.getEditTagDetails(amendedEdit)
+	if *details.Name != name {
+		s.fieldMismatch(name, *details.Name, "Name")
+	}
+
+	// the previous version is kept as a revision
+	revisions, _ := s.resolver.Edit().Revisions(s.ctx, amendedEdit)
+	if len(revisions) != 1 {
+		s.fieldMismatch(1, len(revisions), "Revisions")
+		return
+	}
+	revisionDetails, _ := s.resolver.EditRevision().Details(s.ctx, revisions[0])
+	if *revisionDetails.(*models.TagEdit).Name != *originalDetails.Name {
+		s.fieldMismatch(*originalDetails.Name, *revisionDetails.(*models.TagEdit).Name, "Revision Name")
+	}
+
+	// votes cast before the amendment are outdated
+	votes, _ := s.resolver.Edit().Votes(s.ctx, amendedEdit)
+	if len(votes) != 1 || !votes[0].Outdated {
+		s.t.Error("Expected vote to be outdated")
+	}
+
+	votedEdit, err := s.vote(voter, amendedEdit, models.VoteTypeEnumReject)
+	if err != nil {
+		return
+	}
+	if votedEdit.VoteCount != -1 {
+		s.fieldMismatch(-1, votedEdit.VoteCount, "VoteCount")
+	}
+}
+
+func (s *editTestRunner) testAmendEditNotAuthor() {
+	createdEdit, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	other := s.createUserRunner([]models.RoleEnum{models.RoleEnumEdit})
+	if _, err := s.amend(other, createdEdit, s.generateTagName()); err == nil {
+		s.t.Error("Expected error amending edit of another user")
+	}
+}
+
+func (s *editTestRunner) createRenameTagEdit(tag *models.Tag) (*models.Edit, error) {
+	s.t.Helper()
+	name := s.generateTagName()
+	id := tag.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	return s.createTestTagEdit(models.OperationEnumModify, &models.TagEditDetailsInput{Name: &name}, &editInput)
+}
+
+func (s *editTestRunner) createEditGroup(edits ...*models.Edit) (*models.EditGroup, error) {
+	s.t.Helper()
+	var ids []string
+	for _, e := range edits {
+		ids = append(ids, e.ID.String())
+	}
+
+	group, err := s.resolver.Mutation().EditGroupCreate(s.ctx, models.EditGroupInput{EditIds: ids})
+	if err != nil {
+		s.t.Errorf("Error creating edit group: %s", err.Error())
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (s *editTestRunner) findEdit(edit *models.Edit) *models.Edit {
+	s.t.Helper()
+	id := edit.ID.String()
+	ret, err := s.resolver.Query().FindEdit(s.ctx, &id)
+	if err != nil {
+		s.t.Errorf("Error finding edit: %s", err.Error())
+	}
+
+	return ret
+}
+
+func (s *editTestRunner) testEditGroupVoteAccept() {
+	defer config.Set(config.VoteApplicationThreshold, config.GetVoteApplicationThreshold())
+	config.Set(config.VoteApplicationThreshold, 1)
+
+	edit1, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+	edit2, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	group, err := s.createEditGroup(edit1, edit2)
+	if err != nil {
+		return
+	}
+
+	edits, _ := s.resolver.EditGroup().Edits(s.ctx, group)
+	if len(edits) != 2 {
+		s.fieldMismatch(2, len(edits), "Edits")
+		return
+	}
+
+	// a vote on one edit of the group accepts the whole group
+	votedEdit, err := s.vote(s.createVoter(), edit1, models.VoteTypeEnumAccept)
+	if err != nil {
+		return
+	}
+
+	s.verifyEditStatus(models.VoteStatusEnumAccepted.String(), votedEdit)
+	s.verifyEditApplication(true, votedEdit)
+
+	otherEdit := s.findEdit(edit2)
+	s.verifyEditStatus(models.VoteStatusEnumAccepted.String(), otherEdit)
+	s.verifyEditApplication(true, otherEdit)
+	if otherEdit.VoteCount != 1 {
+		s.fieldMismatch(1, otherEdit.VoteCount, "VoteCount")
+	}
+}
+
+func (s *editTestRunner) testAmendGroupedEdit() {
+	defer config.Set(config.VoteApplicationThreshold, config.GetVoteApplicationThreshold())
+	config.Set(config.VoteApplicationThreshold, 0)
+
+	edit1, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+	edit2, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	if _, err := s.createEditGroup(edit1, edit2); err != nil {
+		return
+	}
+
+	if _, err := s.vote(s.createVoter(), edit1, models.VoteTypeEnumAccept); err != nil {
+		return
+	}
+
+	if _, err := s.amend(&s.testRunner, edit1, s.generateTagName()); err != nil {
+		s.t.Errorf("Error amending edit: %s", err.Error())
+		return
+	}
+
+	// amending one edit outdates the votes of the whole group
+	for _, e := range []*models.Edit{edit1, edit2} {
+		if storedEdit := s.findEdit(e); storedEdit != nil && storedEdit.VoteCount != 0 {
+			s.fieldMismatch(0, storedEdit.VoteCount, "VoteCount")
+		}
+	}
+}
+
+func (s *editTestRunner) testApplyEditGroupRollback() {
+	tag1, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+	tag2, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	edit1, err := s.createRenameTagEdit(tag1)
+	if err != nil {
+		return
+	}
+	edit2, err := s.createRenameTagEdit(tag2)
+	if err != nil {
+		return
+	}
+
+	if _, err := s.createEditGroup(edit1, edit2); err != nil {
+		return
+	}
+
+	// renaming the second tag outside the group makes the second edit
+	// conflict with its target
+	conflictingEdit, err := s.createRenameTagEdit(tag2)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(conflictingEdit.ID.String()); err != nil {
+		return
+	}
+
+	_, err = s.resolver.Mutation().ApplyEdit(s.ctx, models.ApplyEditInput{ID: edit1.ID.String()})
+	if err == nil {
+		s.t.Error("Expected error applying edit group with a conflicting edit")
+		return
+	}
+
+	// none of the edits of the group may be applied
+	id := tag1.ID.String()
+	tag, _ := s.resolver.Query().FindTag(s.ctx, &id, nil)
+	if tag.Name != tag1.Name {
+		s.fieldMismatch(tag1.Name, tag.Name, "Name")
+	}
+
+	for _, e := range []*models.Edit{edit1, edit2} {
+		groupedEdit := s.findEdit(e)
+		s.verifyEditStatus(models.VoteStatusEnumPending.String(), groupedEdit)
+		s.verifyEditApplication(false, groupedEdit)
+	}
+}
+
+func (s *editTestRunner) testEditGroupNotAuthor() {
+	edit1, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+	edit2, err := s.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	other := s.createUserRunner([]models.RoleEnum{models.RoleEnumEdit})
+	input := models.EditGroupInput{
+		EditIds: []string{edit1.ID.String(), edit2.ID.String()},
+	}
+	if _, err := other.resolver.Mutation().EditGroupCreate(other.ctx, input); err == nil {
+		s.t.Error("Expected error grouping edits of another user")
+	}
+}
+
+func (s *editTestRunner) queryUserCount(name string, userFilter models.UserFilterType) int {
+	s.t.Helper()
+	userFilter.Name = &name
+	result, err := s.resolver.Query().QueryUsers(s.ctx, &userFilter, nil)
+	if err != nil {
+		s.t.Errorf("Error querying users: %s", err.Error())
+		return 0
+	}
+
+	return result.Count
+}
+
+func (s *editTestRunner) testUserVoteStats() {
+	defer config.Set(config.VoteApplicationThreshold, config.GetVoteApplicationThreshold())
+	config.Set(config.VoteApplicationThreshold, 0)
+
+	author := s.createUserRunner([]models.RoleEnum{models.RoleEnumEdit})
+	rejectVoter := s.createVoter()
+	acceptVoter := s.createVoter()
+	rejectVoterUser, _ := rejectVoter.resolver.Query().Me(rejectVoter.ctx)
+	acceptVoterUser, _ := acceptVoter.resolver.Query().Me(acceptVoter.ctx)
+
+	acceptedEdit, err := author.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	if _, err := s.vote(rejectVoter, acceptedEdit, models.VoteTypeEnumReject); err != nil {
+		return
+	}
+	if _, err := s.vote(acceptVoter, acceptedEdit, models.VoteTypeEnumAccept); err != nil {
+		return
+	}
+	if _, err := s.applyEdit(acceptedEdit.ID.String()); err != nil {
+		return
+	}
+
+	// a reject vote on an accepted edit is unsuccessful
+	userResolver := s.resolver.User()
+	if n, _ := userResolver.SuccessfulVotes(s.ctx, rejectVoterUser); n != 0 {
+		s.fieldMismatch(0, n, "SuccessfulVotes")
+	}
+	if n, _ := userResolver.UnsuccessfulVotes(s.ctx, rejectVoterUser); n != 1 {
+		s.fieldMismatch(1, n, "UnsuccessfulVotes")
+	}
+	if n, _ := userResolver.SuccessfulVotes(s.ctx, acceptVoterUser); n != 1 {
+		s.fieldMismatch(1, n, "SuccessfulVotes")
+	}
+	if n, _ := userResolver.UnsuccessfulVotes(s.ctx, acceptVoterUser); n != 0 {
+		s.fieldMismatch(0, n, "UnsuccessfulVotes")
+	}
+}
+
+func (s *editTestRunner) testUserEditStats() {
+	defer config.Set(config.VoteApplicationThreshold, config.GetVoteApplicationThreshold())
+	config.Set(config.VoteApplicationThreshold, 1)
+
+	author := s.createUserRunner([]models.RoleEnum{models.RoleEnumEdit})
+	voter := s.createVoter()
+	authorUser, _ := author.resolver.Query().Me(author.ctx)
+	voterUser, _ := voter.resolver.Query().Me(voter.ctx)
+
+	acceptedEdit, err := author.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+	cancelledEdit, err := author.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+	if _, err := author.createTestTagEdit(models.OperationEnumCreate, nil, nil); err != nil {
+		return
+	}
+
+	if _, err := s.vote(voter, acceptedEdit, models.VoteTypeEnumAccept); err != nil {
+		return
+	}
+	if _, err := s.resolver.Mutation().CancelEdit(s.ctx, models.CancelEditInput{ID: cancelledEdit.ID.String()}); err != nil {
+		s.t.Errorf("Error cancelling edit: %s", err.Error())
+		return
+	}
+
+	userResolver := s.resolver.User()
+	if n, _ := userResolver.SuccessfulEdits(s.ctx, authorUser); n != 1 {
+		s.fieldMismatch(1, n, "SuccessfulEdits")
+	}
+	if n, _ := userResolver.UnsuccessfulEdits(s.ctx, authorUser); n != 1 {
+		s.fieldMismatch(1, n, "UnsuccessfulEdits")
+	}
+	if n, _ := userResolver.SuccessfulVotes(s.ctx, voterUser); n != 1 {
+		s.fieldMismatch(1, n, "SuccessfulVotes")
+	}
+	if n, _ := userResolver.UnsuccessfulVotes(s.ctx, voterUser); n != 0 {
+		s.fieldMismatch(0, n, "UnsuccessfulVotes")
+	}
+
+	successfulEdits := models.UserFilterType{
+		SuccessfulEdits: &models.IntCriterionInput{
+			Value:    0,
+			Modifier: models.CriterionModifierGreaterThan,
+		},
+	}
+	if n := s.queryUserCount(authorUser.Name, successfulEdits); n != 1 {
+		s.fieldMismatch(1, n, "Users with successful edits")
+	}
+	if n := s.queryUserCount(voterUser.Name, successfulEdits); n != 0 {
+		s.fieldMismatch(0, n, "Users with successful edits")
+	}
+
+	unsuccessfulVotes := models.UserFilterType{
+		UnsuccessfulVotes: &models.IntCriterionInput{
+			Value:    0,
+			Modifier: models.CriterionModifierEquals,
+		},
+	}
+	if n := s.queryUserCount(voterUser.Name, unsuccessfulVotes); n != 1 {
+		s.fieldMismatch(1, n, "Users without unsuccessful votes")
+	}
+}
+
+func (s *editTestRunner) createAddAliasTagEdit(tag *models.Tag) (*models.Edit, error) {
+	s.t.Helper()
+	id := tag.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+	detailsInput := models.TagEditDetailsInput{
+		Aliases: []string{s.generateTagName()},
+	}
+
+	return s.createTestTagEdit(models.OperationEnumModify, &detailsInput, &editInput)
+}
+
+func (s *editTestRunner) testTrustedEditApplied() {
+	trusted := &editTestRunner{
+		testRunner: *s.createUserRunner([]models.RoleEnum{models.RoleEnumTrustedEdit}),
+	}
+
+	tag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	aliasEdit, err := trusted.createAddAliasTagEdit(tag)
+	if err != nil {
+		return
+	}
+
+	s.verifyEditStatus(models.VoteStatusEnumImmediateAccepted.String(), aliasEdit)
+	s.verifyEditApplication(true, aliasEdit)
+
+	aliases, _ := s.resolver.Tag().Aliases(s.ctx, tag)
+	if len(aliases) != 1 {
+		s.fieldMismatch(1, len(aliases), "Aliases")
+	}
+
+	// auto-applied edits can be reverted
+	if _, err := s.revertEdit(aliasEdit.ID.String()); err != nil {
+		return
+	}
+
+	aliases, _ = s.resolver.Tag().Aliases(s.ctx, tag)
+	if len(aliases) != 0 {
+		s.fieldMismatch(0, len(aliases), "Aliases")
+	}
+
+	// other changes are voted on
+	renameEdit, err := trusted.createRenameTagEdit(tag)
+	if err != nil {
+		return
+	}
+
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), renameEdit)
+	s.verifyEditApplication(false, renameEdit)
+}
+
+func (s *editTestRunner) testTrustedEditApplyFailure() {
+	trusted := &editTestRunner{
+		testRunner: *s.createUserRunner([]models.RoleEnum{models.RoleEnumTrustedEdit}),
+	}
+
+	// aliases are unique, so the edit adding the alias of another tag
+	// cannot be applied
+	alias := s.generateTagName()
+	if _, err := s.createTestTag(&models.TagCreateInput{
+		Name:    s.generateTagName(),
+		Aliases: []string{alias},
+	}); err != nil {
+		return
+	}
+
+	tag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	id := tag.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+	detailsInput := models.TagEditDetailsInput{
+		Aliases: []string{alias},
+	}
+	aliasEdit, err := trusted.createTestTagEdit(models.OperationEnumModify, &detailsInput, &editInput)
+	if err != nil {
+		return
+	}
+
+	// the edit is kept for a vote
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), aliasEdit)
+	s.verifyEditApplication(false, aliasEdit)
+
+	storedEdit := s.findEdit(aliasEdit)
+	if storedEdit == nil {
+		s.t.Error("Trusted edit was not saved")
+		return
+	}
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), storedEdit)
+	s.verifyEditApplication(false, storedEdit)
+}
+
+func (s *editTestRunner) testTrustedEditThreshold() {
+	defer config.Set(config.VoteApplicationThreshold, config.GetVoteApplicationThreshold())
+	defer config.Set(config.TrustedEditThreshold, config.GetTrustedEditThreshold())
+	config.Set(config.VoteApplicationThreshold, 1)
+	config.Set(config.TrustedEditThreshold, 1)
+
+	editor := &editTestRunner{
+		testRunner: *s.createUserRunner([]models.RoleEnum{models.RoleEnumEdit}),
+	}
+
+	tag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	pendingEdit, err := editor.createAddAliasTagEdit(tag)
+	if err != nil {
+		return
+	}
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), pendingEdit)
+
+	if _, err := s.vote(s.createVoter(), pendingEdit, models.VoteTypeEnumAccept); err != nil {
+		return
+	}
+
+	// the accepted edit makes the editor trusted
+	aliasEdit, err := editor.createAddAliasTagEdit(tag)
+	if err != nil {
+		return
+	}
+	s.verifyEditStatus(models.VoteStatusEnumImmediateAccepted.String(), aliasEdit)
+	s.verifyEditApplication(true, aliasEdit)
+
+	renameEdit, err := editor.createRenameTagEdit(tag)
+	if err != nil {
+		return
+	}
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), renameEdit)
+}
+
 func TestUnauthorisedEditEdit(t *testing.T) {
 	pt := &editTestRunner{
 		testRunner: *asRead(t),
@@ -78,3 +902,100 @@ func TestCancelEdit(t *testing.T) {
 	pt := createEditTestRunner(t)
 	pt.testCancelEdit()
 }
+
+func TestUnauthorisedEditVote(t *testing.T) {
+	pt := &editTestRunner{
+		testRunner: *asRead(t),
+	}
+	pt.testUnauthorisedEditVote()
+}
+
+func TestEditVoteAccept(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testEditVoteAccept()
+}
+
+func TestEditVoteReject(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testEditVoteReject()
+}
+
+func TestEditVoteChange(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testEditVoteChange()
+}
+
+func TestOwnEditVote(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testOwnEditVote()
+}
+
+func TestEditCommentReply(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testEditCommentReply()
+}
+
+func TestEditCommentUpdate(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testEditCommentUpdate()
+}
+
+func TestEditCommentHide(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testEditCommentHide()
+}
+
+func TestAmendGroupedEdit(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testAmendGroupedEdit()
+}
+
+func TestAmendEdit(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testAmendEdit()
+}
+
+func TestAmendEditNotAuthor(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testAmendEditNotAuthor()
+}
+
+func TestEditGroupVoteAccept(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testEditGroupVoteAccept()
+}
+
+func TestApplyEditGroupRollback(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testApplyEditGroupRollback()
+}
+
+func TestEditGroupNotAuthor(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testEditGroupNotAuthor()
+}
+
+func TestUserVoteStats(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testUserVoteStats()
+}
+
+func TestUserEditStats(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testUserEditStats()
+}
+
+func TestTrustedEditApplied(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testTrustedEditApplied()
+}
+
+func TestTrustedEditApplyFailure(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testTrustedEditApplyFailure()
+}
+
+func TestTrustedEditThreshold(t *testing.T) {
+	pt := createEditTestRunner(t)
+	pt.testTrustedEditThreshold()
+}
@@ -324,6 +324,22 @@ func (s *testRunner) createTestUser(input *models.UserCreateInput) (*models.User
 	return createdUser, nil
 }
 
+func (s *testRunner) createUserRunner(roles []models.RoleEnum) *testRunner {
+	name := s.generateUserName()
+	input := models.UserCreateInput{
+		Name:     name,
+		Email:    name + "@example.com",
+		Password: "password" + name,
+		Roles:    roles,
+	}
+	voter, err := s.createTestUser(&input)
+	if err != nil {
+		return nil
+	}
+
+	return createTestRunner(s.t, voter, roles)
+}
+
 func (s *testRunner) createTestTagEdit(operation models.OperationEnum, detailsInput *models.TagEditDetailsInput, editInput *models.EditInput) (*models.Edit, error) {
 	s.t.Helper()
 
@@ -357,6 +373,108 @@ func (s *testRunner) createTestTagEdit(operation models.OperationEnum, detailsIn
 	return createdEdit, nil
 }
 
+func (s *testRunner) createTestPerformerEdit(operation models.OperationEnum, detailsInput *models.PerformerEditDetailsInput, editInput *models.EditInput) (*models.Edit, error) {
+	s.t.Helper()
+
+	if editInput == nil {
+		input := models.EditInput{
+			Operation: operation,
+		}
+		editInput = &input
+	}
+
+	if detailsInput == nil {
+		name := s.generatePerformerName()
+		input := models.PerformerEditDetailsInput{
+			Name: &name,
+		}
+		detailsInput = &input
+	}
+
+	performerEditInput := models.PerformerEditInput{
+		Edit:    editInput,
+		Details: detailsInput,
+	}
+
+	createdEdit, err := s.resolver.Mutation().PerformerEdit(s.ctx, performerEditInput)
+
+	if err != nil {
+		s.t.Errorf("Error creating edit: %s", err.Error())
+		return nil, err
+	}
+
+	return createdEdit, nil
+}
+
+func (s *testRunner) createTestSceneEdit(operation models.OperationEnum, detailsInput *models.SceneEditDetailsInput, editInput *models.EditInput) (*models.Edit, error) {
+	s.t.Helper()
+
+	if editInput == nil {
+		input := models.EditInput{
+			Operation: operation,
+		}
+		editInput = &input
+	}
+
+	if detailsInput == nil {
+		title := "title"
+		input := models.SceneEditDetailsInput{
+			Title: &title,
+			Fingerprints: []*models.FingerprintInput{
+				s.generateSceneFingerprint(),
+			},
+		}
+		detailsInput = &input
+	}
+
+	sceneEditInput := models.SceneEditInput{
+		Edit:    editInput,
+		Details: detailsInput,
+	}
+
+	createdEdit, err := s.resolver.Mutation().SceneEdit(s.ctx, sceneEditInput)
+
+	if err != nil {
+		s.t.Errorf("Error creating edit: %s", err.Error())
+		return nil, err
+	}
+
+	return createdEdit, nil
+}
+
+func (s *testRunner) createTestStudioEdit(operation models.OperationEnum, detailsInput *models.StudioEditDetailsInput, editInput *models.EditInput) (*models.Edit, error) {
+	s.t.Helper()
+
+	if editInput == nil {
+		input := models.EditInput{
+			Operation: operation,
+		}
+		editInput = &input
+	}
+
+	if detailsInput == nil {
+		name := s.generateStudioName()
+		input := models.StudioEditDetailsInput{
+			Name: &name,
+		}
+		detailsInput = &input
+	}
+
+	studioEditInput := models.StudioEditInput{
+		Edit:    editInput,
+		Details: detailsInput,
+	}
+
+	createdEdit, err := s.resolver.Mutation().StudioEdit(s.ctx, studioEditInput)
+
+	if err != nil {
+		s.t.Errorf("Error creating edit: %s", err.Error())
+		return nil, err
+	}
+
+	return createdEdit, nil
+}
+
 func (s *testRunner) applyEdit(id string) (*models.Edit, error) {
 	s.t.Helper()
 
@@ -373,6 +491,22 @@ func (s *testRunner) applyEdit(id string) (*models.Edit, error) {
 	return appliedEdit, nil
 }
 
+func (s *testRunner) revertEdit(id string) (*models.Edit, error) {
+	s.t.Helper()
+
+	input := models.RevertEditInput{
+		ID: id,
+	}
+	revertEdit, err := s.resolver.Mutation().RevertEdit(s.ctx, input)
+
+	if err != nil {
+		s.t.Errorf("Error reverting edit: %s", err.Error())
+		return nil, err
+	}
+
+	return revertEdit, nil
+}
+
 func (s *testRunner) getEditTagDetails(input *models.Edit) *models.TagEdit {
 	s.t.Helper()
 	r := s.resolver.Edit()
@@ -391,6 +525,60 @@ func (s *testRunner) getEditTagTarget(input *models.Edit) *models.Tag {
 	return tagTarget
 }
 
+func (s *testRunner) getEditPerformerDetails(input *models.Edit) *models.PerformerEdit {
+	s.t.Helper()
+	r := s.resolver.Edit()
+
+	details, _ := r.Details(s.ctx, input)
+	performerDetails := details.(*models.PerformerEdit)
+	return performerDetails
+}
+
+func (s *testRunner) getEditPerformerTarget(input *models.Edit) *models.Performer {
+	s.t.Helper()
+	r := s.resolver.Edit()
+
+	target, _ := r.Target(s.ctx, input)
+	performerTarget := target.(*models.Performer)
+	return performerTarget
+}
+
+func (s *testRunner) getEditSceneDetails(input *models.Edit) *models.SceneEdit {
+	s.t.Helper()
+	r := s.resolver.Edit()
+
+	details, _ := r.Details(s.ctx, input)
+	sceneDetails := details.(*models.SceneEdit)
+	return sceneDetails
+}
+
+func (s *testRunner) getEditSceneTarget(input *models.Edit) *models.Scene {
+	s.t.Helper()
+	r := s.resolver.Edit()
+
+	target, _ := r.Target(s.ctx, input)
+	sceneTarget := target.(*models.Scene)
+	return sceneTarget
+}
+
+func (s *testRunner) getEditStudioDetails(input *models.Edit) *models.StudioEdit {
+	s.t.Helper()
+	r := s.resolver.Edit()
+
+	details, _ := r.Details(s.ctx, input)
+	studioDetails := details.(*models.StudioEdit)
+	return studioDetails
+}
+
+func (s *testRunner) getEditStudioTarget(input *models.Edit) *models.Studio {
+	s.t.Helper()
+	r := s.resolver.Edit()
+
+	target, _ := r.Target(s.ctx, input)
+	studioTarget := target.(*models.Studio)
+	return studioTarget
+}
+
 func compareUrls(input []*models.URLInput, urls []*models.URL) bool {
 	if len(urls) != len(input) {
 		return false
@@ -0,0 +1,187 @@
+// +build integration
+
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stashapp/stashdb/pkg/manager/config"
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+type notificationTestRunner struct {
+	editTestRunner
+}
+
+func createNotificationTestRunner(t *testing.T) *notificationTestRunner {
+	return &notificationTestRunner{
+		editTestRunner: *createEditTestRunner(t),
+	}
+}
+
+func (s *notificationTestRunner) getNotifications(runner *testRunner, unreadOnly bool) []*models.Notification {
+	s.t.Helper()
+	me, err := runner.resolver.Query().Me(runner.ctx)
+	if err != nil {
+		s.t.Errorf("Error finding current user: %s", err.Error())
+		return nil
+	}
+
+	notifications, err := runner.resolver.User().Notifications(runner.ctx, me, &unreadOnly)
+	if err != nil {
+		s.t.Errorf("Error finding notifications: %s", err.Error())
+		return nil
+	}
+
+	return notifications
+}
+
+func (s *notificationTestRunner) verifyNotificationTypes(expected []models.NotificationEnum, notifications []*models.Notification) {
+	s.t.Helper()
+	if len(notifications) != len(expected) {
+		s.fieldMismatch(len(expected), len(notifications), "Notifications")
+		return
+	}
+
+	// notifications created within the same second have no defined order
+	counts := make(map[string]int)
+	for _, t := range expected {
+		counts[t.String()]++
+	}
+	for _, n := range notifications {
+		counts[n.Type]--
+	}
+	for t, c := range counts {
+		if c != 0 {
+			s.t.Errorf("Notification type %s: got %d more than expected", t, -c)
+		}
+	}
+}
+
+func (s *notificationTestRunner) testEditNotifications() {
+	defer config.Set(config.VoteApplicationThreshold, config.GetVoteApplicationThreshold())
+	config.Set(config.VoteApplicationThreshold, 1)
+
+	author := s.createUserRunner([]models.RoleEnum{models.RoleEnumEdit})
+	createdEdit, err := author.createTestTagEdit(models.OperationEnumCreate, nil, nil)
+	if err != nil {
+		return
+	}
+
+	// the author is not notified of their own comments
+	s.comment(author, createdEdit, "authorComment", nil)
+	s.comment(s.createUserRunner([]models.RoleEnum{models.RoleEnumEdit}), createdEdit, "otherComment", nil)
+	if _, err := s.vote(s.createVoter(), createdEdit, models.VoteTypeEnumAccept); err != nil {
+		return
+	}
+
+	notifications := s.getNotifications(author, true)
+	s.verifyNotificationTypes([]models.NotificationEnum{
+		models.NotificationEnumEditApplied,
+		models.NotificationEnumEditVote,
+		models.NotificationEnumEditComment,
+	}, notifications)
+
+	input := models.MarkNotificationsReadInput{
+		Ids: []string{notifications[0].ID.String()},
+	}
+	if _, err := author.resolver.Mutation().MarkNotificationsRead(author.ctx, input); err != nil {
+		s.t.Errorf("Error marking notifications read: %s", err.Error())
+		return
+	}
+
+	if unread := s.getNotifications(author, true); len(unread) != 2 {
+		s.fieldMismatch(2, len(unread), "Unread notifications")
+	}
+
+	if _, err := author.resolver.Mutation().MarkNotificationsRead(author.ctx, models.MarkNotificationsReadInput{}); err != nil {
+		s.t.Errorf("Error marking notifications read: %s", err.Error())
+		return
+	}
+
+	if unread := s.getNotifications(author, true); len(unread) != 0 {
+		s.fieldMismatch(0, len(unread), "Unread notifications")
+	}
+	if all := s.getNotifications(author, false); len(all) != 3 {
+		s.fieldMismatch(3, len(all), "Notifications")
+	}
+}
+
+func (s *notificationTestRunner) testWatchedEditNotification() {
+	createdTag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	watcher := s.createUserRunner([]models.RoleEnum{models.RoleEnumRead})
+	input := models.NotificationSubscriptionInput{
+		TargetType: models.TargetTypeEnumTag,
+		TargetID:   createdTag.ID.String(),
+		Subscribed: true,
+	}
+	if _, err := watcher.resolver.Mutation().SubscribeNotifications(watcher.ctx, input); err != nil {
+		s.t.Errorf("Error subscribing to notifications: %s", err.Error())
+		return
+	}
+
+	createdEdit, err := s.createRenameTagEdit(createdTag)
+	if err != nil {
+		return
+	}
+
+	// pending edits are not notified to subscribers
+	s.verifyNotificationTypes(nil, s.getNotifications(watcher, true))
+
+	if _, err := s.applyEdit(createdEdit.ID.String()); err != nil {
+		return
+	}
+
+	notifications := s.getNotifications(watcher, true)
+	s.verifyNotificationTypes([]models.NotificationEnum{
+		models.NotificationEnumWatchedEdit,
+	}, notifications)
+	if len(notifications) == 1 && notifications[0].EditID != createdEdit.ID {
+		s.fieldMismatch(createdEdit.ID, notifications[0].EditID, "EditID")
+	}
+
+	input.Subscribed = false
+	if _, err := watcher.resolver.Mutation().SubscribeNotifications(watcher.ctx, input); err != nil {
+		s.t.Errorf("Error unsubscribing from notifications: %s", err.Error())
+		return
+	}
+
+	me, _ := watcher.resolver.Query().Me(watcher.ctx)
+	subscriptions, _ := watcher.resolver.User().NotificationSubscriptions(watcher.ctx, me)
+	if len(subscriptions) != 0 {
+		s.fieldMismatch(0, len(subscriptions), "Subscriptions")
+	}
+}
+
+func (s *notificationTestRunner) testNotificationsHiddenFromOtherUsers() {
+	other := s.createUserRunner([]models.RoleEnum{models.RoleEnumRead})
+	otherUser, _ := other.resolver.Query().Me(other.ctx)
+
+	notifications, err := s.resolver.User().Notifications(s.ctx, otherUser, nil)
+	if err != nil {
+		s.t.Errorf("Error finding notifications: %s", err.Error())
+		return
+	}
+	if notifications != nil {
+		s.t.Error("Expected notifications of other users to be hidden")
+	}
+}
+
+func TestEditNotifications(t *testing.T) {
+	pt := createNotificationTestRunner(t)
+	pt.testEditNotifications()
+}
+
+func TestWatchedEditNotification(t *testing.T) {
+	pt := createNotificationTestRunner(t)
+	pt.testWatchedEditNotification()
+}
+
+func TestNotificationsHiddenFromOtherUsers(t *testing.T) {
+	pt := createNotificationTestRunner(t)
+	pt.testNotificationsHiddenFromOtherUsers()
+}
@@ -0,0 +1,923 @@
+// +build integration
+
+package api_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+type performerEditTestRunner struct {
+	testRunner
+}
+
+func createPerformerEditTestRunner(t *testing.T) *performerEditTestRunner {
+	return &performerEditTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *performerEditTestRunner) testCreatePerformerEdit() {
+	name := "Name"
+	disambiguation := "Disambiguation"
+	gender := models.GenderEnumFemale
+	height := 170
+	performerEditDetailsInput := models.PerformerEditDetailsInput{
+		Name:           &name,
+		Disambiguation: &disambiguation,
+		Aliases:        []string{"Alias1"},
+		Gender:         &gender,
+		Height:         &height,
+		Urls: []*models.URLInput{
+			{
+				URL:  "http://example.org",
+				Type: "HOME",
+			},
+		},
+		Tattoos: []*models.BodyModificationInput{
+			{
+				Location: "Arm",
+			},
+		},
+	}
+	edit, err := s.createTestPerformerEdit(models.OperationEnumCreate, &performerEditDetailsInput, nil)
+	if err == nil {
+		s.verifyCreatedPerformerEdit(performerEditDetailsInput, edit)
+	}
+}
+
+func (s *performerEditTestRunner) verifyCreatedPerformerEdit(input models.PerformerEditDetailsInput, edit *models.Edit) {
+	r := s.resolver.Edit()
+
+	id, _ := r.ID(s.ctx, edit)
+	if id == "" {
+		s.t.Errorf("Expected created edit id to be non-zero")
+	}
+
+	s.verifyEditOperation(models.OperationEnumCreate.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumPerformer.String(), edit)
+	s.verifyEditApplication(false, edit)
+
+	performerDetails := s.getEditPerformerDetails(edit)
+
+	// ensure basic attributes are set correctly
+	if *input.Name != *performerDetails.Name {
+		s.fieldMismatch(input.Name, performerDetails.Name, "Name")
+	}
+
+	if *input.Disambiguation != *performerDetails.Disambiguation {
+		s.fieldMismatch(input.Disambiguation, performerDetails.Disambiguation, "Disambiguation")
+	}
+
+	if input.Gender.String() != *performerDetails.Gender {
+		s.fieldMismatch(input.Gender.String(), performerDetails.Gender, "Gender")
+	}
+
+	if *input.Height != *performerDetails.Height {
+		s.fieldMismatch(input.Height, performerDetails.Height, "Height")
+	}
+
+	if !reflect.DeepEqual(input.Aliases, performerDetails.AddedAliases) {
+		s.fieldMismatch(input.Aliases, performerDetails.AddedAliases, "Aliases")
+	}
+
+	if !compareUrls(input.Urls, performerDetails.AddedUrls) {
+		s.fieldMismatch(input.Urls, performerDetails.AddedUrls, "Urls")
+	}
+
+	if len(performerDetails.AddedTattoos) != 1 || performerDetails.AddedTattoos[0].Location != input.Tattoos[0].Location {
+		s.fieldMismatch(input.Tattoos, performerDetails.AddedTattoos, "Tattoos")
+	}
+}
+
+func (s *performerEditTestRunner) testModifyPerformerEdit() {
+	existingName := "performerName"
+	existingAlias := "performerAlias"
+	performerCreateInput := models.PerformerCreateInput{
+		Name:    existingName,
+		Aliases: []string{existingAlias},
+		Urls: []*models.URLInput{
+			{
+				URL:  "http://example.org/old",
+				Type: "HOME",
+			},
+		},
+	}
+	createdPerformer, err := s.createTestPerformer(&performerCreateInput)
+	if err != nil {
+		return
+	}
+
+	newDisambiguation := "newDisambiguation"
+	newAlias := "newPerformerAlias"
+	newName := "newPerformerName"
+	performerEditDetailsInput := models.PerformerEditDetailsInput{
+		Name:           &newName,
+		Disambiguation: &newDisambiguation,
+		Aliases:        []string{newAlias},
+		Urls: []*models.URLInput{
+			{
+				URL:  "http://example.org/new",
+				Type: "HOME",
+			},
+		},
+	}
+	id := createdPerformer.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	createdUpdateEdit, err := s.createTestPerformerEdit(models.OperationEnumModify, &performerEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+
+	s.verifyUpdatedPerformerEdit(createdPerformer, performerEditDetailsInput, createdUpdateEdit)
+}
+
+func (s *performerEditTestRunner) verifyUpdatedPerformerEdit(originalPerformer *models.Performer, input models.PerformerEditDetailsInput, edit *models.Edit) {
+	performerDetails := s.getEditPerformerDetails(edit)
+
+	s.verifyEditOperation(models.OperationEnumModify.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumPerformer.String(), edit)
+	s.verifyEditApplication(false, edit)
+
+	// ensure basic attributes are set correctly
+	if *input.Name != *performerDetails.Name {
+		s.fieldMismatch(*input.Name, *performerDetails.Name, "Name")
+	}
+
+	if *input.Disambiguation != *performerDetails.Disambiguation {
+		s.fieldMismatch(input.Disambiguation, performerDetails.Disambiguation, "Disambiguation")
+	}
+
+	performerAliases, _ := s.resolver.Performer().Aliases(s.ctx, originalPerformer)
+	if !reflect.DeepEqual(performerAliases, performerDetails.RemovedAliases) {
+		s.fieldMismatch(performerAliases, performerDetails.RemovedAliases, "RemovedAliases")
+	}
+
+	if !reflect.DeepEqual(input.Aliases, performerDetails.AddedAliases) {
+		s.fieldMismatch(input.Aliases, performerDetails.AddedAliases, "AddedAliases")
+	}
+
+	if !compareUrls(input.Urls, performerDetails.AddedUrls) {
+		s.fieldMismatch(input.Urls, performerDetails.AddedUrls, "AddedUrls")
+	}
+
+	if len(performerDetails.RemovedUrls) != 1 || performerDetails.RemovedUrls[0].URL != "http://example.org/old" {
+		s.fieldMismatch("http://example.org/old", performerDetails.RemovedUrls, "RemovedUrls")
+	}
+}
+
+func (s *performerEditTestRunner) testDestroyPerformerEdit() {
+	createdPerformer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	performerID := createdPerformer.ID.String()
+
+	performerEditDetailsInput := models.PerformerEditDetailsInput{}
+	editInput := models.EditInput{
+		Operation: models.OperationEnumDestroy,
+		ID:        &performerID,
+	}
+	destroyEdit, err := s.createTestPerformerEdit(models.OperationEnumDestroy, &performerEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+
+	s.verifyDestroyPerformerEdit(performerID, destroyEdit)
+}
+
+func (s *performerEditTestRunner) verifyDestroyPerformerEdit(performerID string, edit *models.Edit) {
+	s.verifyEditOperation(models.OperationEnumDestroy.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumPerformer.String(), edit)
+	s.verifyEditApplication(false, edit)
+
+	editTarget := s.getEditPerformerTarget(edit)
+
+	if performerID != editTarget.ID.String() {
+		s.fieldMismatch(performerID, editTarget.ID.String(), "ID")
+	}
+}
+
+func (s *performerEditTestRunner) testMergePerformerEdit() {
+	createdPrimaryPerformer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	createdMergePerformer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	newName := "newPerformerName2"
+	performerEditDetailsInput := models.PerformerEditDetailsInput{
+		Name: &newName,
+	}
+	id := createdPrimaryPerformer.ID.String()
+	mergeSources := []string{createdMergePerformer.ID.String()}
+	editInput := models.EditInput{
+		Operation:      models.OperationEnumMerge,
+		ID:             &id,
+		MergeSourceIds: mergeSources,
+	}
+
+	createdMergeEdit, err := s.createTestPerformerEdit(models.OperationEnumMerge, &performerEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+
+	s.verifyMergePerformerEdit(performerEditDetailsInput, createdMergeEdit, mergeSources)
+}
+
+func (s *performerEditTestRunner) verifyMergePerformerEdit(input models.PerformerEditDetailsInput, edit *models.Edit, inputMergeSources []string) {
+	performerDetails := s.getEditPerformerDetails(edit)
+
+	s.verifyEditOperation(models.OperationEnumMerge.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumPerformer.String(), edit)
+	s.verifyEditApplication(false, edit)
+
+	if *input.Name != *performerDetails.Name {
+		s.fieldMismatch(*input.Name, *performerDetails.Name, "Name")
+	}
+
+	mergeSources := []string{}
+	merges, _ := s.resolver.Edit().MergeSources(s.ctx, edit)
+	for i := range merges {
+		merge := merges[i].(*models.Performer)
+		mergeSources = append(mergeSources, merge.ID.String())
+	}
+	if !reflect.DeepEqual(inputMergeSources, mergeSources) {
+		s.fieldMismatch(inputMergeSources, mergeSources, "MergeSources")
+	}
+}
+
+func (s *performerEditTestRunner) testApplyCreatePerformerEdit() {
+	name := "performerName4"
+	performerEditDetailsInput := models.PerformerEditDetailsInput{
+		Name:    &name,
+		Aliases: []string{"performerAlias4"},
+		Urls: []*models.URLInput{
+			{
+				URL:  "http://example.org/4",
+				Type: "HOME",
+			},
+		},
+	}
+	edit, err := s.createTestPerformerEdit(models.OperationEnumCreate, &performerEditDetailsInput, nil)
+	if err != nil {
+		return
+	}
+
+	appliedEdit, err := s.applyEdit(edit.ID.String())
+	if err == nil {
+		s.verifyAppliedPerformerCreateEdit(performerEditDetailsInput, appliedEdit)
+	}
+}
+
+func (s *performerEditTestRunner) verifyAppliedPerformerCreateEdit(input models.PerformerEditDetailsInput, edit *models.Edit) {
+	s.verifyEditOperation(models.OperationEnumCreate.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumImmediateAccepted.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumPerformer.String(), edit)
+	s.verifyEditApplication(true, edit)
+
+	performer := s.getEditPerformerTarget(edit)
+
+	if *input.Name != performer.Name {
+		s.fieldMismatch(input.Name, performer.Name, "Name")
+	}
+
+	aliases, _ := s.resolver.Performer().Aliases(s.ctx, performer)
+	if !reflect.DeepEqual(input.Aliases, aliases) {
+		s.fieldMismatch(input.Aliases, aliases, "Aliases")
+	}
+
+	urls, _ := s.resolver.Performer().Urls(s.ctx, performer)
+	if !compareUrls(input.Urls, urls) {
+		s.fieldMismatch(input.Urls, urls, "Urls")
+	}
+}
+
+func (s *performerEditTestRunner) testApplyModifyPerformerEdit() {
+	performerCreateInput := models.PerformerCreateInput{
+		Name:    "performerName5",
+		Aliases: []string{"performerAlias5"},
+	}
+	createdPerformer, err := s.createTestPerformer(&performerCreateInput)
+	if err != nil {
+		return
+	}
+
+	newName := "newPerformerName5"
+	newCountry := "newCountry5"
+	performerEditDetailsInput := models.PerformerEditDetailsInput{
+		Name:    &newName,
+		Country: &newCountry,
+		Aliases: []string{"newPerformerAlias5"},
+	}
+	id := createdPerformer.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	createdUpdateEdit, err := s.createTestPerformerEdit(models.OperationEnumModify, &performerEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(createdUpdateEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	modifiedPerformer, _ := s.resolver.Query().FindPerformer(s.ctx, id, nil)
+	s.verifyApplyModifyPerformerEdit(performerEditDetailsInput, modifiedPerformer, appliedEdit)
+}
+
+func (s *performerEditTestRunner) verifyApplyModifyPerformerEdit(input models.PerformerEditDetailsInput, updatedPerformer *models.Performer, edit *models.Edit) {
+	s.verifyEditOperation(models.OperationEnumModify.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumImmediateAccepted.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumPerformer.String(), edit)
+	s.verifyEditApplication(true, edit)
+
+	if *input.Name != updatedPerformer.Name {
+		s.fieldMismatch(*input.Name, updatedPerformer.Name, "Name")
+	}
+
+	country, _ := s.resolver.Performer().Country(s.ctx, updatedPerformer)
+	if *input.Country != *country {
+		s.fieldMismatch(*input.Country, *country, "Country")
+	}
+
+	aliases, _ := s.resolver.Performer().Aliases(s.ctx, updatedPerformer)
+	if !reflect.DeepEqual(input.Aliases, aliases) {
+		s.fieldMismatch(input.Aliases, aliases, "Aliases")
+	}
+}
+
+func (s *performerEditTestRunner) testApplyDestroyPerformerEdit() {
+	createdPerformer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	performerID := createdPerformer.ID.String()
+	sceneInput := models.SceneCreateInput{
+		Performers: []*models.PerformerAppearanceInput{
+			{
+				PerformerID: performerID,
+			},
+		},
+	}
+	scene, err := s.createTestScene(&sceneInput)
+	if err != nil {
+		return
+	}
+
+	editInput := models.EditInput{
+		Operation: models.OperationEnumDestroy,
+		ID:        &performerID,
+	}
+	destroyEdit, err := s.createTestPerformerEdit(models.OperationEnumDestroy, &models.PerformerEditDetailsInput{}, &editInput)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(destroyEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	destroyedPerformer, _ := s.resolver.Query().FindPerformer(s.ctx, performerID, nil)
+	s.verifyApplyDestroyPerformerEdit(destroyedPerformer, appliedEdit, scene)
+}
+
+func (s *performerEditTestRunner) verifyApplyDestroyPerformerEdit(destroyedPerformer *models.Performer, edit *models.Edit, scene *models.Scene) {
+	s.verifyEditOperation(models.OperationEnumDestroy.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumImmediateAccepted.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumPerformer.String(), edit)
+	s.verifyEditApplication(true, edit)
+
+	if destroyedPerformer.Deleted != true {
+		s.fieldMismatch(destroyedPerformer.Deleted, true, "Deleted")
+	}
+
+	scenePerformers, _ := s.resolver.Scene().Performers(s.ctx, scene)
+	if len(scenePerformers) > 0 {
+		s.fieldMismatch(len(scenePerformers), 0, "Scene performer count")
+	}
+}
+
+func (s *performerEditTestRunner) testApplyMergePerformerEdit() {
+	mergeSource1, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+	mergeSource2, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+	mergeTarget, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	// Scene with performer from both source and target, should not cause db unique error
+	sceneInput := models.SceneCreateInput{
+		Performers: []*models.PerformerAppearanceInput{
+			{PerformerID: mergeSource2.ID.String()},
+			{PerformerID: mergeTarget.ID.String()},
+		},
+	}
+	scene1, err := s.createTestScene(&sceneInput)
+	if err != nil {
+		return
+	}
+
+	sceneInput = models.SceneCreateInput{
+		Performers: []*models.PerformerAppearanceInput{
+			{PerformerID: mergeSource1.ID.String()},
+			{PerformerID: mergeSource2.ID.String()},
+		},
+	}
+	scene2, err := s.createTestScene(&sceneInput)
+	if err != nil {
+		return
+	}
+
+	newName := "newPerformerName6"
+	performerEditDetailsInput := models.PerformerEditDetailsInput{
+		Name: &newName,
+	}
+	id := mergeTarget.ID.String()
+	mergeSources := []string{mergeSource1.ID.String(), mergeSource2.ID.String()}
+	editInput := models.EditInput{
+		Operation:      models.OperationEnumMerge,
+		ID:             &id,
+		MergeSourceIds: mergeSources,
+	}
+
+	mergeEdit, err := s.createTestPerformerEdit(models.OperationEnumMerge, &performerEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+
+	appliedMerge, err := s.applyEdit(mergeEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	s.verifyAppliedMergePerformerEdit(appliedMerge, scene1, scene2)
+}
+
+func (s *performerEditTestRunner) verifyAppliedMergePerformerEdit(edit *models.Edit, scene1 *models.Scene, scene2 *models.Scene) {
+	s.verifyEditOperation(models.OperationEnumMerge.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumImmediateAccepted.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumPerformer.String(), edit)
+	s.verifyEditApplication(true, edit)
+
+	merges, _ := s.resolver.Edit().MergeSources(s.ctx, edit)
+	for i := range merges {
+		performer := merges[i].(*models.Performer)
+		if performer.Deleted != true {
+			s.fieldMismatch(performer.Deleted, true, "Deleted")
+		}
+	}
+
+	editTarget := s.getEditPerformerTarget(edit)
+	scene1Performers, _ := s.resolver.Scene().Performers(s.ctx, scene1)
+	if len(scene1Performers) != 1 {
+		s.fieldMismatch(len(scene1Performers), 1, "Scene 1 performer count")
+	} else if scene1Performers[0].Performer.ID != editTarget.ID {
+		s.fieldMismatch(scene1Performers[0].Performer.ID, editTarget.ID, "Scene 1 performer ID")
+	}
+
+	scene2Performers, _ := s.resolver.Scene().Performers(s.ctx, scene2)
+	if len(scene2Performers) != 1 {
+		s.fieldMismatch(len(scene2Performers), 1, "Scene 2 performer count")
+	} else if scene2Performers[0].Performer.ID != editTarget.ID {
+		s.fieldMismatch(scene2Performers[0].Performer.ID, editTarget.ID, "Scene 2 performer ID")
+	}
+}
+
+func (s *performerEditTestRunner) testConflictingPerformerEdits() {
+	createdPerformer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	id := createdPerformer.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	firstHeight := 170
+	firstEdit, err := s.createTestPerformerEdit(models.OperationEnumModify, &models.PerformerEditDetailsInput{
+		Height: &firstHeight,
+	}, &editInput)
+	if err != nil {
+		return
+	}
+
+	secondHeight := 180
+	secondEdit, err := s.createTestPerformerEdit(models.OperationEnumModify, &models.PerformerEditDetailsInput{
+		Height: &secondHeight,
+	}, &editInput)
+	if err != nil {
+		return
+	}
+
+	conflicts, _ := s.resolver.Edit().Conflicts(s.ctx, secondEdit)
+	if len(conflicts) != 0 {
+		s.fieldMismatch(0, len(conflicts), "Conflicts")
+	}
+
+	if _, err := s.applyEdit(firstEdit.ID.String()); err != nil {
+		return
+	}
+
+	// the field conflict is followed by the stale target conflict
+	conflicts, _ = s.resolver.Edit().Conflicts(s.ctx, secondEdit)
+	if len(conflicts) != 2 || conflicts[0].Field != "height" || conflicts[1].Field != "updated_at" {
+		s.fieldMismatch("height, updated_at", conflicts, "Conflicts")
+	}
+
+	input := models.ApplyEditInput{
+		ID: secondEdit.ID.String(),
+	}
+	if _, err := s.resolver.Mutation().ApplyEdit(s.ctx, input); err == nil {
+		s.t.Error("Expected error applying conflicting edit")
+	}
+}
+
+func (s *performerEditTestRunner) testStalePerformerEdit() {
+	createdPerformer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	id := createdPerformer.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	height := 170
+	staleEdit, err := s.createTestPerformerEdit(models.OperationEnumModify, &models.PerformerEditDetailsInput{
+		Height: &height,
+	}, &editInput)
+	if err != nil {
+		return
+	}
+
+	// change a different field of the target after the edit was submitted
+	country := "country"
+	otherEdit, err := s.createTestPerformerEdit(models.OperationEnumModify, &models.PerformerEditDetailsInput{
+		Country: &country,
+	}, &editInput)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(otherEdit.ID.String()); err != nil {
+		return
+	}
+
+	conflicts, _ := s.resolver.Edit().Conflicts(s.ctx, staleEdit)
+	if len(conflicts) != 1 || conflicts[0].Field != "updated_at" {
+		s.fieldMismatch("updated_at", conflicts, "Conflicts")
+	}
+
+	input := models.ApplyEditInput{
+		ID: staleEdit.ID.String(),
+	}
+	if _, err := s.resolver.Mutation().ApplyEdit(s.ctx, input); err == nil {
+		s.t.Error("Expected error applying edit to a changed target")
+	}
+
+	performer, _ := s.resolver.Query().FindPerformer(s.ctx, id, nil)
+	if performer.Height.Valid {
+		s.fieldMismatch(nil, performer.Height.Int64, "Height")
+	}
+}
+
+func (s *performerEditTestRunner) testFindPerformerAsOf() {
+	performerCreateInput := models.PerformerCreateInput{
+		Name: "performerName8",
+	}
+	createdPerformer, err := s.createTestPerformer(&performerCreateInput)
+	if err != nil {
+		return
+	}
+
+	newName := "newPerformerName8"
+	newCountry := "newCountry8"
+	performerEditDetailsInput := models.PerformerEditDetailsInput{
+		Name:    &newName,
+		Country: &newCountry,
+	}
+	id := createdPerformer.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	createdUpdateEdit, err := s.createTestPerformerEdit(models.OperationEnumModify, &performerEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(createdUpdateEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	edits, _ := s.resolver.Performer().Edits(s.ctx, createdPerformer)
+	if len(edits) != 1 || edits[0].ID != appliedEdit.ID {
+		s.t.Errorf("Performer edits do not contain the applied edit")
+	}
+
+	asOf := createdPerformer.CreatedAt.Timestamp
+	pastPerformer, err := s.resolver.Query().FindPerformer(s.ctx, id, &asOf)
+	if err != nil {
+		s.t.Errorf("Error finding performer as of %s: %s", asOf, err.Error())
+		return
+	}
+	if pastPerformer == nil {
+		s.t.Errorf("Performer not found as of %s", asOf)
+		return
+	}
+
+	if pastPerformer.Name != performerCreateInput.Name {
+		s.fieldMismatch(performerCreateInput.Name, pastPerformer.Name, "Name")
+	}
+	if pastPerformer.Country.Valid {
+		s.fieldMismatch(nil, pastPerformer.Country.String, "Country")
+	}
+
+	beforeCreation := asOf.Add(-time.Hour)
+	pastPerformer, _ = s.resolver.Query().FindPerformer(s.ctx, id, &beforeCreation)
+	if pastPerformer != nil {
+		s.t.Errorf("Performer found before its creation")
+	}
+}
+
+func (s *performerEditTestRunner) findPerformerDeletedAsOf(id string, asOf time.Time) bool {
+	s.t.Helper()
+	performer, err := s.resolver.Query().FindPerformer(s.ctx, id, &asOf)
+	if err != nil {
+		s.t.Errorf("Error finding performer as of %s: %s", asOf, err.Error())
+		return false
+	}
+	if performer == nil {
+		s.t.Errorf("Performer not found as of %s", asOf)
+		return false
+	}
+	return performer.Deleted
+}
+
+func (s *performerEditTestRunner) testFindMergedPerformerAsOf() {
+	source, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+	target, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	id := target.ID.String()
+	sourceID := source.ID.String()
+	editInput := models.EditInput{
+		Operation:      models.OperationEnumMerge,
+		ID:             &id,
+		MergeSourceIds: []string{sourceID},
+	}
+	mergeEdit, err := s.createTestPerformerEdit(models.OperationEnumMerge, &models.PerformerEditDetailsInput{
+		Name: &target.Name,
+	}, &editInput)
+	if err != nil {
+		return
+	}
+
+	beforeMerge := time.Now()
+	if _, err := s.applyEdit(mergeEdit.ID.String()); err != nil {
+		return
+	}
+	afterMerge := time.Now()
+
+	// the merge source existed before the merge
+	if s.findPerformerDeletedAsOf(sourceID, beforeMerge) {
+		s.fieldMismatch(false, true, "Deleted before merge")
+	}
+
+	if _, err := s.revertEdit(mergeEdit.ID.String()); err != nil {
+		return
+	}
+
+	// and was deleted until the merge was reverted
+	if !s.findPerformerDeletedAsOf(sourceID, afterMerge) {
+		s.fieldMismatch(true, false, "Deleted after merge")
+	}
+	if s.findPerformerDeletedAsOf(sourceID, time.Now()) {
+		s.fieldMismatch(false, true, "Deleted after revert")
+	}
+}
+
+func (s *performerEditTestRunner) testRevertPerformerClearedField() {
+	createdPerformer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	country := "US"
+	id := createdPerformer.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+	modifyEdit, err := s.createTestPerformerEdit(models.OperationEnumModify, &models.PerformerEditDetailsInput{
+		Country: &country,
+	}, &editInput)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(modifyEdit.ID.String()); err != nil {
+		return
+	}
+
+	revertEdit, err := s.revertEdit(modifyEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	// the revert records clearing the country set by the edit
+	details := s.getEditPerformerDetails(revertEdit)
+	if details.Country != nil || !reflect.DeepEqual(details.ClearedFields, []string{"country"}) {
+		s.fieldMismatch([]string{"country"}, details.ClearedFields, "ClearedFields")
+	}
+
+	performer, err := s.resolver.Query().FindPerformer(s.ctx, id, nil)
+	if err != nil {
+		s.t.Errorf("Error finding performer: %s", err.Error())
+		return
+	}
+	if performer.Country.Valid {
+		s.fieldMismatch(nil, performer.Country.String, "Country")
+	}
+}
+
+func (s *performerEditTestRunner) testFindRevertedPerformerAsOf() {
+	createdPerformer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	birthdate := "1990-01-01"
+	id := createdPerformer.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+	modifyEdit, err := s.createTestPerformerEdit(models.OperationEnumModify, &models.PerformerEditDetailsInput{
+		Birthdate: &models.FuzzyDateInput{
+			Date:     birthdate,
+			Accuracy: models.DateAccuracyEnumDay,
+		},
+	}, &editInput)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(modifyEdit.ID.String()); err != nil {
+		return
+	}
+	afterEdit := time.Now()
+
+	if _, err := s.revertEdit(modifyEdit.ID.String()); err != nil {
+		return
+	}
+
+	// the birthdate was set between the edit and its revert
+	pastPerformer, err := s.resolver.Query().FindPerformer(s.ctx, id, &afterEdit)
+	if err != nil {
+		s.t.Errorf("Error finding performer as of %s: %s", afterEdit, err.Error())
+		return
+	}
+	if pastPerformer == nil {
+		s.t.Errorf("Performer not found as of %s", afterEdit)
+		return
+	}
+	if !pastPerformer.Birthdate.Valid || pastPerformer.Birthdate.String != birthdate {
+		s.fieldMismatch(birthdate, pastPerformer.Birthdate, "Birthdate")
+	}
+
+	// and is null before the edit
+	beforeEdit := createdPerformer.CreatedAt.Timestamp
+	pastPerformer, err = s.resolver.Query().FindPerformer(s.ctx, id, &beforeEdit)
+	if err != nil {
+		s.t.Errorf("Error finding performer as of %s: %s", beforeEdit, err.Error())
+		return
+	}
+	if pastPerformer != nil && pastPerformer.Birthdate.Valid {
+		s.fieldMismatch(nil, pastPerformer.Birthdate.String, "Birthdate")
+	}
+}
+
+func (s *performerEditTestRunner) testPerformerEditWithoutID() {
+	for _, operation := range []models.OperationEnum{models.OperationEnumModify, models.OperationEnumDestroy} {
+		input := models.PerformerEditInput{
+			Edit: &models.EditInput{
+				Operation: operation,
+			},
+			Details: &models.PerformerEditDetailsInput{},
+		}
+
+		if _, err := s.resolver.Mutation().PerformerEdit(s.ctx, input); err == nil {
+			s.t.Errorf("Expected error for %s edit without id", operation)
+		}
+	}
+}
+
+func TestStalePerformerEdit(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testStalePerformerEdit()
+}
+
+func TestCreatePerformerEdit(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testCreatePerformerEdit()
+}
+
+func TestModifyPerformerEdit(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testModifyPerformerEdit()
+}
+
+func TestDestroyPerformerEdit(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testDestroyPerformerEdit()
+}
+
+func TestMergePerformerEdit(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testMergePerformerEdit()
+}
+
+func TestApplyCreatePerformerEdit(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testApplyCreatePerformerEdit()
+}
+
+func TestApplyModifyPerformerEdit(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testApplyModifyPerformerEdit()
+}
+
+func TestApplyDestroyPerformerEdit(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testApplyDestroyPerformerEdit()
+}
+
+func TestApplyMergePerformerEdit(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testApplyMergePerformerEdit()
+}
+
+func TestConflictingPerformerEdits(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testConflictingPerformerEdits()
+}
+
+func TestFindPerformerAsOf(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testFindPerformerAsOf()
+}
+
+func TestFindMergedPerformerAsOf(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testFindMergedPerformerAsOf()
+}
+
+func TestRevertPerformerClearedField(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testRevertPerformerClearedField()
+}
+
+func TestFindRevertedPerformerAsOf(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testFindRevertedPerformerAsOf()
+}
+
+func TestPerformerEditWithoutID(t *testing.T) {
+	pt := createPerformerEditTestRunner(t)
+	pt.testPerformerEditWithoutID()
+}
@@ -4,6 +4,7 @@ package api_test
 
 import (
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/stashapp/stashdb/pkg/api"
@@ -205,7 +206,7 @@ func (s *performerTestRunner) testFindPerformer() {
 		return
 	}
 
-	performer, err := s.resolver.Query().FindPerformer(s.ctx, createdPerformer.ID.String())
+	performer, err := s.resolver.Query().FindPerformer(s.ctx, createdPerformer.ID.String(), nil)
 	if err != nil {
 		s.t.Errorf("Error finding performer: %s", err.Error())
 		return
@@ -384,7 +385,7 @@ func (s *performerTestRunner) testDestroyPerformer() {
 	}
 
 	// ensure cannot find performer
-	foundPerformer, err := s.resolver.Query().FindPerformer(s.ctx, performerID)
+	foundPerformer, err := s.resolver.Query().FindPerformer(s.ctx, performerID, nil)
 	if err != nil {
 		s.t.Errorf("Error finding performer after destroying: %s", err.Error())
 		return
@@ -397,6 +398,120 @@ func (s *performerTestRunner) testDestroyPerformer() {
 	// TODO - ensure scene was not removed
 }
 
+func (s *performerTestRunner) testQueryPerformersFacets() {
+	prefix := "testQueryPerformersFacets_"
+	performers := []struct {
+		gender    models.GenderEnum
+		ethnicity models.EthnicityEnum
+		country   string
+	}{
+		{models.GenderEnumFemale, models.EthnicityEnumCaucasian, "US"},
+		{models.GenderEnumFemale, models.EthnicityEnumAsian, "JP"},
+		{models.GenderEnumMale, models.EthnicityEnumCaucasian, "US"},
+	}
+
+	for i, performer := range performers {
+		gender := performer.gender
+		ethnicity := performer.ethnicity
+		country := performer.country
+		input := models.PerformerCreateInput{
+			Name:      prefix + strconv.Itoa(i),
+			Gender:    &gender,
+			Ethnicity: &ethnicity,
+			Country:   &country,
+		}
+
+		if _, err := s.createTestPerformer(&input); err != nil {
+			return
+		}
+	}
+
+	name := prefix
+	filter := models.PerformerFilterType{
+		Name: &name,
+	}
+
+	s.verifyPerformerFacets(filter, map[models.GenderEnum]int{
+		models.GenderEnumFemale: 2,
+		models.GenderEnumMale:   1,
+	}, map[models.EthnicityEnum]int{
+		models.EthnicityEnumCaucasian: 2,
+		models.EthnicityEnumAsian:     1,
+	}, map[string]int{
+		"US": 2,
+		"JP": 1,
+	})
+
+	// facets are counted from the filtered performers only
+	filter.Country = &models.StringCriterionInput{
+		Value:    "US",
+		Modifier: models.CriterionModifierEquals,
+	}
+
+	s.verifyPerformerFacets(filter, map[models.GenderEnum]int{
+		models.GenderEnumFemale: 1,
+		models.GenderEnumMale:   1,
+	}, map[models.EthnicityEnum]int{
+		models.EthnicityEnumCaucasian: 2,
+	}, map[string]int{
+		"US": 2,
+	})
+}
+
+func (s *performerTestRunner) verifyPerformerFacets(filter models.PerformerFilterType, genders map[models.GenderEnum]int, ethnicities map[models.EthnicityEnum]int, countries map[string]int) {
+	s.t.Helper()
+
+	results, err := s.resolver.Query().QueryPerformers(s.ctx, &filter, nil)
+	if err != nil {
+		s.t.Errorf("Error querying performers: %s", err.Error())
+		return
+	}
+
+	r := s.resolver.PerformerFacets()
+
+	genderFacets, err := r.Genders(s.ctx, results.Facets)
+	if err != nil {
+		s.t.Errorf("Error getting gender facets: %s", err.Error())
+		return
+	}
+
+	gotGenders := make(map[models.GenderEnum]int)
+	for _, facet := range genderFacets {
+		gotGenders[facet.Gender] = facet.Count
+	}
+	if !reflect.DeepEqual(gotGenders, genders) {
+		s.t.Errorf("Gender facets: got %v want %v", gotGenders, genders)
+	}
+
+	ethnicityFacets, err := r.Ethnicities(s.ctx, results.Facets)
+	if err != nil {
+		s.t.Errorf("Error getting ethnicity facets: %s", err.Error())
+		return
+	}
+
+	gotEthnicities := make(map[models.EthnicityEnum]int)
+	for _, facet := range ethnicityFacets {
+		gotEthnicities[facet.Ethnicity] = facet.Count
+	}
+	if !reflect.DeepEqual(gotEthnicities, ethnicities) {
+		s.t.Errorf("Ethnicity facets: got %v want %v", gotEthnicities, ethnicities)
+	}
+
+	countryFacets, err := r.Countries(s.ctx, results.Facets, nil)
+	if err != nil {
+		s.t.Errorf("Error getting country facets: %s", err.Error())
+		return
+	}
+
+	gotCountries := make(map[string]int)
+	for _, facet := range countryFacets {
+		gotCountries[facet.Country] = facet.Count
+	}
+	if !reflect.DeepEqual(gotCountries, countries) {
+		s.t.Errorf("Country facets: got %v want %v", gotCountries, countries)
+	}
+}
+
 func (s *performerTestRunner) testUnauthorisedPerformerModify() {
 	// test each api interface - all require modify so all should fail
 	_, err := s.resolver.Mutation().PerformerCreate(s.ctx, models.PerformerCreateInput{})
@@ -417,7 +532,7 @@ func (s *performerTestRunner) testUnauthorisedPerformerModify() {
 
 func (s *performerTestRunner) testUnauthorisedPerformerQuery() {
 	// test each api interface - all require read so all should fail
-	_, err := s.resolver.Query().FindPerformer(s.ctx, "")
+	_, err := s.resolver.Query().FindPerformer(s.ctx, "", nil)
 	if err != api.ErrUnauthorized {
 		s.t.Errorf("FindPerformer: got %v want %v", err, api.ErrUnauthorized)
 	}
@@ -451,6 +566,11 @@ func TestDestroyPerformer(t *testing.T) {
 	pt.testDestroyPerformer()
 }
 
+func TestQueryPerformersFacets(t *testing.T) {
+	pt := createPerformerTestRunner(t)
+	pt.testQueryPerformersFacets()
+}
+
 func TestUnauthorisedPerformerModify(t *testing.T) {
 	pt := &performerTestRunner{
 		testRunner: *asRead(t),
@@ -20,9 +20,24 @@ func (r *Resolver) Edit() models.EditResolver {
 func (r *Resolver) EditComment() models.EditCommentResolver {
 	return &editCommentResolver{r}
 }
+func (r *Resolver) EditRevision() models.EditRevisionResolver {
+	return &editRevisionResolver{r}
+}
+func (r *Resolver) EditGroup() models.EditGroupResolver {
+	return &editGroupResolver{r}
+}
+func (r *Resolver) Notification() models.NotificationResolver {
+	return &notificationResolver{r}
+}
+func (r *Resolver) NotificationSubscription() models.NotificationSubscriptionResolver {
+	return &notificationSubscriptionResolver{r}
+}
 func (r *Resolver) Performer() models.PerformerResolver {
 	return &performerResolver{r}
 }
+func (r *Resolver) PerformerEdit() models.PerformerEditResolver {
+	return &performerEditResolver{r}
+}
 func (r *Resolver) Tag() models.TagResolver {
 	return &tagResolver{r}
 }
@@ -32,9 +47,33 @@ func (r *Resolver) Image() models.ImageResolver {
 func (r *Resolver) Studio() models.StudioResolver {
 	return &studioResolver{r}
 }
+func (r *Resolver) StudioEdit() models.StudioEditResolver {
+	return &studioEditResolver{r}
+}
 func (r *Resolver) Scene() models.SceneResolver {
 	return &sceneResolver{r}
 }
+func (r *Resolver) SceneEdit() models.SceneEditResolver {
+	return &sceneEditResolver{r}
+}
+func (r *Resolver) ReportedFingerprint() models.ReportedFingerprintResolver {
+	return &reportedFingerprintResolver{r}
+}
+func (r *Resolver) SceneFacets() models.SceneFacetsResolver {
+	return &sceneFacetsResolver{r}
+}
+func (r *Resolver) StudioFacet() models.StudioFacetResolver {
+	return &studioFacetResolver{r}
+}
+func (r *Resolver) TagFacet() models.TagFacetResolver {
+	return &tagFacetResolver{r}
+}
+func (r *Resolver) PerformerFacets() models.PerformerFacetsResolver {
+	return &performerFacetsResolver{r}
+}
+func (r *Resolver) FingerprintReport() models.FingerprintReportResolver {
+	return &fingerprintReportResolver{r}
+}
 func (r *Resolver) User() models.UserResolver {
 	return &userResolver{r}
 }
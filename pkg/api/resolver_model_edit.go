@@ -3,9 +3,11 @@ package api
 import (
 	"context"
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/stashapp/stashdb/pkg/manager/edit"
 	"github.com/stashapp/stashdb/pkg/models"
 )
 
@@ -63,6 +65,72 @@ func (r *editResolver) Target(ctx context.Context, obj *models.Edit) (models.Edi
 			target.CopyFromTagEdit(*data.Old)
 		}
 
+		return target, nil
+	} else if targetType == "PERFORMER" {
+		eqb := models.NewEditQueryBuilder(nil)
+		performerID, err := eqb.FindPerformerID(obj.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		pqb := models.NewPerformerQueryBuilder(nil)
+		target, err := pqb.Find(*performerID)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := obj.GetPerformerData()
+		if err != nil {
+			return nil, err
+		}
+		if data.Old != nil {
+			target.CopyFromPerformerEdit(*data.Old)
+		}
+
+		return target, nil
+	} else if targetType == "SCENE" {
+		eqb := models.NewEditQueryBuilder(nil)
+		sceneID, err := eqb.FindSceneID(obj.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		sqb := models.NewSceneQueryBuilder(nil)
+		target, err := sqb.Find(*sceneID)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := obj.GetSceneData()
+		if err != nil {
+			return nil, err
+		}
+		if data.Old != nil {
+			target.CopyFromSceneEdit(*data.Old)
+		}
+
+		return target, nil
+	} else if targetType == "STUDIO" {
+		eqb := models.NewEditQueryBuilder(nil)
+		studioID, err := eqb.FindStudioID(obj.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		sqb := models.NewStudioQueryBuilder(nil)
+		target, err := sqb.Find(*studioID)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := obj.GetStudioData()
+		if err != nil {
+			return nil, err
+		}
+		if data.Old != nil {
+			target.CopyFromStudioEdit(*data.Old)
+		}
+
 		return target, nil
 	} else {
 		return nil, errors.New("not implemented")
@@ -97,6 +165,33 @@ func (r *editResolver) MergeSources(ctx context.Context, obj *models.Edit) ([]mo
 					mergeSources = append(mergeSources, tag)
 				}
 			}
+		} else if ret == "PERFORMER" {
+			pqb := models.NewPerformerQueryBuilder(nil)
+			for _, performerStringID := range editData.MergeSources {
+				performerID, _ := uuid.FromString(performerStringID)
+				performer, err := pqb.Find(performerID)
+				if err == nil {
+					mergeSources = append(mergeSources, performer)
+				}
+			}
+		} else if ret == "SCENE" {
+			sqb := models.NewSceneQueryBuilder(nil)
+			for _, sceneStringID := range editData.MergeSources {
+				sceneID, _ := uuid.FromString(sceneStringID)
+				scene, err := sqb.Find(sceneID)
+				if err == nil {
+					mergeSources = append(mergeSources, scene)
+				}
+			}
+		} else if ret == "STUDIO" {
+			sqb := models.NewStudioQueryBuilder(nil)
+			for _, studioStringID := range editData.MergeSources {
+				studioID, _ := uuid.FromString(studioStringID)
+				studio, err := sqb.Find(studioID)
+				if err == nil {
+					mergeSources = append(mergeSources, studio)
+				}
+			}
 		} else {
 			return nil, errors.New("not implemented")
 		}
@@ -114,6 +209,10 @@ func (r *editResolver) Operation(ctx context.Context, obj *models.Edit) (models.
 }
 
 func (r *editResolver) Details(ctx context.Context, obj *models.Edit) (models.EditDetails, error) {
+	return resolveEditDetails(obj)
+}
+
+func resolveEditDetails(obj *models.Edit) (models.EditDetails, error) {
 	var ret models.EditDetails
 	var targetType models.TargetTypeEnum
 	resolveEnumString(obj.TargetType, &targetType)
@@ -123,6 +222,24 @@ func (r *editResolver) Details(ctx context.Context, obj *models.Edit) (models.Ed
 			return nil, err
 		}
 		ret = tagData.New
+	} else if targetType == "PERFORMER" {
+		performerData, err := obj.GetPerformerData()
+		if err != nil {
+			return nil, err
+		}
+		ret = performerData.New
+	} else if targetType == "SCENE" {
+		sceneData, err := obj.GetSceneData()
+		if err != nil {
+			return nil, err
+		}
+		ret = sceneData.New
+	} else if targetType == "STUDIO" {
+		studioData, err := obj.GetStudioData()
+		if err != nil {
+			return nil, err
+		}
+		ret = studioData.New
 	}
 
 	return ret, nil
@@ -141,12 +258,90 @@ func (r *editResolver) Comments(ctx context.Context, obj *models.Edit) ([]*model
 		ret = append(ret, comment)
 	}
 
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].CreatedAt.Timestamp.Before(ret[j].CreatedAt.Timestamp)
+	})
+
 	return ret, nil
 }
 
 func (r *editResolver) Votes(ctx context.Context, obj *models.Edit) ([]*models.VoteComment, error) {
-	// TODO
-	return nil, nil
+	qb := models.NewEditQueryBuilder(nil)
+	votes, err := qb.GetVotes(obj.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	uqb := models.NewUserQueryBuilder(nil)
+	var ret []*models.VoteComment
+	for _, vote := range votes {
+		user, err := uqb.Find(vote.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		var voteType models.VoteTypeEnum
+		resolveEnumString(vote.Vote, &voteType)
+		date := vote.CreatedAt.Timestamp.Format(time.RFC3339)
+
+		ret = append(ret, &models.VoteComment{
+			User:     user,
+			Date:     &date,
+			Comment:  resolveNullString(vote.Comment),
+			Type:     &voteType,
+			Outdated: vote.Outdated,
+		})
+	}
+
+	return ret, nil
+}
+
+func (r *editResolver) Revisions(ctx context.Context, obj *models.Edit) ([]*models.EditRevision, error) {
+	qb := models.NewEditQueryBuilder(nil)
+	revisions, err := qb.GetRevisions(obj.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*models.EditRevision
+	for _, revision := range revisions {
+		ret = append(ret, revision)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].CreatedAt.Timestamp.Before(ret[j].CreatedAt.Timestamp)
+	})
+
+	return ret, nil
+}
+
+func (r *editResolver) Conflicts(ctx context.Context, obj *models.Edit) ([]*models.EditConflict, error) {
+	return edit.FindConflicts(nil, obj)
+}
+
+func (r *editResolver) Reverts(ctx context.Context, obj *models.Edit) (*models.Edit, error) {
+	if !obj.RevertsID.Valid {
+		return nil, nil
+	}
+
+	qb := models.NewEditQueryBuilder(nil)
+	return qb.Find(obj.RevertsID.UUID)
+}
+
+func (r *editResolver) RevertedBy(ctx context.Context, obj *models.Edit) (*models.Edit, error) {
+	qb := models.NewEditQueryBuilder(nil)
+	return qb.FindRevertOf(obj.ID)
+}
+
+func (r *editResolver) Group(ctx context.Context, obj *models.Edit) (*models.EditGroup, error) {
+	if !obj.GroupID.Valid {
+		return nil, nil
+	}
+
+	qb := models.NewEditQueryBuilder(nil)
+	return qb.FindGroup(obj.GroupID.UUID)
 }
 
 func (r *editResolver) Status(ctx context.Context, obj *models.Edit) (models.VoteStatusEnum, error) {
@@ -14,6 +14,10 @@ func (r *editCommentResolver) ID(ctx context.Context, obj *models.EditComment) (
 }
 
 func (r *editCommentResolver) Comment(ctx context.Context, obj *models.EditComment) (string, error) {
+	if obj.Hidden && validateAdmin(ctx) != nil {
+		return "", nil
+	}
+
 	return obj.Text, nil
 }
 
@@ -31,3 +35,20 @@ func (r *editCommentResolver) User(ctx context.Context, obj *models.EditComment)
 
 	return user, nil
 }
+
+func (r *editCommentResolver) Parent(ctx context.Context, obj *models.EditComment) (*models.EditComment, error) {
+	if !obj.ParentID.Valid {
+		return nil, nil
+	}
+
+	qb := models.NewEditQueryBuilder(nil)
+	return qb.FindComment(obj.ParentID.UUID)
+}
+
+func (r *editCommentResolver) Edited(ctx context.Context, obj *models.EditComment) (*time.Time, error) {
+	if !obj.EditedAt.Valid {
+		return nil, nil
+	}
+
+	return &obj.EditedAt.Timestamp, nil
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+type editGroupResolver struct{ *Resolver }
+
+func (r *editGroupResolver) ID(ctx context.Context, obj *models.EditGroup) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *editGroupResolver) User(ctx context.Context, obj *models.EditGroup) (*models.User, error) {
+	qb := models.NewUserQueryBuilder(nil)
+	return qb.Find(obj.UserID)
+}
+
+func (r *editGroupResolver) Edits(ctx context.Context, obj *models.EditGroup) ([]*models.Edit, error) {
+	qb := models.NewEditQueryBuilder(nil)
+	return qb.FindByGroupID(obj.ID)
+}
+
+func (r *editGroupResolver) Created(ctx context.Context, obj *models.EditGroup) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
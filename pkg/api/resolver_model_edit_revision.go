@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+type editRevisionResolver struct{ *Resolver }
+
+func (r *editRevisionResolver) ID(ctx context.Context, obj *models.EditRevision) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *editRevisionResolver) Date(ctx context.Context, obj *models.EditRevision) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+
+func (r *editRevisionResolver) Details(ctx context.Context, obj *models.EditRevision) (models.EditDetails, error) {
+	qb := models.NewEditQueryBuilder(nil)
+	revisedEdit, err := qb.Find(obj.EditID)
+	if err != nil {
+		return nil, err
+	}
+	if revisedEdit == nil {
+		return nil, errors.New("Edit not found")
+	}
+
+	// resolve the details of the revision as those of the edit at the time
+	revisedEdit.Data = obj.Data
+	return resolveEditDetails(revisedEdit)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stashdb/pkg/dataloader"
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+type sceneFacetsResolver struct{ *Resolver }
+
+func (r *sceneFacetsResolver) Studios(ctx context.Context, obj *models.SceneFacets, limit *int) ([]*models.StudioFacet, error) {
+	qb := models.NewSceneQueryBuilder(nil)
+	return qb.GetStudioFacets(obj.Filter, resolveFacetLimit(limit))
+}
+
+func (r *sceneFacetsResolver) Tags(ctx context.Context, obj *models.SceneFacets, limit *int) ([]*models.TagFacet, error) {
+	qb := models.NewSceneQueryBuilder(nil)
+	return qb.GetTagFacets(obj.Filter, resolveFacetLimit(limit))
+}
+
+func (r *sceneFacetsResolver) Years(ctx context.Context, obj *models.SceneFacets) ([]*models.YearFacet, error) {
+	qb := models.NewSceneQueryBuilder(nil)
+	return qb.GetYearFacets(obj.Filter)
+}
+
+type studioFacetResolver struct{ *Resolver }
+
+func (r *studioFacetResolver) Studio(ctx context.Context, obj *models.StudioFacet) (*models.Studio, error) {
+	return dataloader.For(ctx).StudioById.Load(obj.StudioID)
+}
+
+type tagFacetResolver struct{ *Resolver }
+
+func (r *tagFacetResolver) Tag(ctx context.Context, obj *models.TagFacet) (*models.Tag, error) {
+	return dataloader.For(ctx).TagById.Load(obj.TagID)
+}
+
+type performerFacetsResolver struct{ *Resolver }
+
+func (r *performerFacetsResolver) Genders(ctx context.Context, obj *models.PerformerFacets) ([]*models.GenderFacet, error) {
+	qb := models.NewPerformerQueryBuilder(nil)
+	return qb.GetGenderFacets(obj.Filter)
+}
+
+func (r *performerFacetsResolver) Ethnicities(ctx context.Context, obj *models.PerformerFacets) ([]*models.EthnicityFacet, error) {
+	qb := models.NewPerformerQueryBuilder(nil)
+	return qb.GetEthnicityFacets(obj.Filter)
+}
+
+func (r *performerFacetsResolver) Countries(ctx context.Context, obj *models.PerformerFacets, limit *int) ([]*models.CountryFacet, error) {
+	qb := models.NewPerformerQueryBuilder(nil)
+	return qb.GetCountryFacets(obj.Filter, resolveFacetLimit(limit))
+}
+
+// resolveFacetLimit returns the number of facet values to count, where a
+// null limit counts every value.
+func resolveFacetLimit(limit *int) int {
+	if limit == nil {
+		return 0
+	}
+
+	return *limit
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+type notificationResolver struct{ *Resolver }
+
+func (r *notificationResolver) ID(ctx context.Context, obj *models.Notification) (string, error) {
+	return obj.ID.String(), nil
+}
+
+func (r *notificationResolver) Type(ctx context.Context, obj *models.Notification) (models.NotificationEnum, error) {
+	var ret models.NotificationEnum
+	if !resolveEnumString(obj.Type, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *notificationResolver) Edit(ctx context.Context, obj *models.Notification) (*models.Edit, error) {
+	qb := models.NewEditQueryBuilder(nil)
+	return qb.Find(obj.EditID)
+}
+
+func (r *notificationResolver) Created(ctx context.Context, obj *models.Notification) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
+
+func (r *notificationResolver) Read(ctx context.Context, obj *models.Notification) (bool, error) {
+	return obj.ReadAt.Valid, nil
+}
+
+type notificationSubscriptionResolver struct{ *Resolver }
+
+func (r *notificationSubscriptionResolver) TargetType(ctx context.Context, obj *models.NotificationSubscription) (models.TargetTypeEnum, error) {
+	var ret models.TargetTypeEnum
+	if !resolveEnumString(obj.TargetType, &ret) {
+		return "", nil
+	}
+
+	return ret, nil
+}
+
+func (r *notificationSubscriptionResolver) Target(ctx context.Context, obj *models.NotificationSubscription) (models.EditTarget, error) {
+	switch obj.TargetType {
+	case models.TargetTypeEnumTag.String():
+		qb := models.NewTagQueryBuilder(nil)
+		target, err := qb.Find(obj.TargetID)
+		if target == nil {
+			return nil, err
+		}
+		return target, err
+	case models.TargetTypeEnumPerformer.String():
+		qb := models.NewPerformerQueryBuilder(nil)
+		target, err := qb.Find(obj.TargetID)
+		if target == nil {
+			return nil, err
+		}
+		return target, err
+	case models.TargetTypeEnumScene.String():
+		qb := models.NewSceneQueryBuilder(nil)
+		target, err := qb.Find(obj.TargetID)
+		if target == nil {
+			return nil, err
+		}
+		return target, err
+	case models.TargetTypeEnumStudio.String():
+		qb := models.NewStudioQueryBuilder(nil)
+		target, err := qb.Find(obj.TargetID)
+		if target == nil {
+			return nil, err
+		}
+		return target, err
+	}
+
+	return nil, nil
+}
+
+func (r *notificationSubscriptionResolver) Created(ctx context.Context, obj *models.NotificationSubscription) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stashdb/pkg/dataloader"
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+type performerEditResolver struct{ *Resolver }
+
+func (r *performerEditResolver) Gender(ctx context.Context, obj *models.PerformerEdit) (*models.GenderEnum, error) {
+	var ret models.GenderEnum
+	if obj.Gender == nil || !resolveEnumString(*obj.Gender, &ret) {
+		return nil, nil
+	}
+
+	return &ret, nil
+}
+
+func (r *performerEditResolver) Birthdate(ctx context.Context, obj *models.PerformerEdit) (*models.FuzzyDate, error) {
+	if obj.Birthdate == nil {
+		return nil, nil
+	}
+
+	ret := models.FuzzyDate{
+		Date: *obj.Birthdate,
+	}
+	if obj.BirthdateAccuracy != nil {
+		resolveEnumString(*obj.BirthdateAccuracy, &ret.Accuracy)
+	}
+
+	return &ret, nil
+}
+
+func (r *performerEditResolver) Ethnicity(ctx context.Context, obj *models.PerformerEdit) (*models.EthnicityEnum, error) {
+	var ret models.EthnicityEnum
+	if obj.Ethnicity == nil || !resolveEnumString(*obj.Ethnicity, &ret) {
+		return nil, nil
+	}
+
+	return &ret, nil
+}
+
+func (r *performerEditResolver) EyeColor(ctx context.Context, obj *models.PerformerEdit) (*models.EyeColorEnum, error) {
+	var ret models.EyeColorEnum
+	if obj.EyeColor == nil || !resolveEnumString(*obj.EyeColor, &ret) {
+		return nil, nil
+	}
+
+	return &ret, nil
+}
+
+func (r *performerEditResolver) HairColor(ctx context.Context, obj *models.PerformerEdit) (*models.HairColorEnum, error) {
+	var ret models.HairColorEnum
+	if obj.HairColor == nil || !resolveEnumString(*obj.HairColor, &ret) {
+		return nil, nil
+	}
+
+	return &ret, nil
+}
+
+func (r *performerEditResolver) Measurements(ctx context.Context, obj *models.PerformerEdit) (*models.Measurements, error) {
+	if obj.CupSize == nil && obj.BandSize == nil && obj.WaistSize == nil && obj.HipSize == nil {
+		return nil, nil
+	}
+
+	ret := models.Measurements{
+		CupSize:  obj.CupSize,
+		BandSize: obj.BandSize,
+		Waist:    obj.WaistSize,
+		Hip:      obj.HipSize,
+	}
+	return &ret, nil
+}
+
+func (r *performerEditResolver) BreastType(ctx context.Context, obj *models.PerformerEdit) (*models.BreastTypeEnum, error) {
+	var ret models.BreastTypeEnum
+	if obj.BreastType == nil || !resolveEnumString(*obj.BreastType, &ret) {
+		return nil, nil
+	}
+
+	return &ret, nil
+}
+
+func (r *performerEditResolver) AddedImages(ctx context.Context, obj *models.PerformerEdit) ([]*models.Image, error) {
+	return resolveEditImages(ctx, obj.AddedImages)
+}
+
+func (r *performerEditResolver) RemovedImages(ctx context.Context, obj *models.PerformerEdit) ([]*models.Image, error) {
+	return resolveEditImages(ctx, obj.RemovedImages)
+}
+
+func resolveEditImages(ctx context.Context, ids []string) ([]*models.Image, error) {
+	var imageIDs []uuid.UUID
+	for _, id := range ids {
+		imageID, err := uuid.FromString(id)
+		if err != nil {
+			return nil, err
+		}
+		imageIDs = append(imageIDs, imageID)
+	}
+
+	images, errors := dataloader.For(ctx).ImageById.LoadAll(imageIDs)
+	for _, err := range errors {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return images, nil
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+type reportedFingerprintResolver struct{ *Resolver }
+
+func (r *reportedFingerprintResolver) Scene(ctx context.Context, obj *models.ReportedFingerprint) (*models.Scene, error) {
+	qb := models.NewSceneQueryBuilder(nil)
+	return qb.Find(obj.SceneID)
+}
+
+func (r *reportedFingerprintResolver) Reports(ctx context.Context, obj *models.ReportedFingerprint) ([]*models.SceneFingerprintReport, error) {
+	qb := models.NewSceneQueryBuilder(nil)
+	return qb.GetFingerprintReports(obj.SceneID, obj.Fingerprint.Algorithm, obj.Fingerprint.Hash)
+}
+
+type fingerprintReportResolver struct{ *Resolver }
+
+func (r *fingerprintReportResolver) User(ctx context.Context, obj *models.SceneFingerprintReport) (*models.User, error) {
+	qb := models.NewUserQueryBuilder(nil)
+	return qb.Find(obj.UserID)
+}
+
+func (r *fingerprintReportResolver) Created(ctx context.Context, obj *models.SceneFingerprintReport) (*time.Time, error) {
+	return &obj.CreatedAt.Timestamp, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/stashapp/stashdb/pkg/dataloader"
+	"github.com/stashapp/stashdb/pkg/manager/config"
 	"github.com/stashapp/stashdb/pkg/models"
 )
 
@@ -103,6 +104,50 @@ func (r *sceneResolver) Fingerprints(ctx context.Context, obj *models.Scene) ([]
 	return dataloader.For(ctx).SceneFingerprintsById.Load(obj.ID)
 }
 
+func (r *sceneResolver) FingerprintDuration(ctx context.Context, obj *models.Scene) (*int, error) {
+	fingerprints, err := dataloader.For(ctx).SceneFingerprintsById.Load(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	consensus, _, ok := models.FingerprintDurations(fingerprints)
+	if !ok {
+		return nil, nil
+	}
+	return &consensus, nil
+}
+
+func (r *sceneResolver) FingerprintDurationSpread(ctx context.Context, obj *models.Scene) (*int, error) {
+	fingerprints, err := dataloader.For(ctx).SceneFingerprintsById.Load(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, spread, ok := models.FingerprintDurations(fingerprints)
+	if !ok {
+		return nil, nil
+	}
+	return &spread, nil
+}
+
+func (r *sceneResolver) DurationDisputed(ctx context.Context, obj *models.Scene) (bool, error) {
+	threshold := config.GetDurationDisputeThreshold()
+	if threshold <= 0 {
+		return false, nil
+	}
+
+	spread, err := r.FingerprintDurationSpread(ctx, obj)
+	if err != nil || spread == nil {
+		return false, err
+	}
+	return *spread > threshold, nil
+}
+
 func (r *sceneResolver) Urls(ctx context.Context, obj *models.Scene) ([]*models.URL, error) {
 	return dataloader.For(ctx).SceneUrlsById.Load(obj.ID)
 }
+
+func (r *sceneResolver) Edits(ctx context.Context, obj *models.Scene) ([]*models.Edit, error) {
+	eqb := models.NewEditQueryBuilder(nil)
+	return eqb.FindBySceneID(obj.ID)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stashdb/pkg/dataloader"
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+type sceneEditResolver struct{ *Resolver }
+
+func (r *sceneEditResolver) AddedPerformers(ctx context.Context, obj *models.SceneEdit) ([]*models.PerformerAppearance, error) {
+	return resolveEditPerformerAppearances(ctx, obj.AddedPerformers)
+}
+
+func (r *sceneEditResolver) RemovedPerformers(ctx context.Context, obj *models.SceneEdit) ([]*models.PerformerAppearance, error) {
+	return resolveEditPerformerAppearances(ctx, obj.RemovedPerformers)
+}
+
+func (r *sceneEditResolver) AddedTags(ctx context.Context, obj *models.SceneEdit) ([]*models.Tag, error) {
+	return resolveEditTags(ctx, obj.AddedTags)
+}
+
+func (r *sceneEditResolver) RemovedTags(ctx context.Context, obj *models.SceneEdit) ([]*models.Tag, error) {
+	return resolveEditTags(ctx, obj.RemovedTags)
+}
+
+func (r *sceneEditResolver) AddedImages(ctx context.Context, obj *models.SceneEdit) ([]*models.Image, error) {
+	return resolveEditImages(ctx, obj.AddedImages)
+}
+
+func (r *sceneEditResolver) RemovedImages(ctx context.Context, obj *models.SceneEdit) ([]*models.Image, error) {
+	return resolveEditImages(ctx, obj.RemovedImages)
+}
+
+func resolveEditPerformerAppearances(ctx context.Context, appearances []*models.PerformerAppearanceInput) ([]*models.PerformerAppearance, error) {
+	var ret []*models.PerformerAppearance
+	for _, appearance := range appearances {
+		performerID, err := uuid.FromString(appearance.PerformerID)
+		if err != nil {
+			return nil, err
+		}
+
+		performer, err := dataloader.For(ctx).PerformerById.Load(performerID)
+		if err != nil {
+			return nil, err
+		}
+
+		retApp := models.PerformerAppearance{
+			Performer: performer,
+			As:        appearance.As,
+		}
+		ret = append(ret, &retApp)
+	}
+
+	return ret, nil
+}
+
+func resolveEditTags(ctx context.Context, ids []string) ([]*models.Tag, error) {
+	var tagIDs []uuid.UUID
+	for _, id := range ids {
+		tagID, err := uuid.FromString(id)
+		if err != nil {
+			return nil, err
+		}
+		tagIDs = append(tagIDs, tagID)
+	}
+
+	tags, errors := dataloader.For(ctx).TagById.LoadAll(tagIDs)
+	for _, err := range errors {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
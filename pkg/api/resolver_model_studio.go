@@ -55,3 +55,8 @@ func (r *studioResolver) Images(ctx context.Context, obj *models.Studio) ([]*mod
 	}
 	return images, nil
 }
+
+func (r *studioResolver) Edits(ctx context.Context, obj *models.Studio) ([]*models.Edit, error) {
+	eqb := models.NewEditQueryBuilder(nil)
+	return eqb.FindByStudioID(obj.ID)
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+type studioEditResolver struct{ *Resolver }
+
+func (r *studioEditResolver) Parent(ctx context.Context, obj *models.StudioEdit) (*models.Studio, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+
+	parentID, err := uuid.FromString(*obj.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	qb := models.NewStudioQueryBuilder(nil)
+	return qb.Find(parentID)
+}
+
+func (r *studioEditResolver) AddedChildStudios(ctx context.Context, obj *models.StudioEdit) ([]*models.Studio, error) {
+	return resolveEditStudios(obj.AddedChildStudios)
+}
+
+func (r *studioEditResolver) RemovedChildStudios(ctx context.Context, obj *models.StudioEdit) ([]*models.Studio, error) {
+	return resolveEditStudios(obj.RemovedChildStudios)
+}
+
+func (r *studioEditResolver) AddedImages(ctx context.Context, obj *models.StudioEdit) ([]*models.Image, error) {
+	return resolveEditImages(ctx, obj.AddedImages)
+}
+
+func (r *studioEditResolver) RemovedImages(ctx context.Context, obj *models.StudioEdit) ([]*models.Image, error) {
+	return resolveEditImages(ctx, obj.RemovedImages)
+}
+
+func resolveEditStudios(ids []string) ([]*models.Studio, error) {
+	qb := models.NewStudioQueryBuilder(nil)
+
+	var ret []*models.Studio
+	for _, id := range ids {
+		studioID, err := uuid.FromString(id)
+		if err != nil {
+			return nil, err
+		}
+
+		studio, err := qb.Find(studioID)
+		if err != nil {
+			return nil, err
+		}
+		if studio != nil {
+			ret = append(ret, studio)
+		}
+	}
+
+	return ret, nil
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 
+	"github.com/stashapp/stashdb/pkg/dataloader"
 	"github.com/stashapp/stashdb/pkg/manager/config"
 	"github.com/stashapp/stashdb/pkg/models"
 )
@@ -25,23 +26,35 @@ func (r *userResolver) Roles(ctx context.Context, obj *models.User) ([]models.Ro
 }
 
 func (r *userResolver) SuccessfulEdits(ctx context.Context, obj *models.User) (int, error) {
-	// TODO
-	return 0, nil
+	stats, err := dataloader.For(ctx).UserEditStatsById.Load(obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return stats.SuccessfulEdits, nil
 }
 
 func (r *userResolver) UnsuccessfulEdits(ctx context.Context, obj *models.User) (int, error) {
-	// TODO
-	return 0, nil
+	stats, err := dataloader.For(ctx).UserEditStatsById.Load(obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return stats.UnsuccessfulEdits, nil
 }
 
 func (r *userResolver) SuccessfulVotes(ctx context.Context, obj *models.User) (int, error) {
-	// TODO
-	return 0, nil
+	stats, err := dataloader.For(ctx).UserEditStatsById.Load(obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return stats.SuccessfulVotes, nil
 }
 
 func (r *userResolver) UnsuccessfulVotes(ctx context.Context, obj *models.User) (int, error) {
-	// TODO
-	return 0, nil
+	stats, err := dataloader.For(ctx).UserEditStatsById.Load(obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return stats.UnsuccessfulVotes, nil
 }
 
 func (r *userResolver) InvitedBy(ctx context.Context, obj *models.User) (*models.User, error) {
@@ -76,3 +89,25 @@ func (r *userResolver) ActiveInviteCodes(ctx context.Context, obj *models.User)
 	}
 	return ret, nil
 }
+
+func (r *userResolver) Notifications(ctx context.Context, obj *models.User, unreadOnly *bool) ([]*models.Notification, error) {
+	// only show to the user themselves
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil || currentUser.ID != obj.ID {
+		return nil, nil
+	}
+
+	qb := models.NewNotificationQueryBuilder(nil)
+	return qb.FindByUserID(obj.ID, unreadOnly != nil && *unreadOnly)
+}
+
+func (r *userResolver) NotificationSubscriptions(ctx context.Context, obj *models.User) ([]*models.NotificationSubscription, error) {
+	// only show to the user themselves
+	currentUser := getCurrentUser(ctx)
+	if currentUser == nil || currentUser.ID != obj.ID {
+		return nil, nil
+	}
+
+	qb := models.NewNotificationQueryBuilder(nil)
+	return qb.GetSubscriptions(obj.ID)
+}
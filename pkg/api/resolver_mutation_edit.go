@@ -3,31 +3,95 @@ package api
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
 
 	"github.com/stashapp/stashdb/pkg/database"
 	"github.com/stashapp/stashdb/pkg/manager/edit"
+	"github.com/stashapp/stashdb/pkg/manager/notification"
 	"github.com/stashapp/stashdb/pkg/models"
 )
 
 func (r *mutationResolver) SceneEdit(ctx context.Context, input models.SceneEditInput) (*models.Edit, error) {
-	panic("not implemented")
+	if err := validateEdit(ctx); err != nil {
+		return nil, err
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	// create the edit
+	currentUser := getCurrentUser(ctx)
+
+	newEdit := models.NewEdit(UUID, currentUser, models.TargetTypeEnumScene, input.Edit)
+
+	tx := database.DB.MustBeginTx(ctx, nil)
+
+	err = buildSceneEdit(ctx, tx, newEdit, input)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if input.Edit.EditID != nil {
+		return r.amendEdit(ctx, tx, newEdit, input.Edit)
+	}
+
+	// save the edit
+	eqb := models.NewEditQueryBuilder(tx)
+
+	created, err := eqb.Create(*newEdit)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if input.Edit.ID != nil {
+		sceneID, _ := uuid.FromString(*input.Edit.ID)
+
+		editScene := models.EditScene{
+			EditID:  created.ID,
+			SceneID: sceneID,
+		}
+
+		err = eqb.CreateEditScene(editScene)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if input.Edit.Comment != nil {
+		commentID, _ := uuid.NewV4()
+		comment := models.NewEditComment(commentID, currentUser, created, *input.Edit.Comment)
+		if err := eqb.CreateComment(*comment); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := applyTrustedEdit(ctx, tx, newEdit); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	// Commit
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return newEdit, nil
 }
 func (r *mutationResolver) PerformerEdit(ctx context.Context, input models.PerformerEditInput) (*models.Edit, error) {
-	panic("not implemented")
-}
-func (r *mutationResolver) StudioEdit(ctx context.Context, input models.StudioEditInput) (*models.Edit, error) {
-	panic("not implemented")
-}
-
-func (r *mutationResolver) TagEdit(ctx context.Context, input models.TagEditInput) (*models.Edit, error) {
 	if err := validateEdit(ctx); err != nil {
 		return nil, err
 	}
 
-	// TODO - handle modification of existing edit
-
 	UUID, err := uuid.NewV4()
 	if err != nil {
 		return nil, err
@@ -36,40 +100,163 @@ func (r *mutationResolver) TagEdit(ctx context.Context, input models.TagEditInpu
 	// create the edit
 	currentUser := getCurrentUser(ctx)
 
-	newEdit := models.NewEdit(UUID, currentUser, models.TargetTypeEnumTag, input.Edit)
+	newEdit := models.NewEdit(UUID, currentUser, models.TargetTypeEnumPerformer, input.Edit)
 
 	tx := database.DB.MustBeginTx(ctx, nil)
 
-	if input.Edit.Operation == models.OperationEnumModify {
-		err = edit.ModifyTagEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	err = buildPerformerEdit(ctx, tx, newEdit, input)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if input.Edit.EditID != nil {
+		return r.amendEdit(ctx, tx, newEdit, input.Edit)
+	}
+
+	// save the edit
+	eqb := models.NewEditQueryBuilder(tx)
+
+	created, err := eqb.Create(*newEdit)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if input.Edit.ID != nil {
+		performerID, _ := uuid.FromString(*input.Edit.ID)
+
+		editPerformer := models.EditPerformer{
+			EditID:      created.ID,
+			PerformerID: performerID,
+		}
 
+		err = eqb.CreateEditPerformer(editPerformer)
 		if err != nil {
 			_ = tx.Rollback()
 			return nil, err
 		}
-	} else if input.Edit.Operation == models.OperationEnumMerge {
-		err = edit.MergeTagEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	}
 
-		if err != nil {
+	if input.Edit.Comment != nil {
+		commentID, _ := uuid.NewV4()
+		comment := models.NewEditComment(commentID, currentUser, created, *input.Edit.Comment)
+		if err := eqb.CreateComment(*comment); err != nil {
 			_ = tx.Rollback()
 			return nil, err
 		}
-	} else if input.Edit.Operation == models.OperationEnumDestroy {
-		err = edit.DestroyTagEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	}
 
+	if err := applyTrustedEdit(ctx, tx, newEdit); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	// Commit
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return newEdit, nil
+}
+func (r *mutationResolver) StudioEdit(ctx context.Context, input models.StudioEditInput) (*models.Edit, error) {
+	if err := validateEdit(ctx); err != nil {
+		return nil, err
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	// create the edit
+	currentUser := getCurrentUser(ctx)
+
+	newEdit := models.NewEdit(UUID, currentUser, models.TargetTypeEnumStudio, input.Edit)
+
+	tx := database.DB.MustBeginTx(ctx, nil)
+
+	err = buildStudioEdit(ctx, tx, newEdit, input)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if input.Edit.EditID != nil {
+		return r.amendEdit(ctx, tx, newEdit, input.Edit)
+	}
+
+	// save the edit
+	eqb := models.NewEditQueryBuilder(tx)
+
+	created, err := eqb.Create(*newEdit)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if input.Edit.ID != nil {
+		studioID, _ := uuid.FromString(*input.Edit.ID)
+
+		editStudio := models.EditStudio{
+			EditID:   created.ID,
+			StudioID: studioID,
+		}
+
+		err = eqb.CreateEditStudio(editStudio)
 		if err != nil {
 			_ = tx.Rollback()
 			return nil, err
 		}
-	} else if input.Edit.Operation == models.OperationEnumCreate {
-		err = edit.CreateTagEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	}
 
-		if err != nil {
+	if input.Edit.Comment != nil {
+		commentID, _ := uuid.NewV4()
+		comment := models.NewEditComment(commentID, currentUser, created, *input.Edit.Comment)
+		if err := eqb.CreateComment(*comment); err != nil {
 			_ = tx.Rollback()
 			return nil, err
 		}
-	} else {
-		panic("not implemented")
+	}
+
+	if err := applyTrustedEdit(ctx, tx, newEdit); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	// Commit
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return newEdit, nil
+}
+
+func (r *mutationResolver) TagEdit(ctx context.Context, input models.TagEditInput) (*models.Edit, error) {
+	if err := validateEdit(ctx); err != nil {
+		return nil, err
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	// create the edit
+	currentUser := getCurrentUser(ctx)
+
+	newEdit := models.NewEdit(UUID, currentUser, models.TargetTypeEnumTag, input.Edit)
+
+	tx := database.DB.MustBeginTx(ctx, nil)
+
+	err = buildTagEdit(ctx, tx, newEdit, input)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if input.Edit.EditID != nil {
+		return r.amendEdit(ctx, tx, newEdit, input.Edit)
 	}
 
 	// save the edit
@@ -104,6 +291,11 @@ func (r *mutationResolver) TagEdit(ctx context.Context, input models.TagEditInpu
 		}
 	}
 
+	if err := applyTrustedEdit(ctx, tx, newEdit); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
 	// Commit
 	if err := tx.Commit(); err != nil {
 		return nil, err
@@ -111,137 +303,448 @@ func (r *mutationResolver) TagEdit(ctx context.Context, input models.TagEditInpu
 
 	return newEdit, nil
 }
+
+// applyTrustedEdit applies the new edit without a vote if the current user is
+// a trusted editor and the edit only makes low risk changes.
+func applyTrustedEdit(ctx context.Context, tx *sqlx.Tx, newEdit *models.Edit) error {
+	_, err := edit.ApplyTrustedEdit(tx, newEdit, getCurrentUser(ctx), getCurrentRoles(ctx))
+	return err
+}
+
+// amendEdit replaces the details of the existing edit referenced by the
+// input with those of the amended edit, and commits the transaction.
+func (r *mutationResolver) amendEdit(ctx context.Context, tx *sqlx.Tx, amendedEdit *models.Edit, input *models.EditInput) (*models.Edit, error) {
+	editID, err := uuid.FromString(*input.EditID)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	eqb := models.NewEditQueryBuilder(tx)
+	existingEdit, err := eqb.Find(editID)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if existingEdit == nil {
+		_ = tx.Rollback()
+		return nil, errors.New("Edit not found")
+	}
+
+	currentUser := getCurrentUser(ctx)
+	if err := edit.AmendEdit(tx, existingEdit, amendedEdit, currentUser, input.ID); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if input.Comment != nil {
+		commentID, _ := uuid.NewV4()
+		comment := models.NewEditComment(commentID, currentUser, existingEdit, *input.Comment)
+		if err := eqb.CreateComment(*comment); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return existingEdit, nil
+}
+
+func (r *mutationResolver) EditGroupCreate(ctx context.Context, input models.EditGroupInput) (*models.EditGroup, error) {
+	if err := validateEdit(ctx); err != nil {
+		return nil, err
+	}
+
+	currentUser := getCurrentUser(ctx)
+	var group *models.EditGroup
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		eqb := models.NewEditQueryBuilder(txn.GetTx())
+
+		var edits []*models.Edit
+		for _, id := range input.EditIds {
+			editID, err := uuid.FromString(id)
+			if err != nil {
+				return err
+			}
+			groupedEdit, err := eqb.Find(editID)
+			if err != nil {
+				return err
+			}
+			if groupedEdit == nil {
+				return errors.New("Edit not found: " + id)
+			}
+			edits = append(edits, groupedEdit)
+		}
+
+		var err error
+		group, err = edit.CreateEditGroup(txn.GetTx(), currentUser, edits)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
 func (r *mutationResolver) EditVote(ctx context.Context, input models.EditVoteInput) (*models.Edit, error) {
-	panic("not implemented")
+	if err := validateVote(ctx); err != nil {
+		return nil, err
+	}
+
+	if input.Type == models.VoteTypeEnumImmediateAccept || input.Type == models.VoteTypeEnumImmediateReject {
+		if err := validateAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	tx := database.DB.MustBeginTx(ctx, nil)
+
+	editID, _ := uuid.FromString(input.ID)
+	eqb := models.NewEditQueryBuilder(tx)
+	votedEdit, err := eqb.Find(editID)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if votedEdit == nil {
+		_ = tx.Rollback()
+		return nil, errors.New("Edit not found")
+	}
+
+	currentUser := getCurrentUser(ctx)
+	if err := edit.CreateVote(tx, votedEdit, currentUser, input.Type, input.Comment); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return votedEdit, nil
 }
 func (r *mutationResolver) EditComment(ctx context.Context, input models.EditCommentInput) (*models.Edit, error) {
-	panic("not implemented")
+	if err := validateEdit(ctx); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(input.Comment) == "" {
+		return nil, errors.New("Comment cannot be empty")
+	}
+
+	tx := database.DB.MustBeginTx(ctx, nil)
+
+	editID, _ := uuid.FromString(input.ID)
+	eqb := models.NewEditQueryBuilder(tx)
+	commentedEdit, err := eqb.Find(editID)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if commentedEdit == nil {
+		_ = tx.Rollback()
+		return nil, errors.New("Edit not found")
+	}
+
+	currentUser := getCurrentUser(ctx)
+	commentID, _ := uuid.NewV4()
+	comment := models.NewEditComment(commentID, currentUser, commentedEdit, input.Comment)
+
+	if input.ParentID != nil {
+		parentID, _ := uuid.FromString(*input.ParentID)
+		parent, err := eqb.FindComment(parentID)
+		if err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+		if parent == nil || parent.EditID != commentedEdit.ID {
+			_ = tx.Rollback()
+			return nil, errors.New("Parent comment not found")
+		}
+		comment.ParentID = uuid.NullUUID{UUID: parentID, Valid: true}
+	}
+
+	if err := eqb.CreateComment(*comment); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := notification.OnEditComment(tx, commentedEdit, currentUser); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return commentedEdit, nil
 }
 
-func (r *mutationResolver) CancelEdit(ctx context.Context, input models.CancelEditInput) (*models.Edit, error) {
-	if err := validateAdmin(ctx); err != nil {
+func (r *mutationResolver) EditCommentUpdate(ctx context.Context, input models.EditCommentUpdateInput) (*models.EditComment, error) {
+	if err := validateEdit(ctx); err != nil {
 		return nil, err
 	}
 
+	if strings.TrimSpace(input.Comment) == "" {
+		return nil, errors.New("Comment cannot be empty")
+	}
+
 	tx := database.DB.MustBeginTx(ctx, nil)
 
-	tagID, _ := uuid.FromString(input.ID)
+	commentID, _ := uuid.FromString(input.ID)
 	eqb := models.NewEditQueryBuilder(tx)
-	edit, err := eqb.Find(tagID)
+	comment, err := eqb.FindComment(commentID)
 	if err != nil {
+		_ = tx.Rollback()
 		return nil, err
 	}
-	if edit == nil {
-		return nil, errors.New("Edit not found")
+	if comment == nil {
+		_ = tx.Rollback()
+		return nil, errors.New("Comment not found")
+	}
+
+	// only the author may edit a comment
+	if comment.UserID != getCurrentUser(ctx).ID {
+		_ = tx.Rollback()
+		return nil, ErrUnauthorized
+	}
+	if comment.Hidden {
+		_ = tx.Rollback()
+		return nil, errors.New("Hidden comments cannot be edited")
+	}
+
+	comment.Text = input.Comment
+	comment.EditedAt = models.NullSQLiteTimestamp{Timestamp: time.Now(), Valid: true}
+	if err := eqb.UpdateComment(*comment); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
 	}
 
-	var status models.VoteStatusEnum
-	resolveEnumString(edit.Status, &status)
-	if status != models.VoteStatusEnumPending {
-		return nil, errors.New("Invalid vote status: " + edit.Status)
+	return comment, nil
+}
+
+func (r *mutationResolver) EditCommentHide(ctx context.Context, input models.EditCommentHideInput) (*models.EditComment, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
 	}
 
-	edit.ImmediateReject()
-	updatedEdit, err := eqb.Update(*edit)
+	tx := database.DB.MustBeginTx(ctx, nil)
 
+	commentID, _ := uuid.FromString(input.ID)
+	eqb := models.NewEditQueryBuilder(tx)
+	comment, err := eqb.FindComment(commentID)
 	if err != nil {
 		_ = tx.Rollback()
 		return nil, err
 	}
+	if comment == nil {
+		_ = tx.Rollback()
+		return nil, errors.New("Comment not found")
+	}
+
+	comment.Hidden = input.Hidden
+	if err := eqb.UpdateComment(*comment); err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	return updatedEdit, nil
+	return comment, nil
 }
 
-func (r *mutationResolver) ApplyEdit(ctx context.Context, input models.ApplyEditInput) (*models.Edit, error) {
+func (r *mutationResolver) CancelEdit(ctx context.Context, input models.CancelEditInput) (*models.Edit, error) {
 	if err := validateAdmin(ctx); err != nil {
 		return nil, err
 	}
 
 	tx := database.DB.MustBeginTx(ctx, nil)
 
-	tagID, _ := uuid.FromString(input.ID)
+	editID, _ := uuid.FromString(input.ID)
 	eqb := models.NewEditQueryBuilder(tx)
-	edit, err := eqb.Find(tagID)
+	pendingEdit, err := eqb.Find(editID)
 	if err != nil {
+		_ = tx.Rollback()
 		return nil, err
 	}
-	if edit == nil {
+	if pendingEdit == nil {
+		_ = tx.Rollback()
 		return nil, errors.New("Edit not found")
 	}
 
-	if edit.Applied {
-		return nil, errors.New("Edit already applied")
+	if err := edit.ImmediateReject(tx, pendingEdit); err != nil {
+		_ = tx.Rollback()
+		return nil, err
 	}
 
-	var status models.VoteStatusEnum
-	resolveEnumString(edit.Status, &status)
-	if status != models.VoteStatusEnumPending {
-		return nil, errors.New("Invalid vote status: " + edit.Status)
+	if err := tx.Commit(); err != nil {
+		return nil, err
 	}
 
-	var operation models.OperationEnum
-	resolveEnumString(edit.Operation, &operation)
-	var targetType models.TargetTypeEnum
-	resolveEnumString(edit.TargetType, &targetType)
-	switch targetType {
-	case models.TargetTypeEnumTag:
-		tqb := models.NewTagQueryBuilder(tx)
-		var tag *models.Tag = nil
-		if operation != models.OperationEnumCreate {
-			tagID, err := eqb.FindTagID(edit.ID)
-			if err != nil {
-				return nil, err
-			}
-			tag, err = tqb.Find(*tagID)
-			if err != nil {
-				return nil, err
-			}
-			if tag == nil {
-				return nil, errors.New("Tag not found: " + tagID.String())
-			}
-		}
-		newTag, err := tqb.ApplyEdit(*edit, operation, tag)
+	return pendingEdit, nil
+}
+
+func (r *mutationResolver) ApplyEdit(ctx context.Context, input models.ApplyEditInput) (*models.Edit, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	editID, _ := uuid.FromString(input.ID)
+	var appliedEdit *models.Edit
+
+	// the edits of a group are applied in the same transaction, so that
+	// none of them are applied if any of them fails
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		eqb := models.NewEditQueryBuilder(txn.GetTx())
+		pendingEdit, err := eqb.Find(editID)
 		if err != nil {
-			_ = tx.Rollback()
-			return nil, err
+			return err
+		}
+		if pendingEdit == nil {
+			return errors.New("Edit not found")
 		}
 
-		if operation == models.OperationEnumCreate {
-			editTag := models.EditTag{
-				EditID: edit.ID,
-				TagID:  newTag.ID,
-			}
+		appliedEdit = pendingEdit
+		return edit.ImmediateAccept(txn.GetTx(), pendingEdit)
+	})
 
-			err = eqb.CreateEditTag(editTag)
-			if err != nil {
-				_ = tx.Rollback()
-				return nil, err
-			}
-		}
-	default:
-		return nil, errors.New("Not implemented: " + edit.TargetType)
+	if err != nil {
+		return nil, err
 	}
 
+	return appliedEdit, nil
+}
+
+func (r *mutationResolver) RevertEdit(ctx context.Context, input models.RevertEditInput) (*models.Edit, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	currentUser := getCurrentUser(ctx)
+	tx := database.DB.MustBeginTx(ctx, nil)
+
+	editID, _ := uuid.FromString(input.ID)
+	eqb := models.NewEditQueryBuilder(tx)
+	appliedEdit, err := eqb.Find(editID)
 	if err != nil {
 		_ = tx.Rollback()
 		return nil, err
 	}
+	if appliedEdit == nil {
+		_ = tx.Rollback()
+		return nil, errors.New("Edit not found")
+	}
 
-	edit.ImmediateAccept()
-	updatedEdit, err := eqb.Update(*edit)
-
+	revertEdit, err := edit.RevertEdit(tx, appliedEdit, currentUser)
 	if err != nil {
 		_ = tx.Rollback()
 		return nil, err
 	}
 
+	if input.Comment != nil {
+		commentID, _ := uuid.NewV4()
+		comment := models.NewEditComment(commentID, currentUser, revertEdit, *input.Comment)
+		if err := eqb.CreateComment(*comment); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	return updatedEdit, nil
+	return revertEdit, nil
+}
+
+func buildTagEdit(ctx context.Context, tx *sqlx.Tx, newEdit *models.Edit, input models.TagEditInput) error {
+	if input.Details == nil {
+		input.Details = &models.TagEditDetailsInput{}
+	}
+
+	switch input.Edit.Operation {
+	case models.OperationEnumModify:
+		return edit.ModifyTagEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	case models.OperationEnumMerge:
+		return edit.MergeTagEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	case models.OperationEnumDestroy:
+		return edit.DestroyTagEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	case models.OperationEnumCreate:
+		return edit.CreateTagEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	}
+
+	return errors.New("Unsupported operation: " + input.Edit.Operation.String())
+}
+
+func buildPerformerEdit(ctx context.Context, tx *sqlx.Tx, newEdit *models.Edit, input models.PerformerEditInput) error {
+	if input.Details == nil {
+		input.Details = &models.PerformerEditDetailsInput{}
+	}
+
+	switch input.Edit.Operation {
+	case models.OperationEnumModify:
+		return edit.ModifyPerformerEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	case models.OperationEnumMerge:
+		return edit.MergePerformerEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	case models.OperationEnumDestroy:
+		return edit.DestroyPerformerEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	case models.OperationEnumCreate:
+		return edit.CreatePerformerEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	}
+
+	return errors.New("Unsupported operation: " + input.Edit.Operation.String())
+}
+
+func buildSceneEdit(ctx context.Context, tx *sqlx.Tx, newEdit *models.Edit, input models.SceneEditInput) error {
+	if input.Details == nil {
+		input.Details = &models.SceneEditDetailsInput{}
+	}
+
+	if err := models.ValidateFingerprints(input.Details.Fingerprints); err != nil {
+		return err
+	}
+
+	switch input.Edit.Operation {
+	case models.OperationEnumModify:
+		return edit.ModifySceneEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	case models.OperationEnumMerge:
+		return edit.MergeSceneEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	case models.OperationEnumDestroy:
+		return edit.DestroySceneEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	case models.OperationEnumCreate:
+		return edit.CreateSceneEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	}
+
+	return errors.New("Unsupported operation: " + input.Edit.Operation.String())
+}
+
+func buildStudioEdit(ctx context.Context, tx *sqlx.Tx, newEdit *models.Edit, input models.StudioEditInput) error {
+	if input.Details == nil {
+		input.Details = &models.StudioEditDetailsInput{}
+	}
+
+	switch input.Edit.Operation {
+	case models.OperationEnumModify:
+		return edit.ModifyStudioEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	case models.OperationEnumMerge:
+		return edit.MergeStudioEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	case models.OperationEnumDestroy:
+		return edit.DestroyStudioEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	case models.OperationEnumCreate:
+		return edit.CreateStudioEdit(tx, newEdit, input, wasFieldIncludedFunc(ctx))
+	}
+
+	return errors.New("Unsupported operation: " + input.Edit.Operation.String())
 }
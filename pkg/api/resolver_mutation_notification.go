@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stashdb/pkg/database"
+	"github.com/stashapp/stashdb/pkg/manager/notification"
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+func (r *mutationResolver) MarkNotificationsRead(ctx context.Context, input models.MarkNotificationsReadInput) (bool, error) {
+	if err := validateRead(ctx); err != nil {
+		return false, err
+	}
+
+	var ids []uuid.UUID
+	for _, id := range input.Ids {
+		notificationID, err := uuid.FromString(id)
+		if err != nil {
+			return false, err
+		}
+		ids = append(ids, notificationID)
+	}
+
+	currentUser := getCurrentUser(ctx)
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		nqb := models.NewNotificationQueryBuilder(txn.GetTx())
+		return nqb.MarkRead(currentUser.ID, ids)
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) SubscribeNotifications(ctx context.Context, input models.NotificationSubscriptionInput) (bool, error) {
+	if err := validateRead(ctx); err != nil {
+		return false, err
+	}
+
+	targetID, err := uuid.FromString(input.TargetID)
+	if err != nil {
+		return false, err
+	}
+
+	currentUser := getCurrentUser(ctx)
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
+		return notification.Subscribe(txn.GetTx(), currentUser, input.TargetType, targetID, input.Subscribed)
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -2,7 +2,9 @@ package api
 
 import (
 	"context"
+	"errors"
 	"github.com/gofrs/uuid"
+	"strings"
 	"time"
 
 	"github.com/stashapp/stashdb/pkg/database"
@@ -14,6 +16,10 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input models.SceneCr
 		return nil, err
 	}
 
+	if err := models.ValidateFingerprints(input.Fingerprints); err != nil {
+		return nil, err
+	}
+
 	var err error
 
 	if err != nil {
@@ -86,6 +92,10 @@ func (r *mutationResolver) SceneUpdate(ctx context.Context, input models.SceneUp
 		return nil, err
 	}
 
+	if err := models.ValidateFingerprints(input.Fingerprints); err != nil {
+		return nil, err
+	}
+
 	tx := database.DB.MustBeginTx(ctx, nil)
 	qb := models.NewSceneQueryBuilder(tx)
 
@@ -171,6 +181,14 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 }
 
 func (r *mutationResolver) SubmitFingerprint(ctx context.Context, input models.FingerprintSubmission) (bool, error) {
+	if err := validateEdit(ctx); err != nil {
+		return false, err
+	}
+
+	if err := models.ValidateFingerprints([]*models.FingerprintInput{input.Fingerprint}); err != nil {
+		return false, err
+	}
+
 	tx := database.DB.MustBeginTx(ctx, nil)
 	qb := models.NewSceneQueryBuilder(tx)
 
@@ -179,13 +197,32 @@ func (r *mutationResolver) SubmitFingerprint(ctx context.Context, input models.F
 	scene, err := qb.Find(sceneID)
 
 	if err != nil {
+		_ = tx.Rollback()
 		return false, err
 	}
-
-	sceneFingerprint := models.CreateSceneFingerprints(scene.ID, []*models.FingerprintInput{input.Fingerprint})
-	if err := qb.CreateFingerprints(sceneFingerprint); err != nil {
+	if scene == nil {
 		_ = tx.Rollback()
-		return false, err
+		return false, errors.New("Scene not found")
+	}
+
+	submission := models.NewSceneFingerprintSubmission(scene.ID, input.Fingerprint, getCurrentUser(ctx))
+
+	if input.Unmatch != nil && *input.Unmatch {
+		if err := qb.DestroyFingerprintSubmission(*submission); err != nil {
+			_ = tx.Rollback()
+			return false, err
+		}
+	} else {
+		sceneFingerprint := models.CreateSceneFingerprints(scene.ID, []*models.FingerprintInput{input.Fingerprint})
+		if err := qb.CreateFingerprints(sceneFingerprint); err != nil {
+			_ = tx.Rollback()
+			return false, err
+		}
+
+		if err := qb.CreateFingerprintSubmission(*submission); err != nil {
+			_ = tx.Rollback()
+			return false, err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -194,3 +231,98 @@ func (r *mutationResolver) SubmitFingerprint(ctx context.Context, input models.F
 
 	return true, nil
 }
+
+func (r *mutationResolver) FingerprintDestroy(ctx context.Context, input models.FingerprintDestroyInput) (bool, error) {
+	if err := validateModify(ctx); err != nil {
+		return false, err
+	}
+
+	fingerprint := models.FingerprintInput{Algorithm: input.Algorithm, Hash: input.Hash}
+	if err := models.ValidateFingerprints([]*models.FingerprintInput{&fingerprint}); err != nil {
+		return false, err
+	}
+	input.Hash = fingerprint.Hash
+
+	sceneID, err := uuid.FromString(input.SceneID)
+	if err != nil {
+		return false, err
+	}
+
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
+		qb := models.NewSceneQueryBuilder(txn.GetTx())
+		return qb.DestroyFingerprint(sceneID, input.Algorithm, input.Hash)
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) ReportFingerprint(ctx context.Context, input models.FingerprintReportInput) (bool, error) {
+	if err := validateEdit(ctx); err != nil {
+		return false, err
+	}
+
+	// normalise the hash as it is stored
+	fingerprint := models.FingerprintInput{Algorithm: input.Algorithm, Hash: input.Hash}
+	if err := models.ValidateFingerprints([]*models.FingerprintInput{&fingerprint}); err != nil {
+		return false, err
+	}
+	input.Hash = fingerprint.Hash
+
+	if strings.TrimSpace(input.Reason) == "" {
+		return false, errors.New("A reason is required")
+	}
+
+	sceneID, err := uuid.FromString(input.SceneID)
+	if err != nil {
+		return false, err
+	}
+
+	err = database.WithTransaction(ctx, func(txn database.Transaction) error {
+		qb := models.NewSceneQueryBuilder(txn.GetTx())
+
+		fingerprints, err := qb.GetFingerprints(sceneID)
+		if err != nil {
+			return err
+		}
+
+		found := false
+		for _, fingerprint := range fingerprints {
+			if fingerprint.Algorithm == input.Algorithm && fingerprint.Hash == input.Hash {
+				found = true
+			}
+		}
+		if !found {
+			return errors.New("Fingerprint not found")
+		}
+
+		report := models.NewSceneFingerprintReport(sceneID, input, getCurrentUser(ctx))
+		return qb.CreateFingerprintReport(*report)
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) RebuildSceneSearch(ctx context.Context) (bool, error) {
+	if err := validateAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	err := database.WithTransaction(ctx, func(txn database.Transaction) error {
+		qb := models.NewSceneQueryBuilder(txn.GetTx())
+		return qb.RebuildSearch()
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
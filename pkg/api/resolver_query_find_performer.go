@@ -2,19 +2,25 @@ package api
 
 import (
 	"context"
+	"time"
+
 	"github.com/gofrs/uuid"
 
+	"github.com/stashapp/stashdb/pkg/manager/edit"
 	"github.com/stashapp/stashdb/pkg/models"
 )
 
-func (r *queryResolver) FindPerformer(ctx context.Context, id string) (*models.Performer, error) {
+func (r *queryResolver) FindPerformer(ctx context.Context, id string, asOf *time.Time) (*models.Performer, error) {
 	if err := validateRead(ctx); err != nil {
 		return nil, err
 	}
 
-	qb := models.NewPerformerQueryBuilder(nil)
-
 	idUUID, _ := uuid.FromString(id)
+	if asOf != nil {
+		return edit.FindPerformerAsOf(nil, idUUID, *asOf)
+	}
+
+	qb := models.NewPerformerQueryBuilder(nil)
 	return qb.Find(idUUID)
 }
 func (r *queryResolver) QueryPerformers(ctx context.Context, performerFilter *models.PerformerFilterType, filter *models.QuerySpec) (*models.QueryPerformersResultType, error) {
@@ -28,5 +34,6 @@ func (r *queryResolver) QueryPerformers(ctx context.Context, performerFilter *mo
 	return &models.QueryPerformersResultType{
 		Performers: performers,
 		Count:      count,
+		Facets:     &models.PerformerFacets{Filter: performerFilter},
 	}, nil
 }
@@ -3,11 +3,21 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/gofrs/uuid"
 
+	"github.com/stashapp/stashdb/pkg/manager/config"
 	"github.com/stashapp/stashdb/pkg/models"
 )
 
+// maxPhashDistance is the greatest Hamming distance for which scenes are
+// matched using the PHASH segment indexes.
+const maxPhashDistance = 16
+
+// maxSceneFingerprintGroups is the greatest number of scenes that may be
+// matched by their fingerprints in one query.
+const maxSceneFingerprintGroups = 1000
+
 func (r *queryResolver) FindScene(ctx context.Context, id string) (*models.Scene, error) {
 	if err := validateRead(ctx); err != nil {
 		return nil, err
@@ -24,9 +34,18 @@ func (r *queryResolver) FindSceneByFingerprint(ctx context.Context, fingerprint
 		return nil, err
 	}
 
+	if err := models.ValidateFingerprintQueries([]*models.FingerprintQueryInput{&fingerprint}); err != nil {
+		return nil, err
+	}
+
 	qb := models.NewSceneQueryBuilder(nil)
 
-	return qb.FindByFingerprint(fingerprint.Algorithm, fingerprint.Hash)
+	scenes, err := qb.FindByFingerprintQueries([]*models.FingerprintQueryInput{&fingerprint}, config.GetFingerprintReportMargin())
+	if err != nil {
+		return nil, err
+	}
+
+	return scenes[0], nil
 }
 
 func (r *queryResolver) FindScenesByFingerprints(ctx context.Context, fingerprints []string) ([]*models.Scene, error) {
@@ -43,6 +62,74 @@ func (r *queryResolver) FindScenesByFingerprints(ctx context.Context, fingerprin
 	return qb.FindByFingerprints(fingerprints)
 }
 
+func (r *queryResolver) FindScenesBySceneFingerprints(ctx context.Context, fingerprints [][]*models.FingerprintQueryInput) ([][]*models.Scene, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(fingerprints) > maxSceneFingerprintGroups {
+		return nil, errors.New("Too many scenes.")
+	}
+
+	// match each distinct fingerprint once
+	var queries []*models.FingerprintQueryInput
+	index := make(map[models.FingerprintQueryInput]int)
+	for _, group := range fingerprints {
+		if err := models.ValidateFingerprintQueries(group); err != nil {
+			return nil, err
+		}
+
+		for _, fingerprint := range group {
+			if _, found := index[*fingerprint]; !found {
+				index[*fingerprint] = len(queries)
+				queries = append(queries, fingerprint)
+			}
+		}
+	}
+
+	qb := models.NewSceneQueryBuilder(nil)
+	matches, err := qb.FindByFingerprintQueries(queries, config.GetFingerprintReportMargin())
+	if err != nil {
+		return nil, err
+	}
+
+	// each group matches the scenes matching any of its fingerprints
+	result := make([][]*models.Scene, len(fingerprints))
+	for i, group := range fingerprints {
+		found := make(map[uuid.UUID]bool)
+		result[i] = []*models.Scene{}
+		for _, fingerprint := range group {
+			for _, scene := range matches[index[*fingerprint]] {
+				if !found[scene.ID] {
+					found[scene.ID] = true
+					result[i] = append(result[i], scene)
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (r *queryResolver) FindScenesByPhash(ctx context.Context, hash string, maxDistance int) ([]*models.Scene, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	if maxDistance < 0 || maxDistance > maxPhashDistance {
+		return nil, fmt.Errorf("max_distance must be between 0 and %d", maxPhashDistance)
+	}
+
+	phash, err := models.ParsePhash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	qb := models.NewSceneQueryBuilder(nil)
+
+	return qb.FindByPhash(phash, maxDistance)
+}
+
 func (r *queryResolver) QueryScenes(ctx context.Context, sceneFilter *models.SceneFilterType, filter *models.QuerySpec) (*models.QueryScenesResultType, error) {
 	if err := validateRead(ctx); err != nil {
 		return nil, err
@@ -54,5 +141,28 @@ func (r *queryResolver) QueryScenes(ctx context.Context, sceneFilter *models.Sce
 	return &models.QueryScenesResultType{
 		Scenes: scenes,
 		Count:  count,
+		Facets: &models.SceneFacets{Filter: sceneFilter},
+	}, nil
+}
+
+func (r *queryResolver) QueryReportedFingerprints(ctx context.Context, filter *models.QuerySpec) (*models.QueryReportedFingerprintsResultType, error) {
+	if err := validateModify(ctx); err != nil {
+		return nil, err
+	}
+
+	if filter == nil {
+		filter = &models.QuerySpec{}
+	}
+
+	qb := models.NewSceneQueryBuilder(nil)
+
+	fingerprints, count, err := qb.QueryReportedFingerprints(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.QueryReportedFingerprintsResultType{
+		Count:        count,
+		Fingerprints: fingerprints,
 	}, nil
 }
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stashdb/pkg/database"
+	"github.com/stashapp/stashdb/pkg/manager/edit"
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+func (r *queryResolver) PreviewEdit(ctx context.Context, input models.PreviewEditInput) (*models.EditPreview, error) {
+	if err := validateEdit(ctx); err != nil {
+		return nil, err
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	currentUser := getCurrentUser(ctx)
+
+	// nothing done while previewing the edit is kept
+	tx := database.DB.MustBeginTx(ctx, nil)
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var newEdit *models.Edit
+	var targetID *string
+	switch {
+	case input.Tag != nil:
+		newEdit = models.NewEdit(UUID, currentUser, models.TargetTypeEnumTag, input.Tag.Edit)
+		targetID = input.Tag.Edit.ID
+		err = buildTagEdit(ctx, tx, newEdit, *input.Tag)
+	case input.Performer != nil:
+		newEdit = models.NewEdit(UUID, currentUser, models.TargetTypeEnumPerformer, input.Performer.Edit)
+		targetID = input.Performer.Edit.ID
+		err = buildPerformerEdit(ctx, tx, newEdit, *input.Performer)
+	case input.Scene != nil:
+		newEdit = models.NewEdit(UUID, currentUser, models.TargetTypeEnumScene, input.Scene.Edit)
+		targetID = input.Scene.Edit.ID
+		err = buildSceneEdit(ctx, tx, newEdit, *input.Scene)
+	case input.Studio != nil:
+		newEdit = models.NewEdit(UUID, currentUser, models.TargetTypeEnumStudio, input.Studio.Edit)
+		targetID = input.Studio.Edit.ID
+		err = buildStudioEdit(ctx, tx, newEdit, *input.Studio)
+	default:
+		return nil, errors.New("An edit input is required")
+	}
+
+	if err != nil {
+		return &models.EditPreview{
+			Errors: []string{err.Error()},
+		}, nil
+	}
+
+	details, err := resolveEditDetails(newEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &models.EditPreview{
+		Details: details,
+		Errors:  []string{},
+	}
+
+	result, err := edit.PreviewEdit(tx, newEdit, targetID)
+	if err != nil {
+		ret.Errors = append(ret.Errors, err.Error())
+	} else {
+		ret.Result = result
+	}
+
+	return ret, nil
+}
@@ -9,6 +9,19 @@ import (
 )
 
 func (r *queryResolver) SearchPerformer(ctx context.Context, term string) ([]*models.Performer, error) {
+	results, err := r.SearchPerformerScored(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	var performers []*models.Performer
+	for _, result := range results {
+		performers = append(performers, result.Performer)
+	}
+	return performers, nil
+}
+
+func (r *queryResolver) SearchPerformerScored(ctx context.Context, term string) ([]*models.PerformerSearchResult, error) {
 	if err := validateRead(ctx); err != nil {
 		return nil, err
 	}
@@ -18,12 +31,17 @@ func (r *queryResolver) SearchPerformer(ctx context.Context, term string) ([]*mo
 	trimmedQuery := strings.TrimSpace(term)
 	performerID, err := uuid.FromString(trimmedQuery)
 	if err == nil {
-		var performers []*models.Performer
+		var results []*models.PerformerSearchResult
 		performer, err := qb.Find(performerID)
 		if performer != nil {
-			performers = append(performers, performer)
+			results = append(results, &models.PerformerSearchResult{
+				Performer:    performer,
+				Score:        1,
+				MatchedField: models.PerformerSearchFieldID,
+				MatchedValue: performer.ID.String(),
+			})
 		}
-		return performers, err
+		return results, err
 	}
 
 	return qb.SearchPerformers(term)
@@ -49,3 +67,45 @@ func (r *queryResolver) SearchScene(ctx context.Context, term string) ([]*models
 
 	return qb.SearchScenes(trimmedQuery)
 }
+
+func (r *queryResolver) SearchStudio(ctx context.Context, term string) ([]*models.Studio, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	qb := models.NewStudioQueryBuilder(nil)
+
+	trimmedQuery := strings.TrimSpace(term)
+	studioID, err := uuid.FromString(trimmedQuery)
+	if err == nil {
+		var studios []*models.Studio
+		studio, err := qb.Find(studioID)
+		if studio != nil {
+			studios = append(studios, studio)
+		}
+		return studios, err
+	}
+
+	return qb.SearchStudios(trimmedQuery)
+}
+
+func (r *queryResolver) SearchTag(ctx context.Context, term string) ([]*models.Tag, error) {
+	if err := validateRead(ctx); err != nil {
+		return nil, err
+	}
+
+	qb := models.NewTagQueryBuilder(nil)
+
+	trimmedQuery := strings.TrimSpace(term)
+	tagID, err := uuid.FromString(trimmedQuery)
+	if err == nil {
+		var tags []*models.Tag
+		tag, err := qb.Find(tagID)
+		if tag != nil {
+			tags = append(tags, tag)
+		}
+		return tags, err
+	}
+
+	return qb.SearchTags(trimmedQuery)
+}
@@ -0,0 +1,338 @@
+// +build integration
+
+package api_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+type sceneEditTestRunner struct {
+	testRunner
+}
+
+func createSceneEditTestRunner(t *testing.T) *sceneEditTestRunner {
+	return &sceneEditTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *sceneEditTestRunner) testCreateSceneEdit() {
+	performer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	tag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	title := "Title"
+	as := "Alias"
+	sceneEditDetailsInput := models.SceneEditDetailsInput{
+		Title: &title,
+		Performers: []*models.PerformerAppearanceInput{
+			{
+				PerformerID: performer.ID.String(),
+				As:          &as,
+			},
+		},
+		TagIds: []string{tag.ID.String()},
+		Fingerprints: []*models.FingerprintInput{
+			s.generateSceneFingerprint(),
+		},
+	}
+	edit, err := s.createTestSceneEdit(models.OperationEnumCreate, &sceneEditDetailsInput, nil)
+	if err == nil {
+		s.verifyCreatedSceneEdit(sceneEditDetailsInput, edit)
+	}
+}
+
+func (s *sceneEditTestRunner) verifyCreatedSceneEdit(input models.SceneEditDetailsInput, edit *models.Edit) {
+	r := s.resolver.Edit()
+
+	id, _ := r.ID(s.ctx, edit)
+	if id == "" {
+		s.t.Errorf("Expected created edit id to be non-zero")
+	}
+
+	s.verifyEditOperation(models.OperationEnumCreate.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumScene.String(), edit)
+	s.verifyEditApplication(false, edit)
+
+	sceneDetails := s.getEditSceneDetails(edit)
+
+	if *input.Title != *sceneDetails.Title {
+		s.fieldMismatch(input.Title, sceneDetails.Title, "Title")
+	}
+
+	if !reflect.DeepEqual(input.Performers, sceneDetails.AddedPerformers) {
+		s.fieldMismatch(input.Performers, sceneDetails.AddedPerformers, "AddedPerformers")
+	}
+
+	if !reflect.DeepEqual(input.TagIds, sceneDetails.AddedTags) {
+		s.fieldMismatch(input.TagIds, sceneDetails.AddedTags, "AddedTags")
+	}
+
+	if len(sceneDetails.AddedFingerprints) != 1 || sceneDetails.AddedFingerprints[0].Hash != input.Fingerprints[0].Hash {
+		s.fieldMismatch(input.Fingerprints, sceneDetails.AddedFingerprints, "AddedFingerprints")
+	}
+}
+
+func (s *sceneEditTestRunner) testModifySceneEdit() {
+	performer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	title := "title"
+	existingFingerprint := s.generateSceneFingerprint()
+	sceneCreateInput := models.SceneCreateInput{
+		Title: &title,
+		Performers: []*models.PerformerAppearanceInput{
+			{
+				PerformerID: performer.ID.String(),
+			},
+		},
+		Fingerprints: []*models.FingerprintInput{
+			existingFingerprint,
+		},
+	}
+	createdScene, err := s.createTestScene(&sceneCreateInput)
+	if err != nil {
+		return
+	}
+
+	newTitle := "newTitle"
+	as := "newAlias"
+	newFingerprint := s.generateSceneFingerprint()
+	sceneEditDetailsInput := models.SceneEditDetailsInput{
+		Title: &newTitle,
+		Performers: []*models.PerformerAppearanceInput{
+			{
+				PerformerID: performer.ID.String(),
+				As:          &as,
+			},
+		},
+		Fingerprints: []*models.FingerprintInput{
+			newFingerprint,
+		},
+	}
+	id := createdScene.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	createdUpdateEdit, err := s.createTestSceneEdit(models.OperationEnumModify, &sceneEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+
+	s.verifyUpdatedSceneEdit(sceneEditDetailsInput, existingFingerprint, createdUpdateEdit)
+}
+
+func (s *sceneEditTestRunner) verifyUpdatedSceneEdit(input models.SceneEditDetailsInput, existingFingerprint *models.FingerprintInput, edit *models.Edit) {
+	sceneDetails := s.getEditSceneDetails(edit)
+
+	s.verifyEditOperation(models.OperationEnumModify.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumScene.String(), edit)
+	s.verifyEditApplication(false, edit)
+
+	if *input.Title != *sceneDetails.Title {
+		s.fieldMismatch(*input.Title, *sceneDetails.Title, "Title")
+	}
+
+	// performer with modified alias should be both removed and added
+	if !reflect.DeepEqual(input.Performers, sceneDetails.AddedPerformers) {
+		s.fieldMismatch(input.Performers, sceneDetails.AddedPerformers, "AddedPerformers")
+	}
+
+	if len(sceneDetails.RemovedPerformers) != 1 || sceneDetails.RemovedPerformers[0].As != nil {
+		s.fieldMismatch(input.Performers[0].PerformerID, sceneDetails.RemovedPerformers, "RemovedPerformers")
+	}
+
+	if len(sceneDetails.AddedFingerprints) != 1 || sceneDetails.AddedFingerprints[0].Hash != input.Fingerprints[0].Hash {
+		s.fieldMismatch(input.Fingerprints, sceneDetails.AddedFingerprints, "AddedFingerprints")
+	}
+
+	if len(sceneDetails.RemovedFingerprints) != 1 || sceneDetails.RemovedFingerprints[0].Hash != existingFingerprint.Hash {
+		s.fieldMismatch(existingFingerprint, sceneDetails.RemovedFingerprints, "RemovedFingerprints")
+	}
+}
+
+func (s *sceneEditTestRunner) testApplyModifySceneEdit() {
+	performer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	createdScene, err := s.createTestScene(nil)
+	if err != nil {
+		return
+	}
+
+	newTitle := "newTitle2"
+	as := "newAlias2"
+	newFingerprint := s.generateSceneFingerprint()
+	sceneEditDetailsInput := models.SceneEditDetailsInput{
+		Title: &newTitle,
+		Performers: []*models.PerformerAppearanceInput{
+			{
+				PerformerID: performer.ID.String(),
+				As:          &as,
+			},
+		},
+		Fingerprints: []*models.FingerprintInput{
+			newFingerprint,
+		},
+	}
+	id := createdScene.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	createdUpdateEdit, err := s.createTestSceneEdit(models.OperationEnumModify, &sceneEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(createdUpdateEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	modifiedScene, _ := s.resolver.Query().FindScene(s.ctx, id)
+	s.verifyApplyModifySceneEdit(sceneEditDetailsInput, modifiedScene, appliedEdit)
+}
+
+func (s *sceneEditTestRunner) verifyApplyModifySceneEdit(input models.SceneEditDetailsInput, updatedScene *models.Scene, edit *models.Edit) {
+	s.verifyEditOperation(models.OperationEnumModify.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumImmediateAccepted.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumScene.String(), edit)
+	s.verifyEditApplication(true, edit)
+
+	if *input.Title != updatedScene.Title.String {
+		s.fieldMismatch(*input.Title, updatedScene.Title.String, "Title")
+	}
+
+	performers, _ := s.resolver.Scene().Performers(s.ctx, updatedScene)
+	if len(performers) != 1 || performers[0].Performer.ID.String() != input.Performers[0].PerformerID || *performers[0].As != *input.Performers[0].As {
+		s.fieldMismatch(input.Performers, performers, "Performers")
+	}
+
+	fingerprints, _ := s.resolver.Scene().Fingerprints(s.ctx, updatedScene)
+	if len(fingerprints) != 1 || fingerprints[0].Hash != input.Fingerprints[0].Hash {
+		s.fieldMismatch(input.Fingerprints, fingerprints, "Fingerprints")
+	}
+}
+
+func (s *sceneEditTestRunner) testApplyMergeSceneEdit() {
+	mergeSource, err := s.createTestScene(nil)
+	if err != nil {
+		return
+	}
+	mergeTarget, err := s.createTestScene(nil)
+	if err != nil {
+		return
+	}
+
+	sourceFingerprints, _ := s.resolver.Scene().Fingerprints(s.ctx, mergeSource)
+	targetFingerprints, _ := s.resolver.Scene().Fingerprints(s.ctx, mergeTarget)
+
+	id := mergeTarget.ID.String()
+	mergeSources := []string{mergeSource.ID.String()}
+	editInput := models.EditInput{
+		Operation:      models.OperationEnumMerge,
+		ID:             &id,
+		MergeSourceIds: mergeSources,
+	}
+
+	mergeEdit, err := s.createTestSceneEdit(models.OperationEnumMerge, &models.SceneEditDetailsInput{}, &editInput)
+	if err != nil {
+		return
+	}
+
+	appliedMerge, err := s.applyEdit(mergeEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	s.verifyAppliedMergeSceneEdit(appliedMerge, append(targetFingerprints, sourceFingerprints...))
+}
+
+func (s *sceneEditTestRunner) verifyAppliedMergeSceneEdit(edit *models.Edit, expectedFingerprints []*models.Fingerprint) {
+	s.verifyEditOperation(models.OperationEnumMerge.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumImmediateAccepted.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumScene.String(), edit)
+	s.verifyEditApplication(true, edit)
+
+	merges, _ := s.resolver.Edit().MergeSources(s.ctx, edit)
+	for i := range merges {
+		scene := merges[i].(*models.Scene)
+		if scene.Deleted != true {
+			s.fieldMismatch(scene.Deleted, true, "Deleted")
+		}
+	}
+
+	// use a new loader to avoid fingerprints cached before the merge
+	target := s.getEditSceneTarget(edit)
+	sqb := models.NewSceneQueryBuilder(nil)
+	fingerprints, _ := sqb.GetFingerprints(target.ID)
+
+	hashes := map[string]bool{}
+	for _, fingerprint := range fingerprints {
+		hashes[fingerprint.Hash] = true
+	}
+	for _, fingerprint := range expectedFingerprints {
+		if !hashes[fingerprint.Hash] {
+			s.fieldMismatch(expectedFingerprints, fingerprints, "Fingerprints")
+			break
+		}
+	}
+}
+
+func (s *sceneEditTestRunner) testSceneEditWithoutID() {
+	for _, operation := range []models.OperationEnum{models.OperationEnumModify, models.OperationEnumDestroy} {
+		input := models.SceneEditInput{
+			Edit: &models.EditInput{
+				Operation: operation,
+			},
+			Details: &models.SceneEditDetailsInput{},
+		}
+
+		if _, err := s.resolver.Mutation().SceneEdit(s.ctx, input); err == nil {
+			s.t.Errorf("Expected error for %s edit without id", operation)
+		}
+	}
+}
+
+func TestCreateSceneEdit(t *testing.T) {
+	pt := createSceneEditTestRunner(t)
+	pt.testCreateSceneEdit()
+}
+
+func TestModifySceneEdit(t *testing.T) {
+	pt := createSceneEditTestRunner(t)
+	pt.testModifySceneEdit()
+}
+
+func TestApplyModifySceneEdit(t *testing.T) {
+	pt := createSceneEditTestRunner(t)
+	pt.testApplyModifySceneEdit()
+}
+
+func TestApplyMergeSceneEdit(t *testing.T) {
+	pt := createSceneEditTestRunner(t)
+	pt.testApplyMergeSceneEdit()
+}
+
+func TestSceneEditWithoutID(t *testing.T) {
+	pt := createSceneEditTestRunner(t)
+	pt.testSceneEditWithoutID()
+}
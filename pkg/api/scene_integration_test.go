@@ -4,7 +4,10 @@ package api_test
 
 import (
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stashapp/stashdb/pkg/api"
 	"github.com/stashapp/stashdb/pkg/models"
@@ -274,6 +277,493 @@ func (s *sceneTestRunner) testFindScenesByFingerprints() {
 	}
 }
 
+func (s *sceneTestRunner) testFindScenesBySceneFingerprints() {
+	fingerprint1 := s.generateSceneFingerprint()
+	fingerprint2 := s.generateSceneFingerprint()
+	scene1, err := s.createTestScene(&models.SceneCreateInput{
+		Fingerprints: []*models.FingerprintInput{fingerprint1, fingerprint2},
+	})
+	if err != nil {
+		return
+	}
+
+	fingerprint3 := s.generateSceneFingerprint()
+	scene2, err := s.createTestScene(&models.SceneCreateInput{
+		Fingerprints: []*models.FingerprintInput{fingerprint3},
+	})
+	if err != nil {
+		return
+	}
+
+	query := func(fingerprint *models.FingerprintInput) *models.FingerprintQueryInput {
+		return &models.FingerprintQueryInput{
+			Algorithm: fingerprint.Algorithm,
+			Hash:      fingerprint.Hash,
+		}
+	}
+
+	input := [][]*models.FingerprintQueryInput{
+		{query(fingerprint1)},
+		{query(fingerprint2), query(fingerprint3), query(fingerprint1)},
+		// the algorithm must match as well as the hash
+		{{Algorithm: models.FingerprintAlgorithmOshash, Hash: fingerprint1.Hash}},
+	}
+
+	results, err := s.resolver.Query().FindScenesBySceneFingerprints(s.ctx, input)
+	if err != nil {
+		s.t.Errorf("Error finding scenes: %s", err.Error())
+		return
+	}
+
+	expected := [][]*models.Scene{
+		{scene1},
+		{scene1, scene2},
+		{},
+	}
+
+	if len(results) != len(expected) {
+		s.fieldMismatch(len(expected), len(results), "Results")
+		return
+	}
+
+	for i, scenes := range results {
+		var expectedIDs, ids []string
+		for _, scene := range expected[i] {
+			expectedIDs = append(expectedIDs, scene.ID.String())
+		}
+		for _, scene := range scenes {
+			ids = append(ids, scene.ID.String())
+		}
+
+		if !reflect.DeepEqual(expectedIDs, ids) {
+			s.fieldMismatch(expectedIDs, ids, "Scenes")
+		}
+	}
+}
+
+func (s *sceneTestRunner) testFingerprintDurations() {
+	prefix := "testFingerprintDurations_"
+	title := prefix + "scene"
+
+	fingerprint1 := s.generateSceneFingerprint()
+	fingerprint2 := s.generateSceneFingerprint()
+	fingerprint2.Duration = 1240
+	fingerprint3 := s.generateSceneFingerprint()
+	fingerprint3.Duration = 1300
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Title:        &title,
+		Fingerprints: []*models.FingerprintInput{fingerprint1, fingerprint2, fingerprint3},
+	})
+	if err != nil {
+		return
+	}
+
+	consensus, err := s.resolver.Scene().FingerprintDuration(s.ctx, scene)
+	if err != nil {
+		s.t.Errorf("Error getting fingerprint duration: %s", err.Error())
+		return
+	}
+	if consensus == nil || *consensus != 1240 {
+		s.fieldMismatch(1240, consensus, "FingerprintDuration")
+	}
+
+	spread, err := s.resolver.Scene().FingerprintDurationSpread(s.ctx, scene)
+	if err != nil {
+		s.t.Errorf("Error getting fingerprint duration spread: %s", err.Error())
+		return
+	}
+	if spread == nil || *spread != 66 {
+		s.fieldMismatch(66, spread, "FingerprintDurationSpread")
+	}
+
+	disputed, _ := s.resolver.Scene().DurationDisputed(s.ctx, scene)
+	if !disputed {
+		s.fieldMismatch(true, disputed, "DurationDisputed")
+	}
+
+	// only matches within the tolerance of the duration are returned
+	duration := 1250
+	tolerance := 10
+	query := models.FingerprintQueryInput{
+		Algorithm:         fingerprint2.Algorithm,
+		Hash:              fingerprint2.Hash,
+		Duration:          &duration,
+		DurationTolerance: &tolerance,
+	}
+
+	scenes, err := s.resolver.Query().FindSceneByFingerprint(s.ctx, query)
+	if err != nil {
+		s.t.Errorf("Error finding scene by fingerprint: %s", err.Error())
+		return
+	}
+	if len(scenes) != 1 {
+		s.fieldMismatch(1, len(scenes), "Scenes")
+	}
+
+	query.Hash = fingerprint1.Hash
+	scenes, err = s.resolver.Query().FindSceneByFingerprint(s.ctx, query)
+	if err != nil {
+		s.t.Errorf("Error finding scene by fingerprint: %s", err.Error())
+		return
+	}
+	if len(scenes) != 0 {
+		s.fieldMismatch(0, len(scenes), "Scenes")
+	}
+
+	// filter by the spread of the durations
+	undisputed, err := s.createTestScene(&models.SceneCreateInput{
+		Title:        &title,
+		Fingerprints: []*models.FingerprintInput{s.generateSceneFingerprint()},
+	})
+	if err != nil {
+		return
+	}
+
+	filter := models.SceneFilterType{
+		Title: &prefix,
+		FingerprintDurationSpread: &models.IntCriterionInput{
+			Value:    10,
+			Modifier: models.CriterionModifierGreaterThan,
+		},
+	}
+	s.verifyQueryScenesResult(filter, []string{scene.ID.String()})
+
+	filter.FingerprintDurationSpread.Modifier = models.CriterionModifierLessThan
+	s.verifyQueryScenesResult(filter, []string{undisputed.ID.String()})
+}
+
+func (s *sceneTestRunner) createPhashScene(phash int64) (*models.Scene, error) {
+	s.t.Helper()
+	input := models.SceneCreateInput{
+		Fingerprints: []*models.FingerprintInput{
+			{
+				Algorithm: models.FingerprintAlgorithmPhash,
+				Hash:      models.FormatPhash(phash),
+				Duration:  1234,
+			},
+		},
+	}
+
+	return s.createTestScene(&input)
+}
+
+func (s *sceneTestRunner) verifyFindScenesByPhash(hash string, maxDistance int, expected []*models.Scene) {
+	s.t.Helper()
+	scenes, err := s.resolver.Query().FindScenesByPhash(s.ctx, hash, maxDistance)
+	if err != nil {
+		s.t.Errorf("Error finding scenes: %s", err.Error())
+		return
+	}
+
+	var expectedIDs, ids []string
+	for _, scene := range expected {
+		expectedIDs = append(expectedIDs, scene.ID.String())
+	}
+	for _, scene := range scenes {
+		ids = append(ids, scene.ID.String())
+	}
+
+	if !reflect.DeepEqual(expectedIDs, ids) {
+		s.fieldMismatch(expectedIDs, ids, "Scenes")
+	}
+}
+
+func (s *sceneTestRunner) testFindScenesByPhash() {
+	phash := time.Now().UnixNano()
+
+	exactScene, err := s.createPhashScene(phash)
+	if err != nil {
+		return
+	}
+	// two bits differ, in different segments
+	nearScene, err := s.createPhashScene(phash ^ 0x18000)
+	if err != nil {
+		return
+	}
+	nearScene2, err := s.createPhashScene(phash ^ 0x1)
+	if err != nil {
+		return
+	}
+	// every bit of the lowest segment differs
+	if _, err := s.createPhashScene(phash ^ 0xffff); err != nil {
+		return
+	}
+
+	hash := strings.ToUpper(models.FormatPhash(phash))
+	s.verifyFindScenesByPhash(hash, 0, []*models.Scene{exactScene})
+	s.verifyFindScenesByPhash(hash, 2, []*models.Scene{exactScene, nearScene2, nearScene})
+
+	if _, err := s.resolver.Query().FindScenesByPhash(s.ctx, hash, 17); err == nil {
+		s.t.Error("Expected error finding scenes beyond the maximum distance")
+	}
+}
+
+func (s *sceneTestRunner) submitFingerprint(runner *testRunner, scene *models.Scene, fingerprint *models.FingerprintInput, unmatch bool) {
+	s.t.Helper()
+	input := models.FingerprintSubmission{
+		SceneID:     scene.ID.String(),
+		Fingerprint: fingerprint,
+		Unmatch:     &unmatch,
+	}
+
+	if _, err := runner.resolver.Mutation().SubmitFingerprint(runner.ctx, input); err != nil {
+		s.t.Errorf("Error submitting fingerprint: %s", err.Error())
+	}
+}
+
+func (s *sceneTestRunner) findFingerprint(scene *models.Scene, hash string) *models.Fingerprint {
+	s.t.Helper()
+
+	// use a new runner so that the fingerprints are not cached
+	runner := asModify(s.t)
+	fingerprints, err := runner.resolver.Scene().Fingerprints(runner.ctx, scene)
+	if err != nil {
+		s.t.Errorf("Error getting fingerprints: %s", err.Error())
+		return nil
+	}
+
+	for _, fingerprint := range fingerprints {
+		if fingerprint.Hash == hash {
+			return fingerprint
+		}
+	}
+	return nil
+}
+
+func (s *sceneTestRunner) testSubmitFingerprint() {
+	scene, err := s.createTestScene(nil)
+	if err != nil {
+		return
+	}
+
+	roles := []models.RoleEnum{models.RoleEnumEdit}
+	submitter := s.createUserRunner(roles)
+	submitter2 := s.createUserRunner(roles)
+	fingerprint := s.generateSceneFingerprint()
+
+	s.submitFingerprint(submitter, scene, fingerprint, false)
+	s.submitFingerprint(submitter2, scene, fingerprint, false)
+	// submitting again is not counted twice
+	s.submitFingerprint(submitter2, scene, fingerprint, false)
+
+	submitted := s.findFingerprint(scene, fingerprint.Hash)
+	if submitted == nil {
+		s.t.Error("Submitted fingerprint not found")
+		return
+	}
+	if submitted.Submissions != 2 {
+		s.fieldMismatch(2, submitted.Submissions, "Submissions")
+	}
+	if submitted.Updated.Before(submitted.Created) {
+		s.fieldMismatch(submitted.Created, submitted.Updated, "Updated")
+	}
+
+	s.submitFingerprint(submitter, scene, fingerprint, true)
+	if submitted = s.findFingerprint(scene, fingerprint.Hash); submitted == nil || submitted.Submissions != 1 {
+		s.fieldMismatch(1, submitted, "Submissions")
+	}
+
+	// the fingerprint is removed along with the last submission
+	s.submitFingerprint(submitter2, scene, fingerprint, true)
+	if submitted = s.findFingerprint(scene, fingerprint.Hash); submitted != nil {
+		s.t.Error("Fingerprint not removed with its last submission")
+	}
+
+	if _, err := s.resolver.Mutation().SubmitFingerprint(asRead(s.t).ctx, models.FingerprintSubmission{}); err != api.ErrUnauthorized {
+		s.t.Errorf("SubmitFingerprint: got %v want %v", err, api.ErrUnauthorized)
+	}
+}
+
+func (s *sceneTestRunner) reportFingerprint(runner *testRunner, scene *models.Scene, fingerprint *models.FingerprintInput, reason string) {
+	s.t.Helper()
+	input := models.FingerprintReportInput{
+		SceneID:   scene.ID.String(),
+		Algorithm: fingerprint.Algorithm,
+		Hash:      fingerprint.Hash,
+		Reason:    reason,
+	}
+
+	if _, err := runner.resolver.Mutation().ReportFingerprint(runner.ctx, input); err != nil {
+		s.t.Errorf("Error reporting fingerprint: %s", err.Error())
+	}
+}
+
+func (s *sceneTestRunner) verifyFingerprintMatched(fingerprint *models.FingerprintInput, expected bool) {
+	s.t.Helper()
+	query := models.FingerprintQueryInput{
+		Algorithm: fingerprint.Algorithm,
+		Hash:      fingerprint.Hash,
+	}
+
+	scenes, err := s.resolver.Query().FindSceneByFingerprint(s.ctx, query)
+	if err != nil {
+		s.t.Errorf("Error finding scene by fingerprint: %s", err.Error())
+		return
+	}
+
+	if matched := len(scenes) > 0; matched != expected {
+		s.fieldMismatch(expected, matched, "Matched")
+	}
+}
+
+func (s *sceneTestRunner) testReportFingerprint() {
+	fingerprint := s.generateSceneFingerprint()
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Fingerprints: []*models.FingerprintInput{fingerprint},
+	})
+	if err != nil {
+		return
+	}
+
+	roles := []models.RoleEnum{models.RoleEnumEdit}
+	reporter := s.createUserRunner(roles)
+	reporter2 := s.createUserRunner(roles)
+
+	s.reportFingerprint(reporter, scene, fingerprint, "first reason")
+	// reporting again replaces the reason of the report
+	s.reportFingerprint(reporter, scene, fingerprint, "second reason")
+
+	// reports may exceed submissions by one by default
+	s.verifyFingerprintMatched(fingerprint, true)
+
+	result, err := s.resolver.Query().QueryReportedFingerprints(s.ctx, &models.QuerySpec{})
+	if err != nil {
+		s.t.Errorf("Error querying reported fingerprints: %s", err.Error())
+		return
+	}
+
+	var reported *models.ReportedFingerprint
+	for _, r := range result.Fingerprints {
+		if r.SceneID == scene.ID && r.Fingerprint.Hash == fingerprint.Hash {
+			reported = r
+		}
+	}
+	if reported == nil {
+		s.t.Error("Reported fingerprint not found")
+		return
+	}
+	if reported.ReportCount != 1 {
+		s.fieldMismatch(1, reported.ReportCount, "ReportCount")
+	}
+
+	reports, err := s.resolver.ReportedFingerprint().Reports(s.ctx, reported)
+	if err != nil {
+		s.t.Errorf("Error getting reports: %s", err.Error())
+		return
+	}
+	if len(reports) != 1 || reports[0].Reason != "second reason" {
+		s.fieldMismatch("second reason", reports, "Reports")
+	}
+
+	// fingerprints are no longer matched once the margin is exceeded
+	s.reportFingerprint(reporter2, scene, fingerprint, "reason")
+	s.verifyFingerprintMatched(fingerprint, false)
+
+	// and are matched again once submitted
+	s.submitFingerprint(s.createUserRunner(roles), scene, fingerprint, false)
+	s.verifyFingerprintMatched(fingerprint, true)
+
+	input := models.FingerprintReportInput{
+		SceneID:   scene.ID.String(),
+		Algorithm: fingerprint.Algorithm,
+		Hash:      s.generateSceneFingerprint().Hash,
+		Reason:    "reason",
+	}
+	if _, err := s.resolver.Mutation().ReportFingerprint(reporter.ctx, input); err == nil {
+		s.t.Error("Expected error reporting a fingerprint not of the scene")
+	}
+
+	if _, err := s.resolver.Mutation().ReportFingerprint(asRead(s.t).ctx, input); err != api.ErrUnauthorized {
+		s.t.Errorf("ReportFingerprint: got %v want %v", err, api.ErrUnauthorized)
+	}
+
+	if _, err := s.resolver.Query().QueryReportedFingerprints(reporter.ctx, nil); err != api.ErrUnauthorized {
+		s.t.Errorf("QueryReportedFingerprints: got %v want %v", err, api.ErrUnauthorized)
+	}
+}
+
+func (s *sceneTestRunner) testDestroyFingerprint() {
+	scene, err := s.createTestScene(nil)
+	if err != nil {
+		return
+	}
+
+	fingerprint := s.generateSceneFingerprint()
+	s.submitFingerprint(s.createUserRunner([]models.RoleEnum{models.RoleEnumEdit}), scene, fingerprint, false)
+
+	input := models.FingerprintDestroyInput{
+		SceneID:   scene.ID.String(),
+		Algorithm: fingerprint.Algorithm,
+		Hash:      fingerprint.Hash,
+	}
+	if _, err := s.resolver.Mutation().FingerprintDestroy(s.ctx, input); err != nil {
+		s.t.Errorf("Error destroying fingerprint: %s", err.Error())
+		return
+	}
+
+	if s.findFingerprint(scene, fingerprint.Hash) != nil {
+		s.t.Error("Fingerprint not destroyed")
+	}
+
+	// submitting the fingerprint again starts a new count
+	s.submitFingerprint(s.createUserRunner([]models.RoleEnum{models.RoleEnumEdit}), scene, fingerprint, false)
+	if submitted := s.findFingerprint(scene, fingerprint.Hash); submitted == nil || submitted.Submissions != 1 {
+		s.fieldMismatch(1, submitted, "Submissions")
+	}
+}
+
+func (s *sceneTestRunner) updateSceneFingerprints(scene *models.Scene, fingerprints []*models.FingerprintInput) {
+	s.t.Helper()
+	input := models.SceneUpdateInput{
+		ID:           scene.ID.String(),
+		Fingerprints: fingerprints,
+	}
+
+	if _, err := s.resolver.Mutation().SceneUpdate(s.ctx, input); err != nil {
+		s.t.Errorf("Error updating scene: %s", err.Error())
+	}
+}
+
+func (s *sceneTestRunner) testUpdateFingerprintSubmissions() {
+	kept := s.generateSceneFingerprint()
+	removed := s.generateSceneFingerprint()
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Fingerprints: []*models.FingerprintInput{kept, removed},
+	})
+	if err != nil {
+		return
+	}
+
+	submitter := s.createUserRunner([]models.RoleEnum{models.RoleEnumEdit})
+	s.submitFingerprint(submitter, scene, kept, false)
+	s.submitFingerprint(submitter, scene, removed, false)
+	s.reportFingerprint(submitter, scene, removed, "reason")
+
+	// the submissions of the remaining fingerprints are kept
+	s.updateSceneFingerprints(scene, []*models.FingerprintInput{kept})
+	if submitted := s.findFingerprint(scene, kept.Hash); submitted == nil || submitted.Submissions != 1 {
+		s.fieldMismatch(1, submitted, "Submissions")
+	}
+
+	// fingerprints added again do not inherit the removed submissions
+	s.updateSceneFingerprints(scene, []*models.FingerprintInput{kept, removed})
+	if submitted := s.findFingerprint(scene, removed.Hash); submitted == nil || submitted.Submissions != 0 {
+		s.fieldMismatch(0, submitted, "Submissions")
+	}
+
+	// or their reports
+	result, err := s.resolver.Query().QueryReportedFingerprints(s.ctx, &models.QuerySpec{})
+	if err != nil {
+		s.t.Errorf("Error querying reported fingerprints: %s", err.Error())
+		return
+	}
+	for _, r := range result.Fingerprints {
+		if r.SceneID == scene.ID && r.Fingerprint.Hash == removed.Hash {
+			s.fieldMismatch(0, r.ReportCount, "ReportCount")
+		}
+	}
+}
+
 func (s *sceneTestRunner) testUpdateScene() {
 	title := "Title"
 	details := "Details"
@@ -755,6 +1245,152 @@ func (s *sceneTestRunner) testQueryScenesByTag() {
 	s.verifyInvalidModifier(filter)
 }
 
+func (s *sceneTestRunner) testQueryScenesFacets() {
+	studio1, _ := s.createTestStudio(nil)
+	studio2, _ := s.createTestStudio(nil)
+	tag1, _ := s.createTestTag(nil)
+	tag2, _ := s.createTestTag(nil)
+
+	studio1ID := studio1.ID.String()
+	studio2ID := studio2.ID.String()
+	tag1ID := tag1.ID.String()
+	tag2ID := tag2.ID.String()
+
+	prefix := "testQueryScenesFacets_"
+	scenes := []struct {
+		studioID string
+		tagIDs   []string
+		date     string
+	}{
+		{studio1ID, []string{tag1ID, tag2ID}, "2019-01-01"},
+		{studio1ID, []string{tag1ID}, "2019-06-01"},
+		{studio2ID, []string{tag1ID}, "2020-01-01"},
+		{studio2ID, nil, "2021-01-01"},
+	}
+
+	for i, scene := range scenes {
+		title := prefix + strconv.Itoa(i)
+		studioID := scene.studioID
+		date := scene.date
+		input := models.SceneCreateInput{
+			Title:    &title,
+			StudioID: &studioID,
+			TagIds:   scene.tagIDs,
+			Date:     &date,
+		}
+
+		if _, err := s.createTestScene(&input); err != nil {
+			return
+		}
+	}
+
+	titleSearch := prefix
+	filter := models.SceneFilterType{
+		Title: &titleSearch,
+	}
+
+	results, err := s.resolver.Query().QueryScenes(s.ctx, &filter, nil)
+	if err != nil {
+		s.t.Errorf("Error querying scenes: %s", err.Error())
+		return
+	}
+
+	s.verifySceneFacets(results.Facets, map[string]int{
+		studio1ID: 2,
+		studio2ID: 2,
+	}, map[string]int{
+		tag1ID: 3,
+		tag2ID: 1,
+	}, map[int]int{
+		2019: 2,
+		2020: 1,
+		2021: 1,
+	})
+
+	// facets are counted from the filtered scenes only
+	filter.Tags = &models.MultiIDCriterionInput{
+		Value:    []string{tag1ID},
+		Modifier: models.CriterionModifierIncludes,
+	}
+
+	results, err = s.resolver.Query().QueryScenes(s.ctx, &filter, nil)
+	if err != nil {
+		s.t.Errorf("Error querying scenes: %s", err.Error())
+		return
+	}
+
+	s.verifySceneFacets(results.Facets, map[string]int{
+		studio1ID: 2,
+		studio2ID: 1,
+	}, map[string]int{
+		tag1ID: 3,
+		tag2ID: 1,
+	}, map[int]int{
+		2019: 2,
+		2020: 1,
+	})
+
+	// limit the studios to the most frequent
+	limit := 1
+	studios, err := s.resolver.SceneFacets().Studios(s.ctx, results.Facets, &limit)
+	if err != nil {
+		s.t.Errorf("Error getting studio facets: %s", err.Error())
+		return
+	}
+
+	if len(studios) != 1 || studios[0].StudioID != studio1.ID {
+		s.t.Errorf("Studio facets: got %v want %s", studios, studio1ID)
+	}
+}
+
+func (s *sceneTestRunner) verifySceneFacets(facets *models.SceneFacets, studios map[string]int, tags map[string]int, years map[int]int) {
+	s.t.Helper()
+
+	r := s.resolver.SceneFacets()
+
+	studioFacets, err := r.Studios(s.ctx, facets, nil)
+	if err != nil {
+		s.t.Errorf("Error getting studio facets: %s", err.Error())
+		return
+	}
+
+	got := make(map[string]int)
+	for _, facet := range studioFacets {
+		got[facet.StudioID.String()] = facet.Count
+	}
+	if !reflect.DeepEqual(got, studios) {
+		s.t.Errorf("Studio facets: got %v want %v", got, studios)
+	}
+
+	tagFacets, err := r.Tags(s.ctx, facets, nil)
+	if err != nil {
+		s.t.Errorf("Error getting tag facets: %s", err.Error())
+		return
+	}
+
+	got = make(map[string]int)
+	for _, facet := range tagFacets {
+		got[facet.TagID.String()] = facet.Count
+	}
+	if !reflect.DeepEqual(got, tags) {
+		s.t.Errorf("Tag facets: got %v want %v", got, tags)
+	}
+
+	yearFacets, err := r.Years(s.ctx, facets)
+	if err != nil {
+		s.t.Errorf("Error getting year facets: %s", err.Error())
+		return
+	}
+
+	gotYears := make(map[int]int)
+	for _, facet := range yearFacets {
+		gotYears[facet.Year] = facet.Count
+	}
+	if !reflect.DeepEqual(gotYears, years) {
+		s.t.Errorf("Year facets: got %v want %v", gotYears, years)
+	}
+}
+
 func (s *sceneTestRunner) testUnauthorisedSceneModify() {
 	// test each api interface - all require modify so all should fail
 	_, err := s.resolver.Mutation().SceneCreate(s.ctx, models.SceneCreateInput{})
@@ -806,6 +1442,41 @@ func TestFindScenesByFingerprints(t *testing.T) {
 	pt.testFindScenesByFingerprints()
 }
 
+func TestFindScenesBySceneFingerprints(t *testing.T) {
+	pt := createSceneTestRunner(t)
+	pt.testFindScenesBySceneFingerprints()
+}
+
+func TestFindScenesByPhash(t *testing.T) {
+	pt := createSceneTestRunner(t)
+	pt.testFindScenesByPhash()
+}
+
+func TestFingerprintDurations(t *testing.T) {
+	pt := createSceneTestRunner(t)
+	pt.testFingerprintDurations()
+}
+
+func TestSubmitFingerprint(t *testing.T) {
+	pt := createSceneTestRunner(t)
+	pt.testSubmitFingerprint()
+}
+
+func TestReportFingerprint(t *testing.T) {
+	pt := createSceneTestRunner(t)
+	pt.testReportFingerprint()
+}
+
+func TestUpdateFingerprintSubmissions(t *testing.T) {
+	pt := createSceneTestRunner(t)
+	pt.testUpdateFingerprintSubmissions()
+}
+
+func TestDestroyFingerprint(t *testing.T) {
+	pt := createSceneTestRunner(t)
+	pt.testDestroyFingerprint()
+}
+
 func TestUpdateScene(t *testing.T) {
 	pt := createSceneTestRunner(t)
 	pt.testUpdateScene()
@@ -834,6 +1505,11 @@ func TestQueryScenesByTag(t *testing.T) {
 	pt.testQueryScenesByTag()
 }
 
+func TestQueryScenesFacets(t *testing.T) {
+	pt := createSceneTestRunner(t)
+	pt.testQueryScenesFacets()
+}
+
 func TestUnauthorisedSceneModify(t *testing.T) {
 	pt := &sceneTestRunner{
 		testRunner: *asRead(t),
@@ -3,8 +3,12 @@
 package api_test
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/gofrs/uuid"
+
 	"github.com/stashapp/stashdb/pkg/api"
 	"github.com/stashapp/stashdb/pkg/models"
 )
@@ -67,6 +71,118 @@ func (s *searchTestRunner) testSearchPerformerByID() {
 	}
 }
 
+func (s *searchTestRunner) testSearchPerformerByAlias() {
+	alias := s.generatePerformerName() + " alias"
+	createdPerformer, err := s.createTestPerformer(&models.PerformerCreateInput{
+		Name:    s.generatePerformerName(),
+		Aliases: []string{alias},
+	})
+	if err != nil {
+		return
+	}
+
+	performers, err := s.resolver.Query().SearchPerformerScored(s.ctx, alias)
+	if err != nil {
+		s.t.Errorf("Error finding performer: %s", err.Error())
+		return
+	}
+
+	if len(performers) == 0 {
+		s.t.Error("Did not find performer by alias search")
+		return
+	}
+
+	result := performers[0]
+	if createdPerformer.ID != result.Performer.ID {
+		s.fieldMismatch(createdPerformer.ID, result.Performer.ID, "ID")
+	}
+	if result.MatchedField != models.PerformerSearchFieldAlias {
+		s.fieldMismatch(models.PerformerSearchFieldAlias, result.MatchedField, "MatchedField")
+	}
+	if result.MatchedValue != alias {
+		s.fieldMismatch(alias, result.MatchedValue, "MatchedValue")
+	}
+	if result.Score != 1 {
+		s.fieldMismatch(1, result.Score, "Score")
+	}
+}
+
+func (s *searchTestRunner) testSearchPerformerByDisambiguation() {
+	name := s.generatePerformerName()
+	brunette := "brunette"
+	blonde := "blonde"
+	if _, err := s.createTestPerformer(&models.PerformerCreateInput{
+		Name:           name,
+		Disambiguation: &brunette,
+	}); err != nil {
+		return
+	}
+	createdPerformer, err := s.createTestPerformer(&models.PerformerCreateInput{
+		Name:           name,
+		Disambiguation: &blonde,
+	})
+	if err != nil {
+		return
+	}
+
+	performers, err := s.resolver.Query().SearchPerformerScored(s.ctx, name+" "+blonde)
+	if err != nil {
+		s.t.Errorf("Error finding performer: %s", err.Error())
+		return
+	}
+
+	if len(performers) == 0 {
+		s.t.Error("Did not find performer by disambiguation search")
+		return
+	}
+
+	// the disambiguation ranks the performers of the same name
+	result := performers[0]
+	if createdPerformer.ID != result.Performer.ID {
+		s.fieldMismatch(createdPerformer.ID, result.Performer.ID, "ID")
+	}
+	if result.MatchedField != models.PerformerSearchFieldDisambiguation {
+		s.fieldMismatch(models.PerformerSearchFieldDisambiguation, result.MatchedField, "MatchedField")
+	}
+	for _, other := range performers[1:] {
+		if other.Score > result.Score {
+			s.t.Errorf("Results not ranked by score: %v, %v", result.Score, other.Score)
+		}
+	}
+}
+
+func (s *searchTestRunner) testSearchPerformerDeleted() {
+	performer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	id := performer.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumDestroy,
+		ID:        &id,
+	}
+	destroyEdit, err := s.createTestPerformerEdit(models.OperationEnumDestroy, &models.PerformerEditDetailsInput{}, &editInput)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(destroyEdit.ID.String()); err != nil {
+		return
+	}
+
+	performers, err := s.resolver.Query().SearchPerformer(s.ctx, performer.Name)
+	if err != nil {
+		s.t.Errorf("Error finding performer: %s", err.Error())
+		return
+	}
+
+	for _, result := range performers {
+		if result.ID == performer.ID {
+			s.t.Error("Found deleted performer by name search")
+		}
+	}
+}
+
 func (s *searchTestRunner) testSearchSceneByTerm() {
 	createdStudio, err := s.createTestStudio(nil)
 	if err != nil {
@@ -127,6 +243,450 @@ func (s *searchTestRunner) testSearchSceneByID() {
 		s.fieldMismatch(createdScene.ID, scenes[0].ID, "ID")
 	}
 }
+func (s *searchTestRunner) testSearchStudioByName() {
+	parent, err := s.createTestStudio(&models.StudioCreateInput{
+		Name: s.generateStudioName() + " network",
+	})
+	if err != nil {
+		return
+	}
+	parentID := parent.ID.String()
+	child, err := s.createTestStudio(&models.StudioCreateInput{
+		Name:     s.generateStudioName(),
+		ParentID: &parentID,
+	})
+	if err != nil {
+		return
+	}
+
+	studios, err := s.resolver.Query().SearchStudio(s.ctx, parent.Name)
+	if err != nil {
+		s.t.Errorf("Error finding studio: %s", err.Error())
+		return
+	}
+
+	var ids []uuid.UUID
+	for _, studio := range studios {
+		ids = append(ids, studio.ID)
+	}
+
+	// the parent ranks above the studios matched by its name
+	if len(ids) < 2 || ids[0] != parent.ID {
+		s.fieldMismatch(parent.ID, ids, "Studios")
+		return
+	}
+	found := false
+	for _, id := range ids {
+		found = found || id == child.ID
+	}
+	if !found {
+		s.t.Error("Did not find child studio by parent name search")
+	}
+}
+
+func (s *searchTestRunner) testSearchTagByAlias() {
+	alias := s.generateTagName() + " alias"
+	createdTag, err := s.createTestTag(&models.TagCreateInput{
+		Name:    s.generateTagName(),
+		Aliases: []string{alias},
+	})
+	if err != nil {
+		return
+	}
+
+	tags, err := s.resolver.Query().SearchTag(s.ctx, alias)
+	if err != nil {
+		s.t.Errorf("Error finding tag: %s", err.Error())
+		return
+	}
+
+	if len(tags) == 0 {
+		s.t.Error("Did not find tag by alias search")
+		return
+	}
+
+	if createdTag.ID != tags[0].ID {
+		s.fieldMismatch(createdTag.ID, tags[0].ID, "ID")
+	}
+}
+
+func (s *searchTestRunner) testSearchTagByDescription() {
+	description := "Scenes featuring xylophones"
+	createdTag, err := s.createTestTag(&models.TagCreateInput{
+		Name:        s.generateTagName(),
+		Description: &description,
+	})
+	if err != nil {
+		return
+	}
+
+	tags, err := s.resolver.Query().SearchTag(s.ctx, "xylophone")
+	if err != nil {
+		s.t.Errorf("Error finding tag: %s", err.Error())
+		return
+	}
+
+	found := false
+	for _, tag := range tags {
+		found = found || tag.ID == createdTag.ID
+	}
+	if !found {
+		s.t.Error("Did not find tag by description search")
+	}
+}
+
+func (s *searchTestRunner) testSearchTagNameBeforeDescription() {
+	description := "Scenes featuring glockenspiels"
+	describedTag, err := s.createTestTag(&models.TagCreateInput{
+		Name:        s.generateTagName(),
+		Description: &description,
+	})
+	if err != nil {
+		return
+	}
+	namedTag, err := s.createTestTag(&models.TagCreateInput{
+		Name: "Glockenspiel " + s.generateTagName(),
+	})
+	if err != nil {
+		return
+	}
+
+	tags, err := s.resolver.Query().SearchTag(s.ctx, "glockenspiel")
+	if err != nil {
+		s.t.Errorf("Error finding tag: %s", err.Error())
+		return
+	}
+
+	var ids []uuid.UUID
+	for _, tag := range tags {
+		ids = append(ids, tag.ID)
+	}
+
+	// name matches rank above description matches, whatever their scores
+	expected := []uuid.UUID{namedTag.ID, describedTag.ID}
+	if !reflect.DeepEqual(expected, ids) {
+		s.fieldMismatch(expected, ids, "Tags")
+	}
+}
+
+func (s *searchTestRunner) verifySceneSearch(term string, scene *models.Scene, expected bool) {
+	s.t.Helper()
+
+	scenes, err := s.resolver.Query().SearchScene(s.ctx, term)
+	if err != nil {
+		s.t.Errorf("Error finding scene: %s", err.Error())
+		return
+	}
+
+	found := false
+	for _, result := range scenes {
+		found = found || result.ID == scene.ID
+	}
+	if found != expected {
+		s.fieldMismatch(expected, found, "Found "+term)
+	}
+}
+
+func (s *searchTestRunner) testSearchSceneWithoutStudio() {
+	title := "Scene without any studio"
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Title: &title,
+	})
+	if err != nil {
+		return
+	}
+
+	s.verifySceneSearch(title, scene, true)
+}
+
+func (s *searchTestRunner) testSearchSceneByPerformer() {
+	performer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	title := "Scene searched by performer"
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Title: &title,
+		Performers: []*models.PerformerAppearanceInput{
+			{PerformerID: performer.ID.String()},
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	s.verifySceneSearch(performer.Name, scene, true)
+
+	// merged performers are replaced by the merge target
+	mergeTarget, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	id := mergeTarget.ID.String()
+	editInput := models.EditInput{
+		Operation:      models.OperationEnumMerge,
+		ID:             &id,
+		MergeSourceIds: []string{performer.ID.String()},
+	}
+	details := models.PerformerEditDetailsInput{
+		Name: &mergeTarget.Name,
+	}
+
+	mergeEdit, err := s.createTestPerformerEdit(models.OperationEnumMerge, &details, &editInput)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(mergeEdit.ID.String()); err != nil {
+		return
+	}
+
+	s.verifySceneSearch(mergeTarget.Name, scene, true)
+	s.verifySceneSearch(performer.Name, scene, false)
+}
+
+func (s *searchTestRunner) testSearchSceneDeletedPerformer() {
+	performer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	title := "Scene of deleted performer"
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Title: &title,
+		Performers: []*models.PerformerAppearanceInput{
+			{PerformerID: performer.ID.String()},
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	s.verifySceneSearch(performer.Name, scene, true)
+
+	id := performer.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumDestroy,
+		ID:        &id,
+	}
+	destroyEdit, err := s.createTestPerformerEdit(models.OperationEnumDestroy, &models.PerformerEditDetailsInput{}, &editInput)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(destroyEdit.ID.String()); err != nil {
+		return
+	}
+
+	s.verifySceneSearch(performer.Name, scene, false)
+	s.verifySceneSearch(title, scene, true)
+}
+
+func (s *searchTestRunner) testSearchSceneByTag() {
+	tag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	title := "Scene searched by tag"
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Title:  &title,
+		TagIds: []string{tag.ID.String()},
+	})
+	if err != nil {
+		return
+	}
+
+	s.verifySceneSearch(tag.Name, scene, true)
+
+	// renamed tags are searched by their new name
+	name := s.generateTagName()
+	id := tag.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+	renameEdit, err := s.createTestTagEdit(models.OperationEnumModify, &models.TagEditDetailsInput{Name: &name}, &editInput)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(renameEdit.ID.String()); err != nil {
+		return
+	}
+
+	s.verifySceneSearch(name, scene, true)
+	s.verifySceneSearch(tag.Name, scene, false)
+}
+
+func (s *searchTestRunner) testSearchSceneByStudioParent() {
+	studio, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+	studioID := studio.ID.String()
+
+	title := "Scene searched by parent studio"
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Title:    &title,
+		StudioID: &studioID,
+	})
+	if err != nil {
+		return
+	}
+
+	parent, err := s.createTestStudio(&models.StudioCreateInput{
+		Name: s.generateStudioName() + " parent",
+	})
+	if err != nil {
+		return
+	}
+	s.verifySceneSearch(parent.Name, scene, false)
+
+	parentID := parent.ID.String()
+	if _, err := s.resolver.Mutation().StudioUpdate(s.ctx, models.StudioUpdateInput{
+		ID:       studioID,
+		ParentID: &parentID,
+	}); err != nil {
+		s.t.Errorf("Error updating studio: %s", err.Error())
+		return
+	}
+
+	s.verifySceneSearch(parent.Name, scene, true)
+}
+
+func (s *searchTestRunner) testSearchSceneDeleted() {
+	title := "Scene deleted by merge"
+	source, err := s.createTestScene(&models.SceneCreateInput{
+		Title: &title,
+	})
+	if err != nil {
+		return
+	}
+	target, err := s.createTestScene(nil)
+	if err != nil {
+		return
+	}
+
+	s.verifySceneSearch(title, source, true)
+
+	id := target.ID.String()
+	editInput := models.EditInput{
+		Operation:      models.OperationEnumMerge,
+		ID:             &id,
+		MergeSourceIds: []string{source.ID.String()},
+	}
+
+	mergeEdit, err := s.createTestSceneEdit(models.OperationEnumMerge, &models.SceneEditDetailsInput{}, &editInput)
+	if err != nil {
+		return
+	}
+	if _, err := s.applyEdit(mergeEdit.ID.String()); err != nil {
+		return
+	}
+
+	s.verifySceneSearch(title, source, false)
+}
+
+func (s *searchTestRunner) testRebuildSceneSearch() {
+	title := "Scene found after rebuild"
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Title: &title,
+	})
+	if err != nil {
+		return
+	}
+
+	if _, err := s.resolver.Mutation().RebuildSceneSearch(s.ctx); err != nil {
+		s.t.Errorf("Error rebuilding scene search: %s", err.Error())
+		return
+	}
+
+	s.verifySceneSearch(title, scene, true)
+
+	if _, err := s.resolver.Mutation().RebuildSceneSearch(asModify(s.t).ctx); err != api.ErrUnauthorized {
+		s.t.Errorf("RebuildSceneSearch: got %v want %v", err, api.ErrUnauthorized)
+	}
+}
+
+func (s *searchTestRunner) testSearchSceneByFilename() {
+	parent, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+	parentID := parent.ID.String()
+	studio, err := s.createTestStudio(&models.StudioCreateInput{
+		Name:     s.generateStudioName(),
+		ParentID: &parentID,
+	})
+	if err != nil {
+		return
+	}
+	studioID := studio.ID.String()
+
+	performer, err := s.createTestPerformer(nil)
+	if err != nil {
+		return
+	}
+
+	date := "2020-05-14"
+	title := "Filename scene"
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Title:    &title,
+		Date:     &date,
+		StudioID: &studioID,
+		Performers: []*models.PerformerAppearanceInput{
+			{PerformerID: performer.ID.String()},
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	otherTitle := "Other filename scene"
+	other, err := s.createTestScene(&models.SceneCreateInput{
+		Title:    &otherTitle,
+		Date:     &date,
+		StudioID: &studioID,
+	})
+	if err != nil {
+		return
+	}
+
+	// the parent studio name without punctuation, as used in filenames
+	abbreviation := strings.Replace(parent.Name, "-", "", -1)
+	filename := abbreviation + ".20.05.14." + strings.Replace(performer.Name, "-", ".", -1) + ".XXX.1080p.mp4"
+
+	scenes, err := s.resolver.Query().SearchScene(s.ctx, filename)
+	if err != nil {
+		s.t.Errorf("Error finding scene: %s", err.Error())
+		return
+	}
+
+	var ids []uuid.UUID
+	for _, result := range scenes {
+		ids = append(ids, result.ID)
+	}
+
+	// the scenes of the studio and date, ranked by the performer
+	expected := []uuid.UUID{scene.ID, other.ID}
+	if !reflect.DeepEqual(expected, ids) {
+		s.fieldMismatch(expected, ids, "Scenes")
+	}
+}
+
+func (s *searchTestRunner) testSearchSceneByFilenameFallback() {
+	title := "Unstructured filename fallback"
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		Title: &title,
+	})
+	if err != nil {
+		return
+	}
+
+	// scenes are ranked by the words of filenames without a date
+	s.verifySceneSearch("Unstructured_Filename_Fallback.x264.mp4", scene, true)
+}
+
 func (s *searchTestRunner) testUnauthorisedSearch() {
 	// test each api interface - all require read so all should fail
 	_, err := s.resolver.Query().SearchPerformer(s.ctx, "")
@@ -134,10 +694,25 @@ func (s *searchTestRunner) testUnauthorisedSearch() {
 		s.t.Errorf("SearchPerformer: got %v want %v", err, api.ErrUnauthorized)
 	}
 
+	_, err = s.resolver.Query().SearchPerformerScored(s.ctx, "")
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("SearchPerformerScored: got %v want %v", err, api.ErrUnauthorized)
+	}
+
 	_, err = s.resolver.Query().SearchScene(s.ctx, "")
 	if err != api.ErrUnauthorized {
 		s.t.Errorf("SearchScene: got %v want %v", err, api.ErrUnauthorized)
 	}
+
+	_, err = s.resolver.Query().SearchStudio(s.ctx, "")
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("SearchStudio: got %v want %v", err, api.ErrUnauthorized)
+	}
+
+	_, err = s.resolver.Query().SearchTag(s.ctx, "")
+	if err != api.ErrUnauthorized {
+		s.t.Errorf("SearchTag: got %v want %v", err, api.ErrUnauthorized)
+	}
 }
 
 func TestSearchPerformerByTerm(t *testing.T) {
@@ -150,6 +725,23 @@ func TestSearchPerformerByID(t *testing.T) {
 	pt.testSearchPerformerByID()
 }
 
+func TestSearchPerformerByAlias(t *testing.T) {
+	pt := createSearchTestRunner(t)
+	pt.testSearchPerformerByAlias()
+}
+
+func TestSearchPerformerByDisambiguation(t *testing.T) {
+	pt := createSearchTestRunner(t)
+	pt.testSearchPerformerByDisambiguation()
+}
+
+func TestSearchPerformerDeleted(t *testing.T) {
+	pt := &searchTestRunner{
+		testRunner: *asAdmin(t),
+	}
+	pt.testSearchPerformerDeleted()
+}
+
 func TestSearchSceneByTerm(t *testing.T) {
 	pt := createSearchTestRunner(t)
 	pt.testSearchSceneByTerm()
@@ -159,6 +751,82 @@ func TestSearchSceneByID(t *testing.T) {
 	pt := createSearchTestRunner(t)
 	pt.testSearchSceneByID()
 }
+
+func TestSearchStudioByName(t *testing.T) {
+	pt := createSearchTestRunner(t)
+	pt.testSearchStudioByName()
+}
+
+func TestSearchTagByAlias(t *testing.T) {
+	pt := createSearchTestRunner(t)
+	pt.testSearchTagByAlias()
+}
+
+func TestSearchTagByDescription(t *testing.T) {
+	pt := createSearchTestRunner(t)
+	pt.testSearchTagByDescription()
+}
+
+func TestSearchTagNameBeforeDescription(t *testing.T) {
+	pt := createSearchTestRunner(t)
+	pt.testSearchTagNameBeforeDescription()
+}
+
+func TestSearchSceneWithoutStudio(t *testing.T) {
+	pt := createSearchTestRunner(t)
+	pt.testSearchSceneWithoutStudio()
+}
+
+func TestSearchSceneByPerformer(t *testing.T) {
+	pt := &searchTestRunner{
+		testRunner: *asAdmin(t),
+	}
+	pt.testSearchSceneByPerformer()
+}
+
+func TestSearchSceneDeletedPerformer(t *testing.T) {
+	pt := &searchTestRunner{
+		testRunner: *asAdmin(t),
+	}
+	pt.testSearchSceneDeletedPerformer()
+}
+
+func TestSearchSceneByTag(t *testing.T) {
+	pt := &searchTestRunner{
+		testRunner: *asAdmin(t),
+	}
+	pt.testSearchSceneByTag()
+}
+
+func TestSearchSceneByStudioParent(t *testing.T) {
+	pt := createSearchTestRunner(t)
+	pt.testSearchSceneByStudioParent()
+}
+
+func TestSearchSceneDeleted(t *testing.T) {
+	pt := &searchTestRunner{
+		testRunner: *asAdmin(t),
+	}
+	pt.testSearchSceneDeleted()
+}
+
+func TestRebuildSceneSearch(t *testing.T) {
+	pt := &searchTestRunner{
+		testRunner: *asAdmin(t),
+	}
+	pt.testRebuildSceneSearch()
+}
+
+func TestSearchSceneByFilename(t *testing.T) {
+	pt := createSearchTestRunner(t)
+	pt.testSearchSceneByFilename()
+}
+
+func TestSearchSceneByFilenameFallback(t *testing.T) {
+	pt := createSearchTestRunner(t)
+	pt.testSearchSceneByFilenameFallback()
+}
+
 func TestUnauthorisedSearch(t *testing.T) {
 	pt := &searchTestRunner{
 		testRunner: *asNone(t),
@@ -6,11 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"path"
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/99designs/gqlgen/handler"
 	"github.com/go-chi/chi"
@@ -18,6 +20,7 @@ import (
 	"github.com/gobuffalo/packr/v2"
 	"github.com/gorilla/websocket"
 	"github.com/rs/cors"
+	"github.com/stashapp/stashdb/pkg/database"
 	"github.com/stashapp/stashdb/pkg/dataloader"
 	"github.com/stashapp/stashdb/pkg/logger"
 	"github.com/stashapp/stashdb/pkg/manager/config"
@@ -66,6 +69,60 @@ func getRequestUserID(w http.ResponseWriter, r *http.Request) (string, bool, err
 	return userID, isAPIKey, err
 }
 
+// interval at which API call counts are written to the database
+const apiCallFlushInterval = 30 * time.Second
+
+var apiCallCounter = user.NewAPICallCounter()
+
+// recordAPICall counts an API call of the user. If the user is over the
+// rate limit, the call is refused and the time until the user may call the
+// API again is returned.
+func recordAPICall(u *models.User, roles []models.RoleEnum) (time.Duration, bool) {
+	// the highest limit of the user's roles applies, and users with a role
+	// without a limit are not limited
+	limit := 0
+	for _, role := range roles {
+		roleLimit, limited := config.GetAPIRateLimit(role.String())
+		if !limited {
+			limit = 0
+			break
+		}
+		if roleLimit > limit {
+			limit = roleLimit
+		}
+	}
+
+	return apiCallCounter.Record(u, limit, config.GetAPIRateWindow(), time.Now())
+}
+
+// writeAPICallCounts stores the API call counts of the users in a single
+// transaction.
+func writeAPICallCounts(counts []user.APICallCount) error {
+	return database.WithTransaction(context.Background(), func(txn database.Transaction) error {
+		qb := models.NewUserQueryBuilder(txn.GetTx())
+		for _, count := range counts {
+			if err := qb.UpdateAPICalls(count.UserID, count.Calls, count.LastCall); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func startAPICallFlush() {
+	go func() {
+		ticker := time.NewTicker(apiCallFlushInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			err := apiCallCounter.Flush(time.Now(), writeAPICallCounts)
+			if err != nil {
+				logger.Errorf("Error writing API call counts: %s", err.Error())
+			}
+		}
+	}()
+}
+
 func authenticateHandler() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -97,7 +154,15 @@ func authenticateHandler() func(http.Handler) http.Handler {
 				return
 			}
 
-			// TODO - increment api key counters
+			// only calls using an api key count towards the rate limit
+			if apiKey != "" && user != nil {
+				if retryAfter, ok := recordAPICall(user, roles); !ok {
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+					w.WriteHeader(http.StatusTooManyRequests)
+					w.Write([]byte("API rate limit exceeded"))
+					return
+				}
+			}
 
 			ctx = context.WithValue(ctx, ContextUser, user)
 			ctx = context.WithValue(ctx, ContextRoles, roles)
@@ -134,6 +199,7 @@ func Start() {
 
 	r.Use(corsConfig.Handler)
 	r.Use(authenticateHandler())
+	startAPICallFlush()
 	r.Use(middleware.Recoverer)
 
 	r.Use(middleware.DefaultCompress)
@@ -0,0 +1,466 @@
+// +build integration
+
+package api_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+type studioEditTestRunner struct {
+	testRunner
+}
+
+func createStudioEditTestRunner(t *testing.T) *studioEditTestRunner {
+	return &studioEditTestRunner{
+		testRunner: *asAdmin(t),
+	}
+}
+
+func (s *studioEditTestRunner) testCreateStudioEdit() {
+	parent, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+
+	name := "Name"
+	parentID := parent.ID.String()
+	studioEditDetailsInput := models.StudioEditDetailsInput{
+		Name:     &name,
+		ParentID: &parentID,
+	}
+	edit, err := s.createTestStudioEdit(models.OperationEnumCreate, &studioEditDetailsInput, nil)
+	if err == nil {
+		s.verifyCreatedStudioEdit(studioEditDetailsInput, edit)
+	}
+}
+
+func (s *studioEditTestRunner) verifyCreatedStudioEdit(input models.StudioEditDetailsInput, edit *models.Edit) {
+	r := s.resolver.Edit()
+
+	id, _ := r.ID(s.ctx, edit)
+	if id == "" {
+		s.t.Errorf("Expected created edit id to be non-zero")
+	}
+
+	s.verifyEditOperation(models.OperationEnumCreate.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumStudio.String(), edit)
+	s.verifyEditApplication(false, edit)
+
+	studioDetails := s.getEditStudioDetails(edit)
+
+	if *input.Name != *studioDetails.Name {
+		s.fieldMismatch(input.Name, studioDetails.Name, "Name")
+	}
+
+	if *input.ParentID != *studioDetails.ParentID {
+		s.fieldMismatch(*input.ParentID, *studioDetails.ParentID, "ParentID")
+	}
+}
+
+func (s *studioEditTestRunner) testModifyStudioEdit() {
+	existingName := "studioName"
+	studioCreateInput := models.StudioCreateInput{
+		Name: existingName,
+	}
+	createdStudio, err := s.createTestStudio(&studioCreateInput)
+	if err != nil {
+		return
+	}
+
+	parent, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+	child, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+
+	newName := "newName"
+	parentID := parent.ID.String()
+	studioEditDetailsInput := models.StudioEditDetailsInput{
+		Name:           &newName,
+		ParentID:       &parentID,
+		ChildStudioIds: []string{child.ID.String()},
+	}
+	id := createdStudio.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	createdUpdateEdit, err := s.createTestStudioEdit(models.OperationEnumModify, &studioEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+
+	s.verifyUpdatedStudioEdit(createdStudio, studioEditDetailsInput, createdUpdateEdit)
+}
+
+func (s *studioEditTestRunner) verifyUpdatedStudioEdit(originalStudio *models.Studio, input models.StudioEditDetailsInput, edit *models.Edit) {
+	studioDetails := s.getEditStudioDetails(edit)
+
+	s.verifyEditOperation(models.OperationEnumModify.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumPending.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumStudio.String(), edit)
+	s.verifyEditApplication(false, edit)
+
+	if *input.Name != *studioDetails.Name {
+		s.fieldMismatch(*input.Name, *studioDetails.Name, "Name")
+	}
+
+	if *input.ParentID != *studioDetails.ParentID {
+		s.fieldMismatch(*input.ParentID, *studioDetails.ParentID, "ParentID")
+	}
+
+	if !reflect.DeepEqual(input.ChildStudioIds, studioDetails.AddedChildStudios) {
+		s.fieldMismatch(input.ChildStudioIds, studioDetails.AddedChildStudios, "AddedChildStudios")
+	}
+
+	target := s.getEditStudioTarget(edit)
+	if originalStudio.Name != target.Name {
+		s.fieldMismatch(originalStudio.Name, target.Name, "Name")
+	}
+}
+
+func (s *studioEditTestRunner) testApplyModifyStudioEdit() {
+	createdStudio, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+
+	parent, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+	child, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+
+	newName := "newName2"
+	parentID := parent.ID.String()
+	studioEditDetailsInput := models.StudioEditDetailsInput{
+		Name:           &newName,
+		ParentID:       &parentID,
+		ChildStudioIds: []string{child.ID.String()},
+	}
+	id := createdStudio.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	createdUpdateEdit, err := s.createTestStudioEdit(models.OperationEnumModify, &studioEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(createdUpdateEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	modifiedStudio, _ := s.resolver.Query().FindStudio(s.ctx, &id, nil)
+	s.verifyApplyModifyStudioEdit(studioEditDetailsInput, modifiedStudio, appliedEdit)
+}
+
+func (s *studioEditTestRunner) testApplyRemoveParentStudioEdit() {
+	parent, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+
+	parentID := parent.ID.String()
+	createdStudio, err := s.createTestStudio(&models.StudioCreateInput{
+		Name:     s.generateStudioName(),
+		ParentID: &parentID,
+	})
+	if err != nil {
+		return
+	}
+
+	// an explicit null parent removes the parent
+	id := createdStudio.ID.String()
+	input := models.StudioEditInput{
+		Edit: &models.EditInput{
+			Operation: models.OperationEnumModify,
+			ID:        &id,
+		},
+		Details: &models.StudioEditDetailsInput{},
+	}
+	ctx := s.updateContext([]string{"parent_id"})
+	createdEdit, err := s.resolver.Mutation().StudioEdit(ctx, input)
+	if err != nil {
+		s.t.Errorf("Error creating edit: %s", err.Error())
+		return
+	}
+
+	details, _ := s.resolver.Edit().Details(s.ctx, createdEdit)
+	if !details.(*models.StudioEdit).RemoveParent {
+		s.fieldMismatch(true, false, "RemoveParent")
+	}
+
+	if _, err := s.applyEdit(createdEdit.ID.String()); err != nil {
+		return
+	}
+
+	modifiedStudio, _ := s.resolver.Query().FindStudio(s.ctx, &id, nil)
+	if modifiedStudio.ParentStudioID.Valid {
+		s.fieldMismatch(nil, modifiedStudio.ParentStudioID.UUID.String(), "ParentID")
+	}
+}
+
+func (s *studioEditTestRunner) verifyApplyModifyStudioEdit(input models.StudioEditDetailsInput, updatedStudio *models.Studio, edit *models.Edit) {
+	s.verifyEditOperation(models.OperationEnumModify.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumImmediateAccepted.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumStudio.String(), edit)
+	s.verifyEditApplication(true, edit)
+
+	if *input.Name != updatedStudio.Name {
+		s.fieldMismatch(*input.Name, updatedStudio.Name, "Name")
+	}
+
+	if updatedStudio.ParentStudioID.UUID.String() != *input.ParentID {
+		s.fieldMismatch(*input.ParentID, updatedStudio.ParentStudioID.UUID.String(), "ParentID")
+	}
+
+	children, _ := s.resolver.Studio().ChildStudios(s.ctx, updatedStudio)
+	if len(children) != 1 || children[0].ID.String() != input.ChildStudioIds[0] {
+		s.fieldMismatch(input.ChildStudioIds, children, "ChildStudios")
+	}
+}
+
+func (s *studioEditTestRunner) testApplyCyclicStudioEdit() {
+	parent, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+
+	parentID := parent.ID.String()
+	childCreateInput := models.StudioCreateInput{
+		Name:     s.generateStudioName(),
+		ParentID: &parentID,
+	}
+	child, err := s.createTestStudio(&childCreateInput)
+	if err != nil {
+		return
+	}
+
+	// set the parent of the parent to its own child
+	childID := child.ID.String()
+	studioEditDetailsInput := models.StudioEditDetailsInput{
+		ParentID: &childID,
+	}
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &parentID,
+	}
+
+	createdUpdateEdit, err := s.createTestStudioEdit(models.OperationEnumModify, &studioEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+
+	input := models.ApplyEditInput{
+		ID: createdUpdateEdit.ID.String(),
+	}
+	_, err = s.resolver.Mutation().ApplyEdit(s.ctx, input)
+	if err == nil {
+		s.t.Error("Expected error applying edit creating a parent cycle")
+	}
+}
+
+func (s *studioEditTestRunner) testApplyMergeStudioEdit() {
+	mergeSource, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+	mergeTarget, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+
+	sourceID := mergeSource.ID.String()
+	sceneInput := models.SceneCreateInput{
+		StudioID: &sourceID,
+		Fingerprints: []*models.FingerprintInput{
+			s.generateSceneFingerprint(),
+		},
+	}
+	scene, err := s.createTestScene(&sceneInput)
+	if err != nil {
+		return
+	}
+
+	id := mergeTarget.ID.String()
+	editInput := models.EditInput{
+		Operation:      models.OperationEnumMerge,
+		ID:             &id,
+		MergeSourceIds: []string{sourceID},
+	}
+
+	mergeEdit, err := s.createTestStudioEdit(models.OperationEnumMerge, &models.StudioEditDetailsInput{}, &editInput)
+	if err != nil {
+		return
+	}
+
+	appliedMerge, err := s.applyEdit(mergeEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	s.verifyAppliedMergeStudioEdit(appliedMerge, scene)
+}
+
+func (s *studioEditTestRunner) verifyAppliedMergeStudioEdit(edit *models.Edit, scene *models.Scene) {
+	s.verifyEditOperation(models.OperationEnumMerge.String(), edit)
+	s.verifyEditStatus(models.VoteStatusEnumImmediateAccepted.String(), edit)
+	s.verifyEditTargetType(models.TargetTypeEnumStudio.String(), edit)
+	s.verifyEditApplication(true, edit)
+
+	merges, _ := s.resolver.Edit().MergeSources(s.ctx, edit)
+	for i := range merges {
+		studio := merges[i].(*models.Studio)
+		if studio.Deleted != true {
+			s.fieldMismatch(studio.Deleted, true, "Deleted")
+		}
+	}
+
+	target := s.getEditStudioTarget(edit)
+	sqb := models.NewSceneQueryBuilder(nil)
+	updatedScene, _ := sqb.Find(scene.ID)
+	if updatedScene.StudioID.UUID != target.ID {
+		s.fieldMismatch(target.ID, updatedScene.StudioID.UUID, "StudioID")
+	}
+}
+
+func (s *studioEditTestRunner) testApplyDestroyStudioEdit() {
+	createdStudio, err := s.createTestStudio(nil)
+	if err != nil {
+		return
+	}
+
+	id := createdStudio.ID.String()
+	child, err := s.createTestStudio(&models.StudioCreateInput{
+		Name:     s.generateStudioName(),
+		ParentID: &id,
+	})
+	if err != nil {
+		return
+	}
+	scene, err := s.createTestScene(&models.SceneCreateInput{
+		StudioID: &id,
+		Fingerprints: []*models.FingerprintInput{
+			s.generateSceneFingerprint(),
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	editInput := models.EditInput{
+		Operation: models.OperationEnumDestroy,
+		ID:        &id,
+	}
+	destroyEdit, err := s.createTestStudioEdit(models.OperationEnumDestroy, &models.StudioEditDetailsInput{}, &editInput)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(destroyEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	s.verifyEditOperation(models.OperationEnumDestroy.String(), appliedEdit)
+	s.verifyEditApplication(true, appliedEdit)
+
+	// the destroyed studio is removed from its scenes and child studios
+	s.verifyStudioReferences(createdStudio, child, scene, false)
+
+	if _, err := s.revertEdit(destroyEdit.ID.String()); err != nil {
+		return
+	}
+
+	// and restored to them by the revert
+	s.verifyStudioReferences(createdStudio, child, scene, true)
+}
+
+func (s *studioEditTestRunner) verifyStudioReferences(studio *models.Studio, child *models.Studio, scene *models.Scene, expected bool) {
+	s.t.Helper()
+
+	sqb := models.NewSceneQueryBuilder(nil)
+	updatedScene, _ := sqb.Find(scene.ID)
+	if referenced := updatedScene.StudioID.Valid && updatedScene.StudioID.UUID == studio.ID; referenced != expected {
+		s.fieldMismatch(expected, referenced, "Scene studio")
+	}
+
+	tqb := models.NewStudioQueryBuilder(nil)
+	updatedChild, _ := tqb.Find(child.ID)
+	if referenced := updatedChild.ParentStudioID.Valid && updatedChild.ParentStudioID.UUID == studio.ID; referenced != expected {
+		s.fieldMismatch(expected, referenced, "Child studio parent")
+	}
+
+	updatedStudio, _ := tqb.Find(studio.ID)
+	if updatedStudio.Deleted == expected {
+		s.fieldMismatch(!expected, updatedStudio.Deleted, "Deleted")
+	}
+}
+
+func (s *studioEditTestRunner) testStudioEditWithoutID() {
+	for _, operation := range []models.OperationEnum{models.OperationEnumModify, models.OperationEnumDestroy} {
+		input := models.StudioEditInput{
+			Edit: &models.EditInput{
+				Operation: operation,
+			},
+			Details: &models.StudioEditDetailsInput{},
+		}
+
+		if _, err := s.resolver.Mutation().StudioEdit(s.ctx, input); err == nil {
+			s.t.Errorf("Expected error for %s edit without id", operation)
+		}
+	}
+}
+
+func TestCreateStudioEdit(t *testing.T) {
+	pt := createStudioEditTestRunner(t)
+	pt.testCreateStudioEdit()
+}
+
+func TestModifyStudioEdit(t *testing.T) {
+	pt := createStudioEditTestRunner(t)
+	pt.testModifyStudioEdit()
+}
+
+func TestApplyModifyStudioEdit(t *testing.T) {
+	pt := createStudioEditTestRunner(t)
+	pt.testApplyModifyStudioEdit()
+}
+
+func TestApplyRemoveParentStudioEdit(t *testing.T) {
+	pt := createStudioEditTestRunner(t)
+	pt.testApplyRemoveParentStudioEdit()
+}
+
+func TestApplyCyclicStudioEdit(t *testing.T) {
+	pt := createStudioEditTestRunner(t)
+	pt.testApplyCyclicStudioEdit()
+}
+
+func TestApplyMergeStudioEdit(t *testing.T) {
+	pt := createStudioEditTestRunner(t)
+	pt.testApplyMergeStudioEdit()
+}
+
+func TestApplyDestroyStudioEdit(t *testing.T) {
+	pt := createStudioEditTestRunner(t)
+	pt.testApplyDestroyStudioEdit()
+}
+
+func TestStudioEditWithoutID(t *testing.T) {
+	pt := createStudioEditTestRunner(t)
+	pt.testStudioEditWithoutID()
+}
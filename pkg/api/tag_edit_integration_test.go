@@ -499,6 +499,278 @@ func (s *tagEditTestRunner) verifyAppliedMergeTagEdit(input models.TagEditDetail
 	}
 }
 
+func (s *tagEditTestRunner) testRevertModifyTagEdit() {
+	existingName := "tagName5"
+	existingAlias := "tagAlias5"
+	tagCreateInput := models.TagCreateInput{
+		Name:    existingName,
+		Aliases: []string{existingAlias},
+	}
+	createdTag, err := s.createTestTag(&tagCreateInput)
+	if err != nil {
+		return
+	}
+
+	newName := "newName5"
+	tagEditDetailsInput := models.TagEditDetailsInput{
+		Name:    &newName,
+		Aliases: []string{"newTagAlias5"},
+	}
+	id := createdTag.ID.String()
+	editInput := models.EditInput{
+		Operation: models.OperationEnumModify,
+		ID:        &id,
+	}
+
+	createdUpdateEdit, err := s.createTestTagEdit(models.OperationEnumModify, &tagEditDetailsInput, &editInput)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(createdUpdateEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	revertEdit, err := s.revertEdit(appliedEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	s.verifyEditOperation(models.OperationEnumModify.String(), revertEdit)
+	s.verifyEditStatus(models.VoteStatusEnumImmediateAccepted.String(), revertEdit)
+	s.verifyEditApplication(true, revertEdit)
+
+	reverts, _ := s.resolver.Edit().Reverts(s.ctx, revertEdit)
+	if reverts == nil || reverts.ID != appliedEdit.ID {
+		s.t.Errorf("Revert edit does not reference the reverted edit")
+	}
+	revertedBy, _ := s.resolver.Edit().RevertedBy(s.ctx, appliedEdit)
+	if revertedBy == nil || revertedBy.ID != revertEdit.ID {
+		s.t.Errorf("Reverted edit does not reference the revert edit")
+	}
+
+	tagDetails := s.getEditTagDetails(revertEdit)
+	if tagDetails.Name == nil || *tagDetails.Name != existingName {
+		s.fieldMismatch(existingName, tagDetails.Name, "Reverted name")
+	}
+
+	revertedTag, _ := s.resolver.Query().FindTag(s.ctx, &id, nil)
+	if revertedTag.Name != existingName {
+		s.fieldMismatch(existingName, revertedTag.Name, "Name")
+	}
+
+	tagAliases, _ := s.resolver.Tag().Aliases(s.ctx, revertedTag)
+	if !reflect.DeepEqual(tagCreateInput.Aliases, tagAliases) {
+		s.fieldMismatch(tagCreateInput.Aliases, tagAliases, "Aliases")
+	}
+
+	// neither edit can be reverted again
+	input := models.RevertEditInput{
+		ID: appliedEdit.ID.String(),
+	}
+	if _, err := s.resolver.Mutation().RevertEdit(s.ctx, input); err == nil {
+		s.t.Errorf("Expected error reverting an edit twice")
+	}
+	input.ID = revertEdit.ID.String()
+	if _, err := s.resolver.Mutation().RevertEdit(s.ctx, input); err == nil {
+		s.t.Errorf("Expected error reverting a revert edit")
+	}
+}
+
+func (s *tagEditTestRunner) testRevertCreateTagEdit() {
+	name := s.generateTagName()
+	tagEditDetailsInput := models.TagEditDetailsInput{
+		Name: &name,
+	}
+	createEdit, err := s.createTestTagEdit(models.OperationEnumCreate, &tagEditDetailsInput, nil)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(createEdit.ID.String())
+	if err != nil {
+		return
+	}
+	id := s.getEditTagTarget(appliedEdit).ID.String()
+
+	revertEdit, err := s.revertEdit(appliedEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	// the revert of a create destroys the created tag
+	s.verifyEditOperation(models.OperationEnumDestroy.String(), revertEdit)
+	s.verifyEditApplication(true, revertEdit)
+
+	revertedTag, _ := s.resolver.Query().FindTag(s.ctx, &id, nil)
+	if !revertedTag.Deleted {
+		s.fieldMismatch(true, revertedTag.Deleted, "Deleted")
+	}
+}
+
+func (s *tagEditTestRunner) testRevertDestroyTagEdit() {
+	createdTag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	tagID := createdTag.ID.String()
+	sceneInput := models.SceneCreateInput{
+		TagIds: []string{tagID},
+	}
+	scene, err := s.createTestScene(&sceneInput)
+	if err != nil {
+		return
+	}
+
+	editInput := models.EditInput{
+		Operation: models.OperationEnumDestroy,
+		ID:        &tagID,
+	}
+	destroyEdit, err := s.createTestTagEdit(models.OperationEnumDestroy, &models.TagEditDetailsInput{}, &editInput)
+	if err != nil {
+		return
+	}
+	appliedEdit, err := s.applyEdit(destroyEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	revertEdit, err := s.revertEdit(appliedEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	// the revert of a destroy creates the tag again
+	s.verifyEditOperation(models.OperationEnumCreate.String(), revertEdit)
+	s.verifyEditApplication(true, revertEdit)
+
+	revertedTag, _ := s.resolver.Query().FindTag(s.ctx, &tagID, nil)
+	if revertedTag.Deleted {
+		s.fieldMismatch(false, revertedTag.Deleted, "Deleted")
+	}
+
+	sceneTags, _ := s.resolver.Scene().Tags(s.ctx, scene)
+	if len(sceneTags) != 1 || sceneTags[0].ID != createdTag.ID {
+		s.fieldMismatch(createdTag.ID, sceneTags, "Scene tags")
+	}
+}
+
+func (s *tagEditTestRunner) testRevertMergeTagEdit() {
+	mergeSource, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+	mergeTarget, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	sceneInput := models.SceneCreateInput{
+		TagIds: []string{mergeSource.ID.String()},
+	}
+	scene, err := s.createTestScene(&sceneInput)
+	if err != nil {
+		return
+	}
+
+	id := mergeTarget.ID.String()
+	editInput := models.EditInput{
+		Operation:      models.OperationEnumMerge,
+		ID:             &id,
+		MergeSourceIds: []string{mergeSource.ID.String()},
+	}
+	mergeEdit, err := s.createTestTagEdit(models.OperationEnumMerge, &models.TagEditDetailsInput{}, &editInput)
+	if err != nil {
+		return
+	}
+
+	appliedMerge, err := s.applyEdit(mergeEdit.ID.String())
+	if err != nil {
+		return
+	}
+
+	if _, err := s.revertEdit(appliedMerge.ID.String()); err != nil {
+		return
+	}
+
+	sourceID := mergeSource.ID.String()
+	revertedSource, _ := s.resolver.Query().FindTag(s.ctx, &sourceID, nil)
+	if revertedSource.Deleted {
+		s.fieldMismatch(false, revertedSource.Deleted, "Merge source deleted")
+	}
+
+	sceneTags, _ := s.resolver.Scene().Tags(s.ctx, scene)
+	if len(sceneTags) != 1 {
+		s.fieldMismatch(1, len(sceneTags), "Scene tag count")
+	} else if sceneTags[0].ID != mergeSource.ID {
+		s.fieldMismatch(mergeSource.ID, sceneTags[0].ID, "Scene tag ID")
+	}
+}
+
+func (s *tagEditTestRunner) testPreviewTagEdit() {
+	createdTag, err := s.createTestTag(nil)
+	if err != nil {
+		return
+	}
+
+	newName := "previewName"
+	id := createdTag.ID.String()
+	input := models.PreviewEditInput{
+		Tag: &models.TagEditInput{
+			Edit: &models.EditInput{
+				Operation: models.OperationEnumModify,
+				ID:        &id,
+			},
+			Details: &models.TagEditDetailsInput{
+				Name: &newName,
+			},
+		},
+	}
+
+	preview, err := s.resolver.Query().PreviewEdit(s.ctx, input)
+	if err != nil {
+		s.t.Errorf("Error previewing edit: %s", err.Error())
+		return
+	}
+
+	if len(preview.Errors) != 0 {
+		s.t.Errorf("Unexpected preview errors: %v", preview.Errors)
+		return
+	}
+
+	tagDetails := preview.Details.(*models.TagEdit)
+	if tagDetails.Name == nil || *tagDetails.Name != newName {
+		s.fieldMismatch(newName, tagDetails.Name, "Details name")
+	}
+
+	previewTag := preview.Result.(*models.Tag)
+	if previewTag.Name != newName {
+		s.fieldMismatch(newName, previewTag.Name, "Result name")
+	}
+
+	// the tag itself must not be changed
+	tag, _ := s.resolver.Query().FindTag(s.ctx, &id, nil)
+	if tag.Name != createdTag.Name {
+		s.fieldMismatch(createdTag.Name, tag.Name, "Name")
+	}
+
+	// a tag cannot be created without a name
+	input.Tag = &models.TagEditInput{
+		Edit: &models.EditInput{
+			Operation: models.OperationEnumCreate,
+		},
+		Details: &models.TagEditDetailsInput{},
+	}
+	preview, err = s.resolver.Query().PreviewEdit(s.ctx, input)
+	if err != nil {
+		s.t.Errorf("Error previewing edit: %s", err.Error())
+		return
+	}
+	if len(preview.Errors) == 0 {
+		s.t.Errorf("Expected preview error for tag without name")
+	}
+}
+
 func TestCreateTagEdit(t *testing.T) {
 	pt := createTagEditTestRunner(t)
 	pt.testCreateTagEdit()
@@ -538,3 +810,28 @@ func TestApplyMergeTagEdit(t *testing.T) {
 	pt := createTagEditTestRunner(t)
 	pt.testApplyMergeTagEdit()
 }
+
+func TestRevertModifyTagEdit(t *testing.T) {
+	pt := createTagEditTestRunner(t)
+	pt.testRevertModifyTagEdit()
+}
+
+func TestRevertCreateTagEdit(t *testing.T) {
+	pt := createTagEditTestRunner(t)
+	pt.testRevertCreateTagEdit()
+}
+
+func TestRevertDestroyTagEdit(t *testing.T) {
+	pt := createTagEditTestRunner(t)
+	pt.testRevertDestroyTagEdit()
+}
+
+func TestRevertMergeTagEdit(t *testing.T) {
+	pt := createTagEditTestRunner(t)
+	pt.testRevertMergeTagEdit()
+}
+
+func TestPreviewTagEdit(t *testing.T) {
+	pt := createTagEditTestRunner(t)
+	pt.testPreviewTagEdit()
+}
@@ -69,6 +69,12 @@ type DBI interface {
 
 	// Query performs a query using the provided query builder.
 	Query(query QueryBuilder, output Models) (int, error)
+
+	// Facet counts the rows matching the provided query builder by the value
+	// of the provided expression. The expression refers to the matching rows
+	// as "filtered", and may refer to tables added by the join clause. At
+	// most limit values are returned, unless limit is not positive.
+	Facet(query QueryBuilder, value string, join string, limit int) ([]*FacetCount, error)
 }
 
 type dbi struct {
@@ -356,6 +362,30 @@ func (q dbi) Query(query QueryBuilder, output Models) (int, error) {
 	return count, err
 }
 
+// Facet counts the rows matching the provided query builder by the value
+// of the provided expression, in descending order of count.
+func (q dbi) Facet(query QueryBuilder, value string, join string, limit int) ([]*FacetCount, error) {
+	var err error
+	var output []*FacetCount
+
+	rawQuery := query.buildFacetQuery(value, join, limit)
+
+	if q.tx != nil {
+		rawQuery = q.tx.Rebind(rawQuery)
+		err = q.tx.Select(&output, rawQuery, query.args...)
+	} else {
+		rawQuery = DB.Rebind(rawQuery)
+		err = DB.Select(&output, rawQuery, query.args...)
+	}
+
+	if err != nil {
+		err = errors.Wrap(err, fmt.Sprintf("Error executing query: %s, with args: %v", rawQuery, query.args))
+		return nil, err
+	}
+
+	return output, nil
+}
+
 func (q dbi) DeleteQuery(query QueryBuilder) error {
 	ensureTx(q.tx)
 	queryStr := q.tx.Rebind(query.buildQuery())
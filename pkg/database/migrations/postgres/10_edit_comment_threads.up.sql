@@ -0,0 +1,7 @@
+ALTER TABLE "edit_comments"
+  ADD COLUMN "parent_id" uuid,
+  ADD COLUMN "edited_at" timestamp,
+  ADD COLUMN "hidden" boolean not null default false,
+  ADD foreign key("parent_id") references "edit_comments"("id") ON DELETE CASCADE;
+
+CREATE INDEX "edit_comments_edit_id_idx" ON "edit_comments" ("edit_id");
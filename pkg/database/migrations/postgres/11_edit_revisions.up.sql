@@ -0,0 +1,11 @@
+CREATE TABLE "edit_revisions" (
+  "id" uuid not null primary key,
+  "edit_id" uuid not null,
+  "data" jsonb,
+  "created_at" timestamp not null,
+  foreign key("edit_id") references "edits"("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "edit_revisions_edit_id_idx" ON "edit_revisions" ("edit_id");
+
+ALTER TABLE "edit_votes" ADD COLUMN "outdated" boolean not null default false;
@@ -0,0 +1 @@
+ALTER TABLE "edits" ADD COLUMN "target_updated_at" timestamp;
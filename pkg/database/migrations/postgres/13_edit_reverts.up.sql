@@ -0,0 +1,6 @@
+ALTER TABLE "edits"
+  ADD COLUMN "reverts_id" uuid,
+  ADD COLUMN "snapshot" jsonb,
+  ADD foreign key("reverts_id") references "edits"("id") ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX "edits_reverts_id_idx" ON "edits" ("reverts_id");
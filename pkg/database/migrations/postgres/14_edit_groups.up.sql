@@ -0,0 +1,12 @@
+CREATE TABLE "edit_groups" (
+  "id" uuid not null primary key,
+  "user_id" uuid,
+  "created_at" timestamp not null,
+  foreign key("user_id") references "users"("id") ON DELETE SET NULL
+);
+
+ALTER TABLE "edits"
+  ADD COLUMN "group_id" uuid,
+  ADD foreign key("group_id") references "edit_groups"("id") ON DELETE SET NULL;
+
+CREATE INDEX "edits_group_id_idx" ON "edits" ("group_id");
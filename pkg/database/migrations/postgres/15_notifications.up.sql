@@ -0,0 +1,24 @@
+CREATE TABLE "notifications" (
+  "id" uuid not null primary key,
+  "user_id" uuid not null,
+  "edit_id" uuid not null,
+  "type" varchar(30) not null,
+  "created_at" timestamp not null,
+  "read_at" timestamp,
+  "emailed" boolean default FALSE not null,
+  foreign key("user_id") references "users"("id") ON DELETE CASCADE,
+  foreign key("edit_id") references "edits"("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "notifications_user_id_idx" ON "notifications" ("user_id", "created_at");
+
+CREATE TABLE "notification_subscriptions" (
+  "user_id" uuid not null,
+  "target_type" varchar(20) not null,
+  "target_id" uuid not null,
+  "created_at" timestamp not null,
+  primary key("user_id", "target_id"),
+  foreign key("user_id") references "users"("id") ON DELETE CASCADE
+);
+
+CREATE INDEX "notification_subscriptions_target_id_idx" ON "notification_subscriptions" ("target_id");
@@ -0,0 +1 @@
+ALTER TABLE "user_roles" ALTER COLUMN "role" TYPE varchar(20);
@@ -0,0 +1,24 @@
+ALTER TABLE "scene_fingerprints"
+ADD COLUMN "phash" bigint;
+
+-- PHASH fingerprints are stored as hexadecimal strings, like the other
+-- algorithms, and as 64-bit integers for distance matching
+CREATE OR REPLACE FUNCTION set_fingerprint_phash() RETURNS TRIGGER AS $$
+BEGIN
+IF (NEW.algorithm = 'PHASH') THEN
+NEW.phash = ('x' || LPAD(NEW.hash, 16, '0'))::bit(64)::bigint;
+ELSE
+NEW.phash = NULL;
+END IF;
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_scene_fingerprint_phash BEFORE INSERT OR UPDATE ON scene_fingerprints FOR EACH ROW EXECUTE PROCEDURE set_fingerprint_phash();
+
+-- any hash within a distance of n of another has at least one 16-bit
+-- segment within a distance of n / 4 of the same segment of the other
+CREATE INDEX scene_fingerprints_phash_0_idx ON scene_fingerprints (((phash >> 48) & 65535)) WHERE phash IS NOT NULL;
+CREATE INDEX scene_fingerprints_phash_1_idx ON scene_fingerprints (((phash >> 32) & 65535)) WHERE phash IS NOT NULL;
+CREATE INDEX scene_fingerprints_phash_2_idx ON scene_fingerprints (((phash >> 16) & 65535)) WHERE phash IS NOT NULL;
+CREATE INDEX scene_fingerprints_phash_3_idx ON scene_fingerprints (((phash >> 0) & 65535)) WHERE phash IS NOT NULL;
@@ -0,0 +1,16 @@
+ALTER TABLE "scene_fingerprints"
+  ADD COLUMN "created_at" timestamp not null default NOW(),
+  ADD COLUMN "updated_at" timestamp not null default NOW();
+
+CREATE TABLE "scene_fingerprint_submissions" (
+  "scene_id" uuid not null,
+  "algorithm" varchar(20) not null,
+  "hash" varchar(255) not null,
+  "user_id" uuid not null,
+  "created_at" timestamp not null,
+  foreign key("scene_id") references "scenes"("id") ON DELETE CASCADE,
+  foreign key("user_id") references "users"("id") ON DELETE CASCADE,
+  primary key("scene_id", "algorithm", "hash", "user_id")
+);
+
+CREATE INDEX "scene_fingerprint_submissions_user_id_idx" ON "scene_fingerprint_submissions" ("user_id");
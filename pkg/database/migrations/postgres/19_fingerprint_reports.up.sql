@@ -0,0 +1,13 @@
+CREATE TABLE "scene_fingerprint_reports" (
+  "scene_id" uuid not null,
+  "algorithm" varchar(20) not null,
+  "hash" varchar(255) not null,
+  "user_id" uuid not null,
+  "reason" text not null,
+  "created_at" timestamp not null,
+  foreign key("scene_id") references "scenes"("id") ON DELETE CASCADE,
+  foreign key("user_id") references "users"("id") ON DELETE CASCADE,
+  primary key("scene_id", "algorithm", "hash", "user_id")
+);
+
+CREATE INDEX "scene_fingerprint_reports_user_id_idx" ON "scene_fingerprint_reports" ("user_id");
@@ -0,0 +1,2 @@
+CREATE INDEX performer_aliases_trgm_idx ON performer_aliases USING GIN (alias gin_trgm_ops);
+CREATE INDEX performers_disambiguation_trgm_idx ON performers USING GIN ((name || ' ' || disambiguation) gin_trgm_ops) WHERE disambiguation IS NOT NULL;
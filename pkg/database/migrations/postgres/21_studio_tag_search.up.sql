@@ -0,0 +1,4 @@
+CREATE INDEX studios_name_trgm_idx ON studios USING GIN (name gin_trgm_ops);
+CREATE INDEX tags_name_trgm_idx ON tags USING GIN (name gin_trgm_ops);
+CREATE INDEX tag_aliases_alias_trgm_idx ON tag_aliases USING GIN (alias gin_trgm_ops);
+CREATE INDEX tags_description_ts_idx ON tags USING GIN (to_tsvector('english', COALESCE(description, '')));
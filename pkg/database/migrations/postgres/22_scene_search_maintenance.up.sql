@@ -0,0 +1,130 @@
+ALTER TABLE scene_search ADD COLUMN tag_names TEXT;
+
+DROP INDEX ts_idx;
+CREATE INDEX ts_idx ON scene_search USING gist (
+	(
+        to_tsvector('simple', COALESCE(scene_date, '')) ||
+        to_tsvector('english', studio_name) ||
+        to_tsvector('english', COALESCE(performer_names, '')) ||
+        to_tsvector('english', COALESCE(tag_names, '')) ||
+        to_tsvector('english', scene_title)
+	)
+);
+
+-- the search row of each scene that is not deleted, naming the performers
+-- and tags that are not deleted
+CREATE VIEW scene_search_source AS
+SELECT
+	S.id AS scene_id,
+	COALESCE(REGEXP_REPLACE(S.title, '[^a-zA-Z0-9 ]+', '', 'g'), '') AS scene_title,
+	S.date::TEXT AS scene_date,
+	CASE WHEN T.name IS NOT NULL THEN (T.name || ' ' || REGEXP_REPLACE(T.name, '[^a-zA-Z0-9]', '', 'g') || ' ' || CASE WHEN TP.name IS NOT NULL THEN (TP.name || ' ' || REGEXP_REPLACE(TP.name, '[^a-zA-Z0-9]', '', 'g') ) ELSE '' END) ELSE '' END AS studio_name,
+	STRING_AGG(P.name || COALESCE(' ' || PS.as, ''), ' ') AS performer_names,
+	(
+		SELECT STRING_AGG(G.name, ' ')
+		FROM scene_tags SG
+		JOIN tags G ON G.id = SG.tag_id AND NOT G.deleted
+		WHERE SG.scene_id = S.id
+	) AS tag_names
+FROM scenes S
+LEFT JOIN scene_performers PS ON PS.scene_id = S.id
+LEFT JOIN performers P ON PS.performer_id = P.id AND NOT P.deleted
+LEFT JOIN studios T ON T.id = S.studio_id
+LEFT JOIN studios TP ON T.parent_studio_id = TP.id
+WHERE NOT S.deleted
+GROUP BY S.id, S.title, T.name, TP.name;
+
+-- rebuild the rows of all scenes
+TRUNCATE scene_search;
+INSERT INTO scene_search (scene_id, scene_title, scene_date, studio_name, performer_names, tag_names)
+SELECT scene_id, scene_title, scene_date, studio_name, performer_names, tag_names FROM scene_search_source;
+
+CREATE UNIQUE INDEX scene_search_scene_id_idx ON scene_search (scene_id);
+
+DROP TRIGGER IF EXISTS update_performer_search_name ON performers;
+DROP TRIGGER IF EXISTS update_scene_search_title ON scenes;
+DROP TRIGGER IF EXISTS insert_scene_search ON scenes;
+DROP TRIGGER IF EXISTS update_studio_search_name ON studios;
+DROP TRIGGER IF EXISTS update_scene_performers_search ON scene_performers;
+
+DROP FUNCTION IF EXISTS update_performers();
+DROP FUNCTION IF EXISTS update_scene();
+DROP FUNCTION IF EXISTS insert_scene();
+DROP FUNCTION IF EXISTS update_studio();
+DROP FUNCTION IF EXISTS update_scene_performers();
+
+-- replaces the search row of the scene, removing it if the scene is deleted
+CREATE OR REPLACE FUNCTION update_scene_search(target_id uuid) RETURNS VOID AS $$
+BEGIN
+DELETE FROM scene_search WHERE scene_id = target_id;
+INSERT INTO scene_search (scene_id, scene_title, scene_date, studio_name, performer_names, tag_names)
+SELECT scene_id, scene_title, scene_date, studio_name, performer_names, tag_names
+FROM scene_search_source
+WHERE scene_id = target_id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION scene_search_scenes() RETURNS TRIGGER AS $$
+BEGIN
+IF (TG_OP = 'DELETE') THEN
+PERFORM update_scene_search(OLD.id);
+ELSE
+PERFORM update_scene_search(NEW.id);
+END IF;
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER scene_search_scenes AFTER INSERT OR UPDATE OR DELETE ON scenes FOR EACH ROW EXECUTE PROCEDURE scene_search_scenes();
+
+-- refreshes the search row of the scene of a row of a scene join table
+CREATE OR REPLACE FUNCTION scene_search_scene_joins() RETURNS TRIGGER AS $$
+BEGIN
+IF (TG_OP <> 'INSERT') THEN
+PERFORM update_scene_search(OLD.scene_id);
+END IF;
+IF (TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND NEW.scene_id <> OLD.scene_id)) THEN
+PERFORM update_scene_search(NEW.scene_id);
+END IF;
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER scene_search_scene_performers AFTER INSERT OR UPDATE OR DELETE ON scene_performers FOR EACH ROW EXECUTE PROCEDURE scene_search_scene_joins();
+CREATE TRIGGER scene_search_scene_tags AFTER INSERT OR UPDATE OR DELETE ON scene_tags FOR EACH ROW EXECUTE PROCEDURE scene_search_scene_joins();
+
+CREATE OR REPLACE FUNCTION scene_search_performers() RETURNS TRIGGER AS $$
+BEGIN
+IF (NEW.name IS DISTINCT FROM OLD.name OR NEW.deleted IS DISTINCT FROM OLD.deleted) THEN
+PERFORM update_scene_search(scene_id) FROM scene_performers WHERE performer_id = NEW.id;
+END IF;
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER scene_search_performers AFTER UPDATE ON performers FOR EACH ROW EXECUTE PROCEDURE scene_search_performers();
+
+CREATE OR REPLACE FUNCTION scene_search_studios() RETURNS TRIGGER AS $$
+BEGIN
+IF (NEW.name IS DISTINCT FROM OLD.name OR NEW.parent_studio_id IS DISTINCT FROM OLD.parent_studio_id) THEN
+PERFORM update_scene_search(S.id)
+FROM scenes S
+LEFT JOIN studios T ON T.id = S.studio_id
+WHERE S.studio_id = NEW.id OR T.parent_studio_id = NEW.id;
+END IF;
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER scene_search_studios AFTER UPDATE ON studios FOR EACH ROW EXECUTE PROCEDURE scene_search_studios();
+
+CREATE OR REPLACE FUNCTION scene_search_tags() RETURNS TRIGGER AS $$
+BEGIN
+IF (NEW.name IS DISTINCT FROM OLD.name OR NEW.deleted IS DISTINCT FROM OLD.deleted) THEN
+PERFORM update_scene_search(scene_id) FROM scene_tags WHERE tag_id = NEW.id;
+END IF;
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER scene_search_tags AFTER UPDATE ON tags FOR EACH ROW EXECUTE PROCEDURE scene_search_tags();
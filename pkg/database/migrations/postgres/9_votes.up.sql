@@ -0,0 +1,13 @@
+CREATE TABLE "edit_votes" (
+  "edit_id" uuid not null,
+  "user_id" uuid not null,
+  "created_at" timestamp not null,
+  "vote" varchar(20) not null,
+  "comment" text,
+  foreign key("edit_id") references "edits"("id") ON DELETE CASCADE,
+  foreign key("user_id") references "users"("id") ON DELETE CASCADE,
+  primary key("edit_id", "user_id")
+);
+
+CREATE INDEX "edit_votes_user_id_idx" ON "edit_votes" ("user_id");
+CREATE INDEX "edits_status_created_at_idx" ON "edits" ("status", "created_at");
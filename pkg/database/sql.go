@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/gofrs/uuid"
@@ -104,6 +105,23 @@ func (qb QueryBuilder) buildCountQuery() string {
 	return "SELECT COUNT(*) as count FROM (" + qb.buildBody() + ") as temp"
 }
 
+// FacetCount is the number of rows of a query with a facet value.
+type FacetCount struct {
+	Value string `db:"value"`
+	Count int    `db:"count"`
+}
+
+func (qb QueryBuilder) buildFacetQuery(value string, join string, limit int) string {
+	query := "SELECT CAST(" + value + " AS TEXT) AS value, COUNT(*) AS count FROM (" + qb.buildBody() + ") AS filtered " +
+		join + " WHERE " + value + " IS NOT NULL GROUP BY 1 ORDER BY count DESC, value"
+
+	if limit > 0 {
+		query += " LIMIT " + strconv.Itoa(limit)
+	}
+
+	return query
+}
+
 func (qb QueryBuilder) buildQuery() string {
 	return qb.buildBody() + qb.SortAndPagination
 }
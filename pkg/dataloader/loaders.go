@@ -23,10 +23,12 @@ type Loaders struct {
 	SceneImageIDsById      UUIDsLoader
 	SceneAppearancesById   SceneAppearancesLoader
 	SceneUrlsById          URLLoader
+	StudioById             StudioLoader
 	StudioImageIDsById     UUIDsLoader
 	StudioUrlsById         URLLoader
 	SceneTagIDsById        UUIDsLoader
 	TagById                TagLoader
+	UserEditStatsById      UserEditStatsLoader
 }
 
 func Middleware(next http.Handler) http.Handler {
@@ -126,6 +128,14 @@ func GetLoaders() *Loaders {
 				return qb.GetAllUrls(ids)
 			},
 		},
+		StudioById: StudioLoader{
+			maxBatch: 1000,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([]*models.Studio, []error) {
+				qb := models.NewStudioQueryBuilder(nil)
+				return qb.FindByIds(ids)
+			},
+		},
 		StudioUrlsById: URLLoader{
 			maxBatch: 100,
 			wait:     1 * time.Millisecond,
@@ -166,5 +176,13 @@ func GetLoaders() *Loaders {
 				return qb.FindByIds(ids)
 			},
 		},
+		UserEditStatsById: UserEditStatsLoader{
+			maxBatch: 100,
+			wait:     1 * time.Millisecond,
+			fetch: func(ids []uuid.UUID) ([]*models.UserEditStats, []error) {
+				qb := models.NewUserQueryBuilder(nil)
+				return qb.GetAllEditStats(ids)
+			},
+		},
 	}
 }
@@ -3,12 +3,14 @@ package email
 import (
 	"errors"
 	"net/smtp"
+	"sync"
 	"time"
 
 	"github.com/stashapp/stashdb/pkg/manager/config"
 )
 
 type Manager struct {
+	mutex       sync.Mutex
 	lastEmailed map[string]time.Time
 }
 
@@ -19,6 +21,9 @@ func NewManager() *Manager {
 }
 
 func (m *Manager) validateEmailCooldown(email string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	m.clearExpired()
 	_, found := m.lastEmailed[email]
 
@@ -49,12 +54,33 @@ func (m *Manager) makeAuth() smtp.Auth {
 	return nil
 }
 
+// Send emails the address, unless it was emailed within the cooldown period.
 func (m *Manager) Send(email, subject, body string) error {
 	err := m.validateEmailCooldown(email)
 	if err != nil {
 		return err
 	}
 
+	if err := m.send(email, subject, body); err != nil {
+		return err
+	}
+
+	// add to email map
+	m.mutex.Lock()
+	m.lastEmailed[email] = time.Now()
+	m.mutex.Unlock()
+
+	return nil
+}
+
+// SendNotification emails the address without checking or starting the
+// cooldown, so that notifications do not delay requested emails such as
+// password resets.
+func (m *Manager) SendNotification(email, subject, body string) error {
+	return m.send(email, subject, body)
+}
+
+func (m *Manager) send(email, subject, body string) error {
 	if len(config.GetMissingEmailSettings()) > 0 {
 		return errors.New("email settings not configured")
 	}
@@ -65,14 +91,5 @@ func (m *Manager) Send(email, subject, body string) error {
 
 	msg := []byte(from + endLine + to + endLine + subject + endLine + endLine + body + endLine)
 
-	err = smtp.SendMail(config.GetEmailHost(), m.makeAuth(), config.GetEmailFrom(), []string{email}, msg)
-
-	if err != nil {
-		return err
-	}
-
-	// add to email map
-	m.lastEmailed[email] = time.Now()
-
-	return nil
+	return smtp.SendMail(config.GetEmailHost(), m.makeAuth(), config.GetEmailFrom(), []string{email}, msg)
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -43,6 +44,39 @@ const activationExpiryDefault = 2 * 60 * 60
 // 5 minutes
 const emailCooldownDefault = 5 * 60
 
+// Voting settings
+const VoteApplicationThreshold = "vote_application_threshold"
+const VotingPeriod = "voting_period"
+
+const voteApplicationThresholdDefault = 3
+
+// 1 week
+const votingPeriodDefault = 7 * 24 * 60 * 60
+
+// Trusted edit settings
+const TrustedEditThreshold = "trusted_edit_threshold"
+const TrustedEditFields = "trusted_edit_fields"
+
+var trustedEditFieldsDefault = []string{"added_aliases", "added_urls", "added_fingerprints"}
+
+// Fingerprint settings
+const DurationDisputeThreshold = "duration_dispute_threshold"
+
+const FingerprintReportMargin = "fingerprint_report_margin"
+
+const durationDisputeThresholdDefault = 10
+const fingerprintReportMarginDefault = 1
+
+// Notification settings
+const NotificationDigestInterval = "notification_digest_interval"
+
+// API rate limit settings
+const APIRateWindow = "api_rate_window"
+const APIRateLimits = "api_rate_limits"
+
+// 1 hour
+const apiRateWindowDefault = 60 * 60
+
 // Email settings
 const EmailHost = "email_host"
 const EmailUser = "email_user"
@@ -164,6 +198,99 @@ func GetDefaultUserRoles() []string {
 	return ret
 }
 
+// GetVoteApplicationThreshold returns the number of net votes required for
+// an edit to be accepted or rejected before the voting period has elapsed.
+// A value of zero disables early acceptance and rejection.
+func GetVoteApplicationThreshold() int {
+	ret := voteApplicationThresholdDefault
+	if viper.IsSet(VoteApplicationThreshold) {
+		ret = viper.GetInt(VoteApplicationThreshold)
+	}
+
+	return ret
+}
+
+// GetVotingPeriod returns the duration that an edit is open for voting.
+func GetVotingPeriod() time.Duration {
+	ret := votingPeriodDefault
+	if viper.IsSet(VotingPeriod) {
+		ret = viper.GetInt(VotingPeriod)
+	}
+
+	return time.Duration(ret * int(time.Second))
+}
+
+// GetTrustedEditThreshold returns the number of successful edits after which
+// the low risk edits of a user are applied without a vote. A value of zero
+// restricts this to users with the TRUSTED_EDIT role.
+func GetTrustedEditThreshold() int {
+	return viper.GetInt(TrustedEditThreshold)
+}
+
+// GetTrustedEditFields returns the edit detail fields that trusted editors
+// may change without a vote.
+func GetTrustedEditFields() []string {
+	ret := trustedEditFieldsDefault
+	if viper.IsSet(TrustedEditFields) {
+		ret = viper.GetStringSlice(TrustedEditFields)
+	}
+
+	return ret
+}
+
+// GetDurationDisputeThreshold returns the difference, in seconds, between
+// the longest and shortest fingerprint durations of a scene above which the
+// scene is flagged for moderation. A value of zero disables flagging.
+func GetDurationDisputeThreshold() int {
+	ret := durationDisputeThresholdDefault
+	if viper.IsSet(DurationDisputeThreshold) {
+		ret = viper.GetInt(DurationDisputeThreshold)
+	}
+
+	return ret
+}
+
+// GetFingerprintReportMargin returns the number of reports by which the
+// reports of a fingerprint may exceed its submissions before the fingerprint
+// is no longer matched to its scene.
+func GetFingerprintReportMargin() int {
+	ret := fingerprintReportMarginDefault
+	if viper.IsSet(FingerprintReportMargin) {
+		ret = viper.GetInt(FingerprintReportMargin)
+	}
+
+	return ret
+}
+
+// GetAPIRateWindow returns the rolling time window over which the API calls
+// of each user are counted.
+func GetAPIRateWindow() time.Duration {
+	ret := apiRateWindowDefault
+	if viper.IsSet(APIRateWindow) {
+		ret = viper.GetInt(APIRateWindow)
+	}
+
+	return time.Duration(ret * int(time.Second))
+}
+
+// GetAPIRateLimit returns the maximum number of API calls within the rate
+// window for users with the role. Returns false if the role is not limited.
+func GetAPIRateLimit(role string) (int, bool) {
+	key := APIRateLimits + "." + strings.ToLower(role)
+	if !viper.IsSet(key) {
+		return 0, false
+	}
+
+	return viper.GetInt(key), true
+}
+
+// GetNotificationDigestInterval returns the interval at which unread
+// notifications are emailed to users. A value of zero disables the email
+// digest.
+func GetNotificationDigestInterval() time.Duration {
+	return time.Duration(viper.GetInt(NotificationDigestInterval) * int(time.Second))
+}
+
 func GetEmailHost() string {
 	return viper.GetString(EmailHost)
 }
@@ -0,0 +1,95 @@
+package edit
+
+import (
+	"errors"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// AmendEdit replaces the details of the existing pending edit with those of
+// the amended edit. The previous details are kept as a revision of the edit,
+// and votes cast before the amendment, on the edit or the rest of its group,
+// are flagged as outdated so that they no longer count towards the vote
+// count of any edit of the group.
+func AmendEdit(tx *sqlx.Tx, existing *models.Edit, amended *models.Edit, user *models.User, targetID *string) error {
+	if existing.UserID != user.ID {
+		return errors.New("Only the author of an edit can amend it")
+	}
+
+	if existing.Status != models.VoteStatusEnumPending.String() {
+		return errors.New("Invalid vote status: " + existing.Status)
+	}
+
+	if existing.TargetType != amended.TargetType {
+		return errors.New("Invalid target type: " + amended.TargetType)
+	}
+
+	if existing.Operation != amended.Operation {
+		return errors.New("Invalid operation: " + amended.Operation)
+	}
+
+	eqb := models.NewEditQueryBuilder(tx)
+
+	if existing.Operation != models.OperationEnumCreate.String() {
+		existingTargetID, err := findTargetID(eqb, existing)
+		if err != nil {
+			return err
+		}
+		if targetID == nil || existingTargetID == nil || existingTargetID.String() != *targetID {
+			return errors.New("Amended edit must have the same target as the existing edit")
+		}
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	revision := models.NewEditRevision(UUID, existing)
+	if err := eqb.CreateRevision(*revision); err != nil {
+		return err
+	}
+
+	// votes on grouped edits are cast on the whole group
+	edits, err := groupEdits(tx, existing)
+	if err != nil {
+		return err
+	}
+	for _, e := range edits {
+		if err := eqb.OutdateVotes(e.ID); err != nil {
+			return err
+		}
+	}
+
+	existing.Amend(*amended)
+	if _, err := eqb.Update(*existing); err != nil {
+		return err
+	}
+
+	// the outdated votes no longer count towards any edit of the group
+	for _, e := range edits {
+		if err := countVotes(eqb, e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func findTargetID(eqb models.EditQueryBuilder, edit *models.Edit) (*uuid.UUID, error) {
+	switch edit.TargetType {
+	case models.TargetTypeEnumTag.String():
+		return eqb.FindTagID(edit.ID)
+	case models.TargetTypeEnumPerformer.String():
+		return eqb.FindPerformerID(edit.ID)
+	case models.TargetTypeEnumScene.String():
+		return eqb.FindSceneID(edit.ID)
+	case models.TargetTypeEnumStudio.String():
+		return eqb.FindStudioID(edit.ID)
+	}
+
+	return nil, errors.New("Not implemented: " + edit.TargetType)
+}
@@ -0,0 +1,193 @@
+package edit
+
+import (
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// ApplyEdit applies the changes of a pending edit to the target object. The
+// state of the affected objects is stored in the edit snapshot beforehand,
+// so that the edit can be reverted. The status of the edit itself is left
+// for the caller to update.
+func ApplyEdit(tx *sqlx.Tx, edit *models.Edit) error {
+	if edit.Applied {
+		return errors.New("Edit already applied")
+	}
+
+	if edit.Status != models.VoteStatusEnumPending.String() {
+		return errors.New("Invalid vote status: " + edit.Status)
+	}
+
+	eqb := models.NewEditQueryBuilder(tx)
+
+	var operation models.OperationEnum
+	if err := operation.UnmarshalGQL(edit.Operation); err != nil {
+		return err
+	}
+	var targetType models.TargetTypeEnum
+	if err := targetType.UnmarshalGQL(edit.TargetType); err != nil {
+		return err
+	}
+
+	if err := validateConflicts(tx, edit); err != nil {
+		return err
+	}
+
+	switch targetType {
+	case models.TargetTypeEnumTag:
+		tqb := models.NewTagQueryBuilder(tx)
+		var tag *models.Tag = nil
+		if operation != models.OperationEnumCreate {
+			tagID, err := eqb.FindTagID(edit.ID)
+			if err != nil {
+				return err
+			}
+			tag, err = tqb.Find(*tagID)
+			if err != nil {
+				return err
+			}
+			if tag == nil {
+				return errors.New("Tag not found: " + tagID.String())
+			}
+
+			snapshot, err := tqb.GetEditSnapshot(*edit, tag)
+			if err != nil {
+				return err
+			}
+			if err := edit.SetSnapshot(snapshot); err != nil {
+				return err
+			}
+		}
+		newTag, err := tqb.ApplyEdit(*edit, operation, tag)
+		if err != nil {
+			return err
+		}
+
+		if operation == models.OperationEnumCreate {
+			editTag := models.EditTag{
+				EditID: edit.ID,
+				TagID:  newTag.ID,
+			}
+
+			return eqb.CreateEditTag(editTag)
+		}
+	case models.TargetTypeEnumPerformer:
+		pqb := models.NewPerformerQueryBuilder(tx)
+		var performer *models.Performer = nil
+		if operation != models.OperationEnumCreate {
+			performerID, err := eqb.FindPerformerID(edit.ID)
+			if err != nil {
+				return err
+			}
+			performer, err = pqb.Find(*performerID)
+			if err != nil {
+				return err
+			}
+			if performer == nil {
+				return errors.New("Performer not found: " + performerID.String())
+			}
+
+			snapshot, err := pqb.GetEditSnapshot(*edit, performer)
+			if err != nil {
+				return err
+			}
+			if err := edit.SetSnapshot(snapshot); err != nil {
+				return err
+			}
+		}
+		newPerformer, err := pqb.ApplyEdit(*edit, operation, performer)
+		if err != nil {
+			return err
+		}
+
+		if operation == models.OperationEnumCreate {
+			editPerformer := models.EditPerformer{
+				EditID:      edit.ID,
+				PerformerID: newPerformer.ID,
+			}
+
+			return eqb.CreateEditPerformer(editPerformer)
+		}
+	case models.TargetTypeEnumScene:
+		sqb := models.NewSceneQueryBuilder(tx)
+		var scene *models.Scene = nil
+		if operation != models.OperationEnumCreate {
+			sceneID, err := eqb.FindSceneID(edit.ID)
+			if err != nil {
+				return err
+			}
+			scene, err = sqb.Find(*sceneID)
+			if err != nil {
+				return err
+			}
+			if scene == nil {
+				return errors.New("Scene not found: " + sceneID.String())
+			}
+
+			snapshot, err := sqb.GetEditSnapshot(*edit, scene)
+			if err != nil {
+				return err
+			}
+			if err := edit.SetSnapshot(snapshot); err != nil {
+				return err
+			}
+		}
+		newScene, err := sqb.ApplyEdit(*edit, operation, scene)
+		if err != nil {
+			return err
+		}
+
+		if operation == models.OperationEnumCreate {
+			editScene := models.EditScene{
+				EditID:  edit.ID,
+				SceneID: newScene.ID,
+			}
+
+			return eqb.CreateEditScene(editScene)
+		}
+	case models.TargetTypeEnumStudio:
+		sqb := models.NewStudioQueryBuilder(tx)
+		var studio *models.Studio = nil
+		if operation != models.OperationEnumCreate {
+			studioID, err := eqb.FindStudioID(edit.ID)
+			if err != nil {
+				return err
+			}
+			studio, err = sqb.Find(*studioID)
+			if err != nil {
+				return err
+			}
+			if studio == nil {
+				return errors.New("Studio not found: " + studioID.String())
+			}
+
+			snapshot, err := sqb.GetEditSnapshot(*edit, studio)
+			if err != nil {
+				return err
+			}
+			if err := edit.SetSnapshot(snapshot); err != nil {
+				return err
+			}
+		}
+		newStudio, err := sqb.ApplyEdit(*edit, operation, studio)
+		if err != nil {
+			return err
+		}
+
+		if operation == models.OperationEnumCreate {
+			editStudio := models.EditStudio{
+				EditID:   edit.ID,
+				StudioID: newStudio.ID,
+			}
+
+			return eqb.CreateEditStudio(editStudio)
+		}
+	default:
+		return errors.New("Not implemented: " + edit.TargetType)
+	}
+
+	return nil
+}
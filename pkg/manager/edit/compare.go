@@ -0,0 +1,141 @@
+package edit
+
+import (
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// urlCompare returns the urls that are present in subject but not in
+// against - in the added slice - and the urls that are not present in
+// subject, and are in against - in the missing slice.
+func urlCompare(subject []*models.URLInput, against []*models.URL) (added []*models.URL, missing []*models.URL) {
+	var subjectUrls []*models.URL
+	for _, v := range subject {
+		subjectUrls = append(subjectUrls, &models.URL{URL: v.URL, Type: v.Type})
+	}
+
+	for _, v := range subjectUrls {
+		if !urlInclude(against, v) && !urlInclude(added, v) {
+			added = append(added, v)
+		}
+	}
+
+	for _, v := range against {
+		if !urlInclude(subjectUrls, v) && !urlInclude(missing, v) {
+			missing = append(missing, v)
+		}
+	}
+
+	return
+}
+
+func urlInclude(vs []*models.URL, t *models.URL) bool {
+	for _, v := range vs {
+		if v.URL == t.URL && v.Type == t.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyModCompare returns the body modifications that are present in subject
+// but not in against - in the added slice - and the body modifications that
+// are not present in subject, and are in against - in the missing slice.
+func bodyModCompare(subject []*models.BodyModificationInput, against []*models.BodyModification) (added []*models.BodyModification, missing []*models.BodyModification) {
+	var subjectMods []*models.BodyModification
+	for _, v := range subject {
+		subjectMods = append(subjectMods, &models.BodyModification{Location: v.Location, Description: v.Description})
+	}
+
+	for _, v := range subjectMods {
+		if !bodyModInclude(against, v) && !bodyModInclude(added, v) {
+			added = append(added, v)
+		}
+	}
+
+	for _, v := range against {
+		if !bodyModInclude(subjectMods, v) && !bodyModInclude(missing, v) {
+			missing = append(missing, v)
+		}
+	}
+
+	return
+}
+
+func bodyModInclude(vs []*models.BodyModification, t *models.BodyModification) bool {
+	for _, v := range vs {
+		if v.Location == t.Location && equalStringPtr(v.Description, t.Description) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStringPtr(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// performerAppearanceCompare returns the appearances that are present in
+// subject but not in against - in the added slice - and the appearances that
+// are not present in subject, and are in against - in the missing slice.
+// An appearance with a changed alias is included in both slices.
+func performerAppearanceCompare(subject []*models.PerformerAppearanceInput, against []*models.PerformerAppearanceInput) (added []*models.PerformerAppearanceInput, missing []*models.PerformerAppearanceInput) {
+	for _, v := range subject {
+		if !performerAppearanceInclude(against, v) && !performerAppearanceInclude(added, v) {
+			added = append(added, v)
+		}
+	}
+
+	for _, v := range against {
+		if !performerAppearanceInclude(subject, v) && !performerAppearanceInclude(missing, v) {
+			missing = append(missing, v)
+		}
+	}
+
+	return
+}
+
+func performerAppearanceInclude(vs []*models.PerformerAppearanceInput, t *models.PerformerAppearanceInput) bool {
+	for _, v := range vs {
+		if v.PerformerID == t.PerformerID && equalStringPtr(v.As, t.As) {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprintCompare returns the fingerprints that are present in subject
+// but not in against - in the added slice - and the fingerprints that are
+// not present in subject, and are in against - in the missing slice.
+// Fingerprints are compared by algorithm and hash only.
+func fingerprintCompare(subject []*models.FingerprintInput, against []*models.Fingerprint) (added []*models.Fingerprint, missing []*models.Fingerprint) {
+	var subjectFingerprints []*models.Fingerprint
+	for _, v := range subject {
+		subjectFingerprints = append(subjectFingerprints, &models.Fingerprint{Hash: v.Hash, Algorithm: v.Algorithm, Duration: v.Duration})
+	}
+
+	for _, v := range subjectFingerprints {
+		if !fingerprintInclude(against, v) && !fingerprintInclude(added, v) {
+			added = append(added, v)
+		}
+	}
+
+	for _, v := range against {
+		if !fingerprintInclude(subjectFingerprints, v) && !fingerprintInclude(missing, v) {
+			missing = append(missing, v)
+		}
+	}
+
+	return
+}
+
+func fingerprintInclude(vs []*models.Fingerprint, t *models.Fingerprint) bool {
+	for _, v := range vs {
+		if v.Hash == t.Hash && v.Algorithm == t.Algorithm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package edit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// FindConflicts returns the fields of the target of the pending edit that
+// were changed after the edit was proposed, and no longer have the value
+// expected by the edit. If the target was updated after the edit was
+// proposed, this is reported as a conflict on updated_at following any field
+// conflicts, since the edit may be based on stale values.
+func FindConflicts(tx *sqlx.Tx, edit *models.Edit) ([]*models.EditConflict, error) {
+	if edit.Status != models.VoteStatusEnumPending.String() {
+		return nil, nil
+	}
+	if edit.Operation != models.OperationEnumModify.String() && edit.Operation != models.OperationEnumMerge.String() {
+		return nil, nil
+	}
+
+	eqb := models.NewEditQueryBuilder(tx)
+	targetID, err := findTargetID(eqb, edit)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []*models.EditConflict
+	var updatedAt models.SQLiteTimestamp
+
+	switch edit.TargetType {
+	case models.TargetTypeEnumTag.String():
+		tqb := models.NewTagQueryBuilder(tx)
+		tag, err := tqb.Find(*targetID)
+		if err != nil || tag == nil {
+			return nil, err
+		}
+		data, err := edit.GetTagData()
+		if err != nil {
+			return nil, err
+		}
+		conflicts = tag.ModifyEditConflicts(*data)
+		updatedAt = tag.UpdatedAt
+	case models.TargetTypeEnumPerformer.String():
+		pqb := models.NewPerformerQueryBuilder(tx)
+		performer, err := pqb.Find(*targetID)
+		if err != nil || performer == nil {
+			return nil, err
+		}
+		data, err := edit.GetPerformerData()
+		if err != nil {
+			return nil, err
+		}
+		conflicts = performer.ModifyEditConflicts(*data)
+		updatedAt = performer.UpdatedAt
+	case models.TargetTypeEnumScene.String():
+		sqb := models.NewSceneQueryBuilder(tx)
+		scene, err := sqb.Find(*targetID)
+		if err != nil || scene == nil {
+			return nil, err
+		}
+		data, err := edit.GetSceneData()
+		if err != nil {
+			return nil, err
+		}
+		conflicts = scene.ModifyEditConflicts(*data)
+		updatedAt = scene.UpdatedAt
+	case models.TargetTypeEnumStudio.String():
+		sqb := models.NewStudioQueryBuilder(tx)
+		studio, err := sqb.Find(*targetID)
+		if err != nil || studio == nil {
+			return nil, err
+		}
+		data, err := edit.GetStudioData()
+		if err != nil {
+			return nil, err
+		}
+		conflicts = studio.ModifyEditConflicts(*data)
+		updatedAt = studio.UpdatedAt
+	default:
+		return nil, nil
+	}
+
+	if conflict := staleTargetConflict(edit, updatedAt); conflict != nil {
+		conflicts = append(conflicts, conflict)
+	}
+
+	return conflicts, nil
+}
+
+// staleTargetConflict returns a conflict if the target was updated after the
+// edit was proposed, or nil if it is unchanged.
+func staleTargetConflict(edit *models.Edit, updatedAt models.SQLiteTimestamp) *models.EditConflict {
+	if !edit.TargetUpdatedAt.Valid || !updatedAt.Timestamp.After(edit.TargetUpdatedAt.Timestamp) {
+		return nil
+	}
+
+	expected := edit.TargetUpdatedAt.Timestamp.Format(time.RFC3339)
+	current := updatedAt.Timestamp.Format(time.RFC3339)
+	return &models.EditConflict{
+		Field:    "updated_at",
+		Expected: &expected,
+		Current:  &current,
+	}
+}
+
+// validateConflicts returns an error if the target of the edit was changed
+// after the edit was proposed in a way that conflicts with the edit.
+func validateConflicts(tx *sqlx.Tx, edit *models.Edit) error {
+	conflicts, err := FindConflicts(tx, edit)
+	if err != nil || len(conflicts) == 0 {
+		return err
+	}
+
+	return errors.New("Edit conflicts with changes made to the target: " + conflicts[0].Error())
+}
@@ -0,0 +1,128 @@
+package edit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/manager/notification"
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// CreateEditGroup groups pending edits of the user so that they are voted on
+// and applied as one unit. Votes already cast on the edits were cast on them
+// individually, so they are flagged as outdated.
+func CreateEditGroup(tx *sqlx.Tx, user *models.User, edits []*models.Edit) (*models.EditGroup, error) {
+	if len(edits) < 2 {
+		return nil, errors.New("An edit group requires at least two edits")
+	}
+
+	grouped := make(map[uuid.UUID]bool)
+	for _, e := range edits {
+		if e.UserID != user.ID {
+			return nil, errors.New("Only the author of an edit can group it")
+		}
+		if e.Status != models.VoteStatusEnumPending.String() {
+			return nil, errors.New("Invalid vote status: " + e.Status)
+		}
+		if e.GroupID.Valid {
+			return nil, errors.New("Edit is already part of a group: " + e.ID.String())
+		}
+		if grouped[e.ID] {
+			return nil, errors.New("Duplicate edit: " + e.ID.String())
+		}
+		grouped[e.ID] = true
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	eqb := models.NewEditQueryBuilder(tx)
+	group, err := eqb.CreateGroup(*models.NewEditGroup(UUID, user))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range edits {
+		if err := eqb.OutdateVotes(e.ID); err != nil {
+			return nil, err
+		}
+
+		if err := eqb.AddToGroup(e.ID, group.ID); err != nil {
+			return nil, err
+		}
+		e.GroupID = uuid.NullUUID{UUID: group.ID, Valid: true}
+		e.VoteCount = 0
+	}
+
+	return group, nil
+}
+
+// groupEdits returns the edits voted on and applied together with the edit:
+// the members of its group, or the edit alone if it is not grouped. The
+// provided edit takes the place of its stored copy, so that changes made to
+// the returned edits are visible to the caller.
+func groupEdits(tx *sqlx.Tx, edit *models.Edit) ([]*models.Edit, error) {
+	if !edit.GroupID.Valid {
+		return []*models.Edit{edit}, nil
+	}
+
+	eqb := models.NewEditQueryBuilder(tx)
+	edits, err := eqb.FindByGroupID(edit.GroupID.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, e := range edits {
+		if e.ID == edit.ID {
+			edits[i] = edit
+		}
+	}
+
+	return edits, nil
+}
+
+// applyEdits applies each of the edits within the transaction. If any of
+// them cannot be applied the error is returned, and the caller is expected to
+// roll back the transaction, undoing the edits already applied.
+func applyEdits(tx *sqlx.Tx, edits []*models.Edit) error {
+	for _, e := range edits {
+		if err := ApplyEdit(tx, e); err != nil {
+			if len(edits) == 1 {
+				return err
+			}
+			return fmt.Errorf("Error applying edit %s: %s", e.ID.String(), err.Error())
+		}
+	}
+
+	return nil
+}
+
+// updateEdits saves the edits, notifying their authors of closed votes.
+func updateEdits(tx *sqlx.Tx, edits []*models.Edit) error {
+	eqb := models.NewEditQueryBuilder(tx)
+	for _, e := range edits {
+		if _, err := eqb.Update(*e); err != nil {
+			return err
+		}
+		if err := notification.OnEditStatusChange(tx, e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validatePending(edits []*models.Edit) error {
+	for _, e := range edits {
+		if e.Status != models.VoteStatusEnumPending.String() {
+			return errors.New("Invalid vote status: " + e.Status)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package edit
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// FindPerformerAsOf returns the performer as it was at the provided time, by
+// undoing the edits applied to it since then, starting with the most recent.
+// Only the performer fields are rebuilt, joined objects such as aliases and
+// urls are not. Merges of the performer into another performer are undone by
+// restoring it. Returns nil if the performer did not exist at that time.
+func FindPerformerAsOf(tx *sqlx.Tx, id uuid.UUID, asOf time.Time) (*models.Performer, error) {
+	pqb := models.NewPerformerQueryBuilder(tx)
+	performer, err := pqb.Find(id)
+	if err != nil || performer == nil {
+		return nil, err
+	}
+
+	if performer.CreatedAt.Timestamp.After(asOf) {
+		return nil, nil
+	}
+
+	eqb := models.NewEditQueryBuilder(tx)
+	edits, err := eqb.FindAppliedByPerformerID(id, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, edit := range edits {
+		merged, err := isPerformerMergeSource(edit, id)
+		if err != nil {
+			return nil, err
+		}
+		if merged {
+			// the performer was merged into the target, or restored by the
+			// revert of the merge
+			performer.Deleted = edit.RevertsID.Valid
+			continue
+		}
+
+		switch edit.Operation {
+		case models.OperationEnumCreate.String():
+			if !edit.RevertsID.Valid {
+				return nil, nil
+			}
+			// the revert of a destroy restored the deleted performer
+			performer.Deleted = true
+		case models.OperationEnumDestroy.String():
+			// the performer was destroyed, or its creation reverted
+			performer.Deleted = false
+		default:
+			data, err := edit.GetPerformerData()
+			if err != nil {
+				return nil, err
+			}
+			performer.UndoPerformerEdit(*data)
+		}
+	}
+
+	return performer, nil
+}
+
+func isPerformerMergeSource(edit *models.Edit, id uuid.UUID) (bool, error) {
+	data, err := edit.GetPerformerData()
+	if err != nil || data == nil {
+		return false, err
+	}
+
+	for _, source := range data.MergeSources {
+		if source == id.String() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
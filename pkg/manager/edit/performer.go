@@ -0,0 +1,188 @@
+package edit
+
+import (
+	"errors"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/models"
+	"github.com/stashapp/stashdb/pkg/utils"
+)
+
+func ModifyPerformerEdit(tx *sqlx.Tx, edit *models.Edit, input models.PerformerEditInput, inputSpecified InputSpecifiedFunc) error {
+	pqb := models.NewPerformerQueryBuilder(tx)
+
+	// get the existing performer
+	if input.Edit.ID == nil {
+		return errors.New("Edit target ID is required")
+	}
+	performerID, _ := uuid.FromString(*input.Edit.ID)
+	performer, err := pqb.Find(performerID)
+
+	if err != nil {
+		return err
+	}
+
+	if performer == nil {
+		return errors.New("performer with id " + performerID.String() + " not found")
+	}
+
+	edit.SetTargetUpdatedAt(performer.UpdatedAt)
+
+	// perform a diff against the input and the current object
+	performerEdit := input.Details.PerformerEditFromDiff(*performer)
+
+	if err := diffPerformerJoins(pqb, performerID, performerEdit.New, *input.Details, inputSpecified); err != nil {
+		return err
+	}
+
+	edit.SetData(performerEdit)
+	return nil
+}
+
+func MergePerformerEdit(tx *sqlx.Tx, edit *models.Edit, input models.PerformerEditInput, inputSpecified InputSpecifiedFunc) error {
+	pqb := models.NewPerformerQueryBuilder(tx)
+
+	// get the existing performer
+	if input.Edit.ID == nil {
+		return errors.New("Merge target ID is required")
+	}
+	performerID, _ := uuid.FromString(*input.Edit.ID)
+	performer, err := pqb.Find(performerID)
+
+	if err != nil {
+		return err
+	}
+
+	if performer == nil {
+		return errors.New("performer with id " + performerID.String() + " not found")
+	}
+
+	edit.SetTargetUpdatedAt(performer.UpdatedAt)
+
+	mergeSources := []string{}
+	for _, mergeSourceId := range input.Edit.MergeSourceIds {
+		sourceID, _ := uuid.FromString(mergeSourceId)
+		sourcePerformer, err := pqb.Find(sourceID)
+		if err != nil {
+			return err
+		}
+
+		if sourcePerformer == nil {
+			return errors.New("performer with id " + sourceID.String() + " not found")
+		}
+		if performerID == sourceID {
+			return errors.New("merge target cannot be used as source")
+		}
+		mergeSources = append(mergeSources, mergeSourceId)
+	}
+
+	if len(mergeSources) < 1 {
+		return errors.New("No merge sources found")
+	}
+
+	// perform a diff against the input and the current object
+	performerEdit := input.Details.PerformerEditFromMerge(*performer, mergeSources)
+
+	if err := diffPerformerJoins(pqb, performerID, performerEdit.New, *input.Details, inputSpecified); err != nil {
+		return err
+	}
+
+	edit.SetData(performerEdit)
+	return nil
+}
+
+func CreatePerformerEdit(tx *sqlx.Tx, edit *models.Edit, input models.PerformerEditInput, inputSpecified InputSpecifiedFunc) error {
+	performerEdit := input.Details.PerformerEditFromCreate()
+
+	performerEdit.New.AddedAliases = input.Details.Aliases
+	performerEdit.New.AddedImages = input.Details.ImageIds
+	performerEdit.New.AddedUrls, _ = urlCompare(input.Details.Urls, nil)
+	performerEdit.New.AddedTattoos, _ = bodyModCompare(input.Details.Tattoos, nil)
+	performerEdit.New.AddedPiercings, _ = bodyModCompare(input.Details.Piercings, nil)
+
+	edit.SetData(performerEdit)
+	return nil
+}
+
+func DestroyPerformerEdit(tx *sqlx.Tx, edit *models.Edit, input models.PerformerEditInput, inputSpecified InputSpecifiedFunc) error {
+	pqb := models.NewPerformerQueryBuilder(tx)
+
+	// get the existing performer
+	if input.Edit.ID == nil {
+		return errors.New("Edit target ID is required")
+	}
+	performerID, _ := uuid.FromString(*input.Edit.ID)
+	performer, err := pqb.Find(performerID)
+
+	if err != nil {
+		return err
+	}
+
+	if performer == nil {
+		return errors.New("performer with id " + performerID.String() + " not found")
+	}
+
+	edit.SetTargetUpdatedAt(performer.UpdatedAt)
+
+	return nil
+}
+
+// diffPerformerJoins populates the added and removed aliases, urls, tattoos,
+// piercings and images of the performer edit. Joins that were not specified
+// in the input are left unchanged.
+func diffPerformerJoins(pqb models.PerformerQueryBuilder, performerID uuid.UUID, performerEdit *models.PerformerEdit, input models.PerformerEditDetailsInput, inputSpecified InputSpecifiedFunc) error {
+	// determine unspecified aliases vs no aliases
+	if len(input.Aliases) != 0 || inputSpecified("aliases") {
+		aliases, err := pqb.GetAliases(performerID)
+		if err != nil {
+			return err
+		}
+
+		performerEdit.AddedAliases, performerEdit.RemovedAliases = utils.StrSliceCompare(input.Aliases, aliases)
+	}
+
+	if len(input.Urls) != 0 || inputSpecified("urls") {
+		urls, err := pqb.GetUrls(performerID)
+		if err != nil {
+			return err
+		}
+
+		performerEdit.AddedUrls, performerEdit.RemovedUrls = urlCompare(input.Urls, urls.ToURLs())
+	}
+
+	if len(input.Tattoos) != 0 || inputSpecified("tattoos") {
+		tattoos, err := pqb.GetTattoos(performerID)
+		if err != nil {
+			return err
+		}
+
+		performerEdit.AddedTattoos, performerEdit.RemovedTattoos = bodyModCompare(input.Tattoos, tattoos.ToBodyModifications())
+	}
+
+	if len(input.Piercings) != 0 || inputSpecified("piercings") {
+		piercings, err := pqb.GetPiercings(performerID)
+		if err != nil {
+			return err
+		}
+
+		performerEdit.AddedPiercings, performerEdit.RemovedPiercings = bodyModCompare(input.Piercings, piercings.ToBodyModifications())
+	}
+
+	if len(input.ImageIds) != 0 || inputSpecified("image_ids") {
+		images, err := pqb.GetImages(performerID)
+		if err != nil {
+			return err
+		}
+
+		var imageIds []string
+		for _, image := range images {
+			imageIds = append(imageIds, image.ImageID.String())
+		}
+
+		performerEdit.AddedImages, performerEdit.RemovedImages = utils.StrSliceCompare(input.ImageIds, imageIds)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package edit
+
+import (
+	"errors"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// PreviewEdit saves and applies the edit to its target, and returns the
+// target as it is after the edit was applied. The transaction must be rolled
+// back by the caller, so that none of the changes are kept.
+func PreviewEdit(tx *sqlx.Tx, edit *models.Edit, targetID *string) (models.EditTarget, error) {
+	eqb := models.NewEditQueryBuilder(tx)
+
+	created, err := eqb.Create(*edit)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetID != nil {
+		targetUUID, _ := uuid.FromString(*targetID)
+		if err := createTargetJoin(eqb, created, targetUUID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ApplyEdit(tx, created); err != nil {
+		return nil, err
+	}
+
+	resultID, err := findTargetID(eqb, created)
+	if err != nil {
+		return nil, err
+	}
+	if resultID == nil {
+		return nil, errors.New("Edit target not found")
+	}
+
+	switch created.TargetType {
+	case models.TargetTypeEnumTag.String():
+		tqb := models.NewTagQueryBuilder(tx)
+		tag, err := tqb.Find(*resultID)
+		if err != nil || tag == nil {
+			return nil, err
+		}
+		return tag, nil
+	case models.TargetTypeEnumPerformer.String():
+		pqb := models.NewPerformerQueryBuilder(tx)
+		performer, err := pqb.Find(*resultID)
+		if err != nil || performer == nil {
+			return nil, err
+		}
+		return performer, nil
+	case models.TargetTypeEnumScene.String():
+		sqb := models.NewSceneQueryBuilder(tx)
+		scene, err := sqb.Find(*resultID)
+		if err != nil || scene == nil {
+			return nil, err
+		}
+		return scene, nil
+	case models.TargetTypeEnumStudio.String():
+		sqb := models.NewStudioQueryBuilder(tx)
+		studio, err := sqb.Find(*resultID)
+		if err != nil || studio == nil {
+			return nil, err
+		}
+		return studio, nil
+	}
+
+	return nil, errors.New("Not implemented: " + created.TargetType)
+}
@@ -0,0 +1,181 @@
+package edit
+
+import (
+	"errors"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// RevertEdit undoes the changes of an applied edit, restoring the affected
+// objects from the snapshot taken when the edit was applied. The revert is
+// recorded as a new applied edit, linked to the original edit, whose details
+// are the inverse of the original details.
+func RevertEdit(tx *sqlx.Tx, original *models.Edit, user *models.User) (*models.Edit, error) {
+	if !original.Applied {
+		return nil, errors.New("Only applied edits can be reverted")
+	}
+
+	if original.RevertsID.Valid {
+		return nil, errors.New("Reverting edits cannot be reverted")
+	}
+
+	eqb := models.NewEditQueryBuilder(tx)
+
+	existingRevert, err := eqb.FindRevertOf(original.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existingRevert != nil {
+		return nil, errors.New("Edit has already been reverted")
+	}
+
+	var operation models.OperationEnum
+	if err := operation.UnmarshalGQL(original.Operation); err != nil {
+		return nil, err
+	}
+
+	targetID, err := findTargetID(eqb, original)
+	if err != nil {
+		return nil, err
+	}
+	if targetID == nil {
+		return nil, errors.New("Edit target not found")
+	}
+
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	revertEdit := models.NewRevertEdit(UUID, user, *original)
+
+	switch original.TargetType {
+	case models.TargetTypeEnumTag.String():
+		tqb := models.NewTagQueryBuilder(tx)
+		tag, err := tqb.Find(*targetID)
+		if err != nil {
+			return nil, err
+		}
+		if tag == nil {
+			return nil, errors.New("Tag not found: " + targetID.String())
+		}
+		if err := validateRevertTarget(original, tag.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := tqb.RevertEdit(*original, operation, tag); err != nil {
+			return nil, err
+		}
+		data, err := original.GetTagData()
+		if err != nil {
+			return nil, err
+		}
+		if err := revertEdit.SetData(data.Inverse()); err != nil {
+			return nil, err
+		}
+	case models.TargetTypeEnumPerformer.String():
+		pqb := models.NewPerformerQueryBuilder(tx)
+		performer, err := pqb.Find(*targetID)
+		if err != nil {
+			return nil, err
+		}
+		if performer == nil {
+			return nil, errors.New("Performer not found: " + targetID.String())
+		}
+		if err := validateRevertTarget(original, performer.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := pqb.RevertEdit(*original, operation, performer); err != nil {
+			return nil, err
+		}
+		data, err := original.GetPerformerData()
+		if err != nil {
+			return nil, err
+		}
+		if err := revertEdit.SetData(data.Inverse()); err != nil {
+			return nil, err
+		}
+	case models.TargetTypeEnumScene.String():
+		sqb := models.NewSceneQueryBuilder(tx)
+		scene, err := sqb.Find(*targetID)
+		if err != nil {
+			return nil, err
+		}
+		if scene == nil {
+			return nil, errors.New("Scene not found: " + targetID.String())
+		}
+		if err := validateRevertTarget(original, scene.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := sqb.RevertEdit(*original, operation, scene); err != nil {
+			return nil, err
+		}
+		data, err := original.GetSceneData()
+		if err != nil {
+			return nil, err
+		}
+		if err := revertEdit.SetData(data.Inverse()); err != nil {
+			return nil, err
+		}
+	case models.TargetTypeEnumStudio.String():
+		sqb := models.NewStudioQueryBuilder(tx)
+		studio, err := sqb.Find(*targetID)
+		if err != nil {
+			return nil, err
+		}
+		if studio == nil {
+			return nil, errors.New("Studio not found: " + targetID.String())
+		}
+		if err := validateRevertTarget(original, studio.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := sqb.RevertEdit(*original, operation, studio); err != nil {
+			return nil, err
+		}
+		data, err := original.GetStudioData()
+		if err != nil {
+			return nil, err
+		}
+		if err := revertEdit.SetData(data.Inverse()); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("Not implemented: " + original.TargetType)
+	}
+
+	created, err := eqb.Create(*revertEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := createTargetJoin(eqb, created, *targetID); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// validateRevertTarget returns an error if the target was changed after the
+// edit was applied, since reverting the edit would discard those changes.
+func validateRevertTarget(original *models.Edit, targetUpdatedAt models.SQLiteTimestamp) error {
+	if targetUpdatedAt.Timestamp.After(original.UpdatedAt.Timestamp) {
+		return errors.New("Edit cannot be reverted. The target has been modified since the edit was applied")
+	}
+	return nil
+}
+
+func createTargetJoin(eqb models.EditQueryBuilder, edit *models.Edit, targetID uuid.UUID) error {
+	switch edit.TargetType {
+	case models.TargetTypeEnumTag.String():
+		return eqb.CreateEditTag(models.EditTag{EditID: edit.ID, TagID: targetID})
+	case models.TargetTypeEnumPerformer.String():
+		return eqb.CreateEditPerformer(models.EditPerformer{EditID: edit.ID, PerformerID: targetID})
+	case models.TargetTypeEnumScene.String():
+		return eqb.CreateEditScene(models.EditScene{EditID: edit.ID, SceneID: targetID})
+	case models.TargetTypeEnumStudio.String():
+		return eqb.CreateEditStudio(models.EditStudio{EditID: edit.ID, StudioID: targetID})
+	}
+
+	return errors.New("Not implemented: " + edit.TargetType)
+}
@@ -0,0 +1,204 @@
+package edit
+
+import (
+	"errors"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/models"
+	"github.com/stashapp/stashdb/pkg/utils"
+)
+
+func ModifySceneEdit(tx *sqlx.Tx, edit *models.Edit, input models.SceneEditInput, inputSpecified InputSpecifiedFunc) error {
+	sqb := models.NewSceneQueryBuilder(tx)
+
+	// get the existing scene
+	if input.Edit.ID == nil {
+		return errors.New("Edit target ID is required")
+	}
+	sceneID, _ := uuid.FromString(*input.Edit.ID)
+	scene, err := sqb.Find(sceneID)
+
+	if err != nil {
+		return err
+	}
+
+	if scene == nil {
+		return errors.New("scene with id " + sceneID.String() + " not found")
+	}
+
+	edit.SetTargetUpdatedAt(scene.UpdatedAt)
+
+	// perform a diff against the input and the current object
+	sceneEdit := input.Details.SceneEditFromDiff(*scene)
+
+	if err := diffSceneJoins(sqb, sceneID, sceneEdit.New, *input.Details, inputSpecified); err != nil {
+		return err
+	}
+
+	edit.SetData(sceneEdit)
+	return nil
+}
+
+func MergeSceneEdit(tx *sqlx.Tx, edit *models.Edit, input models.SceneEditInput, inputSpecified InputSpecifiedFunc) error {
+	sqb := models.NewSceneQueryBuilder(tx)
+
+	// get the existing scene
+	if input.Edit.ID == nil {
+		return errors.New("Merge target ID is required")
+	}
+	sceneID, _ := uuid.FromString(*input.Edit.ID)
+	scene, err := sqb.Find(sceneID)
+
+	if err != nil {
+		return err
+	}
+
+	if scene == nil {
+		return errors.New("scene with id " + sceneID.String() + " not found")
+	}
+
+	edit.SetTargetUpdatedAt(scene.UpdatedAt)
+
+	mergeSources := []string{}
+	for _, mergeSourceId := range input.Edit.MergeSourceIds {
+		sourceID, _ := uuid.FromString(mergeSourceId)
+		sourceScene, err := sqb.Find(sourceID)
+		if err != nil {
+			return err
+		}
+
+		if sourceScene == nil {
+			return errors.New("scene with id " + sourceID.String() + " not found")
+		}
+		if sceneID == sourceID {
+			return errors.New("merge target cannot be used as source")
+		}
+		mergeSources = append(mergeSources, mergeSourceId)
+	}
+
+	if len(mergeSources) < 1 {
+		return errors.New("No merge sources found")
+	}
+
+	// perform a diff against the input and the current object
+	sceneEdit := input.Details.SceneEditFromMerge(*scene, mergeSources)
+
+	if err := diffSceneJoins(sqb, sceneID, sceneEdit.New, *input.Details, inputSpecified); err != nil {
+		return err
+	}
+
+	edit.SetData(sceneEdit)
+	return nil
+}
+
+func CreateSceneEdit(tx *sqlx.Tx, edit *models.Edit, input models.SceneEditInput, inputSpecified InputSpecifiedFunc) error {
+	sceneEdit := input.Details.SceneEditFromCreate()
+
+	sceneEdit.New.AddedUrls, _ = urlCompare(input.Details.Urls, nil)
+	sceneEdit.New.AddedPerformers, _ = performerAppearanceCompare(input.Details.Performers, nil)
+	sceneEdit.New.AddedTags = input.Details.TagIds
+	sceneEdit.New.AddedImages = input.Details.ImageIds
+	sceneEdit.New.AddedFingerprints, _ = fingerprintCompare(input.Details.Fingerprints, nil)
+
+	edit.SetData(sceneEdit)
+	return nil
+}
+
+func DestroySceneEdit(tx *sqlx.Tx, edit *models.Edit, input models.SceneEditInput, inputSpecified InputSpecifiedFunc) error {
+	sqb := models.NewSceneQueryBuilder(tx)
+
+	// get the existing scene
+	if input.Edit.ID == nil {
+		return errors.New("Edit target ID is required")
+	}
+	sceneID, _ := uuid.FromString(*input.Edit.ID)
+	scene, err := sqb.Find(sceneID)
+
+	if err != nil {
+		return err
+	}
+
+	if scene == nil {
+		return errors.New("scene with id " + sceneID.String() + " not found")
+	}
+
+	edit.SetTargetUpdatedAt(scene.UpdatedAt)
+
+	return nil
+}
+
+// diffSceneJoins populates the added and removed urls, performers, tags,
+// images and fingerprints of the scene edit. Joins that were not specified
+// in the input are left unchanged.
+func diffSceneJoins(sqb models.SceneQueryBuilder, sceneID uuid.UUID, sceneEdit *models.SceneEdit, input models.SceneEditDetailsInput, inputSpecified InputSpecifiedFunc) error {
+	if len(input.Urls) != 0 || inputSpecified("urls") {
+		urls, err := sqb.GetUrls(sceneID)
+		if err != nil {
+			return err
+		}
+
+		sceneEdit.AddedUrls, sceneEdit.RemovedUrls = urlCompare(input.Urls, urls.ToURLs())
+	}
+
+	if len(input.Performers) != 0 || inputSpecified("performers") {
+		performers, err := sqb.GetPerformers(sceneID)
+		if err != nil {
+			return err
+		}
+
+		var appearances []*models.PerformerAppearanceInput
+		for _, performer := range performers {
+			appearance := &models.PerformerAppearanceInput{
+				PerformerID: performer.PerformerID.String(),
+			}
+			if performer.As.Valid {
+				as := performer.As.String
+				appearance.As = &as
+			}
+			appearances = append(appearances, appearance)
+		}
+
+		sceneEdit.AddedPerformers, sceneEdit.RemovedPerformers = performerAppearanceCompare(input.Performers, appearances)
+	}
+
+	if len(input.TagIds) != 0 || inputSpecified("tag_ids") {
+		tags, err := sqb.GetTags(sceneID)
+		if err != nil {
+			return err
+		}
+
+		var tagIds []string
+		for _, tag := range tags {
+			tagIds = append(tagIds, tag.TagID.String())
+		}
+
+		sceneEdit.AddedTags, sceneEdit.RemovedTags = utils.StrSliceCompare(input.TagIds, tagIds)
+	}
+
+	if len(input.ImageIds) != 0 || inputSpecified("image_ids") {
+		images, err := sqb.GetImages(sceneID)
+		if err != nil {
+			return err
+		}
+
+		var imageIds []string
+		for _, image := range images {
+			imageIds = append(imageIds, image.ImageID.String())
+		}
+
+		sceneEdit.AddedImages, sceneEdit.RemovedImages = utils.StrSliceCompare(input.ImageIds, imageIds)
+	}
+
+	if len(input.Fingerprints) != 0 || inputSpecified("fingerprints") {
+		fingerprints, err := sqb.GetFingerprints(sceneID)
+		if err != nil {
+			return err
+		}
+
+		sceneEdit.AddedFingerprints, sceneEdit.RemovedFingerprints = fingerprintCompare(input.Fingerprints, fingerprints)
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package edit
+
+import (
+	"errors"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/models"
+	"github.com/stashapp/stashdb/pkg/utils"
+)
+
+func ModifyStudioEdit(tx *sqlx.Tx, edit *models.Edit, input models.StudioEditInput, inputSpecified InputSpecifiedFunc) error {
+	sqb := models.NewStudioQueryBuilder(tx)
+
+	// get the existing studio
+	if input.Edit.ID == nil {
+		return errors.New("Edit target ID is required")
+	}
+	studioID, _ := uuid.FromString(*input.Edit.ID)
+	studio, err := sqb.Find(studioID)
+
+	if err != nil {
+		return err
+	}
+
+	if studio == nil {
+		return errors.New("studio with id " + studioID.String() + " not found")
+	}
+
+	edit.SetTargetUpdatedAt(studio.UpdatedAt)
+
+	if err := validateStudioHierarchy(studioID, *input.Details); err != nil {
+		return err
+	}
+
+	// perform a diff against the input and the current object
+	studioEdit := input.Details.StudioEditFromDiff(*studio)
+	diffStudioParent(*studio, &studioEdit, *input.Details, inputSpecified)
+
+	if err := diffStudioJoins(sqb, studioID, studioEdit.New, *input.Details, inputSpecified); err != nil {
+		return err
+	}
+
+	edit.SetData(studioEdit)
+	return nil
+}
+
+func MergeStudioEdit(tx *sqlx.Tx, edit *models.Edit, input models.StudioEditInput, inputSpecified InputSpecifiedFunc) error {
+	sqb := models.NewStudioQueryBuilder(tx)
+
+	// get the existing studio
+	if input.Edit.ID == nil {
+		return errors.New("Merge target ID is required")
+	}
+	studioID, _ := uuid.FromString(*input.Edit.ID)
+	studio, err := sqb.Find(studioID)
+
+	if err != nil {
+		return err
+	}
+
+	if studio == nil {
+		return errors.New("studio with id " + studioID.String() + " not found")
+	}
+
+	edit.SetTargetUpdatedAt(studio.UpdatedAt)
+
+	if err := validateStudioHierarchy(studioID, *input.Details); err != nil {
+		return err
+	}
+
+	mergeSources := []string{}
+	for _, mergeSourceId := range input.Edit.MergeSourceIds {
+		sourceID, _ := uuid.FromString(mergeSourceId)
+		sourceStudio, err := sqb.Find(sourceID)
+		if err != nil {
+			return err
+		}
+
+		if sourceStudio == nil {
+			return errors.New("studio with id " + sourceID.String() + " not found")
+		}
+		if studioID == sourceID {
+			return errors.New("merge target cannot be used as source")
+		}
+		mergeSources = append(mergeSources, mergeSourceId)
+	}
+
+	if len(mergeSources) < 1 {
+		return errors.New("No merge sources found")
+	}
+
+	// perform a diff against the input and the current object
+	studioEdit := input.Details.StudioEditFromMerge(*studio, mergeSources)
+	diffStudioParent(*studio, &studioEdit, *input.Details, inputSpecified)
+
+	if err := diffStudioJoins(sqb, studioID, studioEdit.New, *input.Details, inputSpecified); err != nil {
+		return err
+	}
+
+	edit.SetData(studioEdit)
+	return nil
+}
+
+func CreateStudioEdit(tx *sqlx.Tx, edit *models.Edit, input models.StudioEditInput, inputSpecified InputSpecifiedFunc) error {
+	studioEdit := input.Details.StudioEditFromCreate()
+
+	studioEdit.New.AddedUrls, _ = urlCompare(input.Details.Urls, nil)
+	studioEdit.New.AddedChildStudios = input.Details.ChildStudioIds
+	studioEdit.New.AddedImages = input.Details.ImageIds
+
+	edit.SetData(studioEdit)
+	return nil
+}
+
+func DestroyStudioEdit(tx *sqlx.Tx, edit *models.Edit, input models.StudioEditInput, inputSpecified InputSpecifiedFunc) error {
+	sqb := models.NewStudioQueryBuilder(tx)
+
+	// get the existing studio
+	if input.Edit.ID == nil {
+		return errors.New("Edit target ID is required")
+	}
+	studioID, _ := uuid.FromString(*input.Edit.ID)
+	studio, err := sqb.Find(studioID)
+
+	if err != nil {
+		return err
+	}
+
+	if studio == nil {
+		return errors.New("studio with id " + studioID.String() + " not found")
+	}
+
+	edit.SetTargetUpdatedAt(studio.UpdatedAt)
+
+	return nil
+}
+
+// validateStudioHierarchy returns an error if the studio is set as its own
+// parent or child. Longer cycles are detected when the edit is applied.
+func validateStudioHierarchy(studioID uuid.UUID, input models.StudioEditDetailsInput) error {
+	if input.ParentID != nil && *input.ParentID == studioID.String() {
+		return errors.New("studio cannot be its own parent")
+	}
+
+	for _, childID := range input.ChildStudioIds {
+		if childID == studioID.String() {
+			return errors.New("studio cannot be its own child")
+		}
+		if input.ParentID != nil && childID == *input.ParentID {
+			return errors.New("parent studio cannot also be a child studio")
+		}
+	}
+
+	return nil
+}
+
+// diffStudioParent flags the removal of the parent of the studio if the
+// parent is explicitly set to null in the input.
+func diffStudioParent(studio models.Studio, studioEdit *models.StudioEditData, input models.StudioEditDetailsInput, inputSpecified InputSpecifiedFunc) {
+	if input.ParentID != nil || !inputSpecified("parent_id") || !studio.ParentStudioID.Valid {
+		return
+	}
+
+	parentID := studio.ParentStudioID.UUID.String()
+	studioEdit.New.RemoveParent = true
+	studioEdit.Old.ParentID = &parentID
+}
+
+// diffStudioJoins populates the added and removed urls, child studios and
+// images of the studio edit. Joins that were not specified in the input are
+// left unchanged.
+func diffStudioJoins(sqb models.StudioQueryBuilder, studioID uuid.UUID, studioEdit *models.StudioEdit, input models.StudioEditDetailsInput, inputSpecified InputSpecifiedFunc) error {
+	if len(input.Urls) != 0 || inputSpecified("urls") {
+		urls, err := sqb.GetUrls(studioID)
+		if err != nil {
+			return err
+		}
+
+		studioEdit.AddedUrls, studioEdit.RemovedUrls = urlCompare(input.Urls, urls.ToURLs())
+	}
+
+	if len(input.ChildStudioIds) != 0 || inputSpecified("child_studio_ids") {
+		children, err := sqb.FindByParentID(studioID)
+		if err != nil {
+			return err
+		}
+
+		var childIds []string
+		for _, child := range children {
+			childIds = append(childIds, child.ID.String())
+		}
+
+		studioEdit.AddedChildStudios, studioEdit.RemovedChildStudios = utils.StrSliceCompare(input.ChildStudioIds, childIds)
+	}
+
+	if len(input.ImageIds) != 0 || inputSpecified("image_ids") {
+		images, err := sqb.GetImages(studioID)
+		if err != nil {
+			return err
+		}
+
+		var imageIds []string
+		for _, image := range images {
+			imageIds = append(imageIds, image.ImageID.String())
+		}
+
+		studioEdit.AddedImages, studioEdit.RemovedImages = utils.StrSliceCompare(input.ImageIds, imageIds)
+	}
+
+	return nil
+}
@@ -30,6 +30,8 @@ func ModifyTagEdit(tx *sqlx.Tx, edit *models.Edit, input models.TagEditInput, in
 		return errors.New("tag with id " + tagID.String() + " not found")
 	}
 
+	edit.SetTargetUpdatedAt(tag.UpdatedAt)
+
 	// perform a diff against the input and the current object
 	tagEdit := input.Details.TagEditFromDiff(*tag)
 
@@ -66,6 +68,8 @@ func MergeTagEdit(tx *sqlx.Tx, edit *models.Edit, input models.TagEditInput, inp
 		return errors.New("tag with id " + tagID.String() + " not found")
 	}
 
+	edit.SetTargetUpdatedAt(tag.UpdatedAt)
+
 	mergeSources := []string{}
 	for _, mergeSourceId := range input.Edit.MergeSourceIds {
 		sourceID, _ := uuid.FromString(mergeSourceId)
@@ -122,11 +126,17 @@ func DestroyTagEdit(tx *sqlx.Tx, edit *models.Edit, input models.TagEditInput, i
 
 	// get the existing tag
 	tagID, _ := uuid.FromString(*input.Edit.ID)
-	_, err := tqb.Find(tagID)
+	tag, err := tqb.Find(tagID)
 
 	if err != nil {
 		return err
 	}
 
+	if tag == nil {
+		return errors.New("tag with id " + tagID.String() + " not found")
+	}
+
+	edit.SetTargetUpdatedAt(tag.UpdatedAt)
+
 	return nil
 }
@@ -0,0 +1,107 @@
+package edit
+
+import (
+	"encoding/json"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/logger"
+	"github.com/stashapp/stashdb/pkg/manager/config"
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// IsTrustedEditor returns true if the low risk edits of the user are applied
+// without a vote. This is the case for users with the TRUSTED_EDIT role, and
+// for users with at least the configured number of successful edits.
+func IsTrustedEditor(tx *sqlx.Tx, user *models.User, roles []models.RoleEnum) (bool, error) {
+	// admins apply edits explicitly, so only the role itself is considered
+	for _, role := range roles {
+		if role == models.RoleEnumTrustedEdit {
+			return true, nil
+		}
+	}
+
+	threshold := config.GetTrustedEditThreshold()
+	if threshold <= 0 {
+		return false, nil
+	}
+
+	uqb := models.NewUserQueryBuilder(tx)
+	stats, errs := uqb.GetAllEditStats([]uuid.UUID{user.ID})
+	if errs != nil {
+		return false, errs[0]
+	}
+
+	return stats[0].SuccessfulEdits >= threshold, nil
+}
+
+// IsLowRisk returns true if the edit only changes the given edit detail
+// fields. Only modifications can be low risk, since creating, destroying and
+// merging objects always require a vote.
+func IsLowRisk(edit *models.Edit, fields []string) (bool, error) {
+	if edit.Operation != models.OperationEnumModify.String() || edit.RevertsID.Valid {
+		return false, nil
+	}
+
+	data := edit.GetData()
+	if data == nil || data.New == nil {
+		return false, nil
+	}
+
+	// the detail fields are omitted from the edit data when unchanged
+	var changed map[string]json.RawMessage
+	if err := json.Unmarshal(*data.New, &changed); err != nil {
+		return false, err
+	}
+
+	if len(changed) == 0 {
+		return false, nil
+	}
+
+	allowed := make(map[string]bool)
+	for _, field := range fields {
+		allowed[field] = true
+	}
+
+	for field := range changed {
+		if !allowed[field] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// ApplyTrustedEdit immediately applies the new edit of a trusted editor if
+// it only changes the fields configured as low risk. The edit is recorded as
+// immediately accepted, and may be reverted like any other applied edit. If
+// the edit cannot be applied, such as when it conflicts with the target, its
+// changes are rolled back and it is left pending for a vote.
+// Returns true if the edit was applied.
+func ApplyTrustedEdit(tx *sqlx.Tx, edit *models.Edit, user *models.User, roles []models.RoleEnum) (bool, error) {
+	lowRisk, err := IsLowRisk(edit, config.GetTrustedEditFields())
+	if err != nil || !lowRisk {
+		return false, err
+	}
+
+	trusted, err := IsTrustedEditor(tx, user, roles)
+	if err != nil || !trusted {
+		return false, err
+	}
+
+	if _, err := tx.Exec("SAVEPOINT apply_trusted_edit"); err != nil {
+		return false, err
+	}
+
+	pending := *edit
+	if err := ImmediateAccept(tx, edit); err != nil {
+		logger.Infof("Trusted edit %s left pending: %s", edit.ID.String(), err.Error())
+		*edit = pending
+		_, err := tx.Exec("ROLLBACK TO SAVEPOINT apply_trusted_edit")
+		return false, err
+	}
+
+	_, err = tx.Exec("RELEASE SAVEPOINT apply_trusted_edit")
+	return err == nil, err
+}
@@ -0,0 +1,199 @@
+package edit
+
+import (
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/manager/config"
+	"github.com/stashapp/stashdb/pkg/manager/notification"
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// CreateVote records the vote of the user on the pending edit and updates
+// the vote count of the edit. If the vote count reaches the configured vote
+// threshold, the edit is accepted and applied, or rejected. Immediate votes
+// accept or reject the edit regardless of the vote count, and must be
+// authorised by the caller. A vote on a grouped edit is cast on every edit
+// of the group.
+func CreateVote(tx *sqlx.Tx, edit *models.Edit, user *models.User, voteType models.VoteTypeEnum, comment *string) error {
+	edits, err := groupEdits(tx, edit)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePending(edits); err != nil {
+		return err
+	}
+
+	immediate := voteType == models.VoteTypeEnumImmediateAccept || voteType == models.VoteTypeEnumImmediateReject
+	if !immediate && edit.UserID == user.ID {
+		return errors.New("Users cannot vote on their own edits")
+	}
+
+	eqb := models.NewEditQueryBuilder(tx)
+	for _, e := range edits {
+		vote := models.NewEditVote(user, e, voteType, comment)
+		if err := eqb.CreateVote(*vote); err != nil {
+			return err
+		}
+	}
+
+	switch voteType {
+	case models.VoteTypeEnumImmediateAccept:
+		return ImmediateAccept(tx, edit)
+	case models.VoteTypeEnumImmediateReject:
+		return ImmediateReject(tx, edit)
+	}
+
+	if err := notification.OnEditVote(tx, edit, user); err != nil {
+		return err
+	}
+
+	voteCount, err := groupVoteCount(eqb, edits)
+	if err != nil {
+		return err
+	}
+
+	threshold := config.GetVoteApplicationThreshold()
+	if threshold > 0 && voteCount >= threshold {
+		if err := applyEdits(tx, edits); err != nil {
+			return err
+		}
+		for _, e := range edits {
+			e.Accept()
+		}
+	} else if threshold > 0 && voteCount <= -threshold {
+		for _, e := range edits {
+			e.Reject()
+		}
+	}
+
+	return updateEdits(tx, edits)
+}
+
+func countVotes(eqb models.EditQueryBuilder, edit *models.Edit) error {
+	votes, err := eqb.GetVotes(edit.ID)
+	if err != nil {
+		return err
+	}
+
+	edit.VoteCount = 0
+	for _, v := range votes {
+		if v.Outdated {
+			continue
+		}
+		if v.Vote == models.VoteTypeEnumAccept.String() {
+			edit.VoteCount++
+		} else if v.Vote == models.VoteTypeEnumReject.String() {
+			edit.VoteCount--
+		}
+	}
+
+	return eqb.UpdateVoteCount(edit.ID, edit.VoteCount)
+}
+
+// groupVoteCount recounts the votes of each of the edits, and returns the
+// lowest vote count among them. Votes are cast on every edit of a group, so
+// the counts only differ if votes were outdated on some of them, in which
+// case the group is decided by its least supported edit.
+func groupVoteCount(eqb models.EditQueryBuilder, edits []*models.Edit) (int, error) {
+	var ret int
+	for i, e := range edits {
+		if err := countVotes(eqb, e); err != nil {
+			return 0, err
+		}
+		if i == 0 || e.VoteCount < ret {
+			ret = e.VoteCount
+		}
+	}
+
+	return ret, nil
+}
+
+// ImmediateAccept applies the pending edit, along with the rest of its group,
+// bypassing the vote.
+func ImmediateAccept(tx *sqlx.Tx, edit *models.Edit) error {
+	edits, err := groupEdits(tx, edit)
+	if err != nil {
+		return err
+	}
+
+	if err := applyEdits(tx, edits); err != nil {
+		return err
+	}
+	for _, e := range edits {
+		e.ImmediateAccept()
+	}
+
+	return updateEdits(tx, edits)
+}
+
+// ImmediateReject rejects the pending edit, along with the rest of its group,
+// bypassing the vote.
+func ImmediateReject(tx *sqlx.Tx, edit *models.Edit) error {
+	edits, err := groupEdits(tx, edit)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePending(edits); err != nil {
+		return err
+	}
+	for _, e := range edits {
+		e.ImmediateReject()
+	}
+
+	return updateEdits(tx, edits)
+}
+
+// CloseVoting accepts and applies the pending edit, along with the rest of
+// its group, if every edit of the group has a positive vote count, and
+// rejects them otherwise.
+func CloseVoting(tx *sqlx.Tx, edit *models.Edit) error {
+	edits, err := groupEdits(tx, edit)
+	if err != nil {
+		return err
+	}
+
+	voteCount, err := groupVoteCount(models.NewEditQueryBuilder(tx), edits)
+	if err != nil {
+		return err
+	}
+
+	if voteCount > 0 {
+		if err := applyEdits(tx, edits); err != nil {
+			return err
+		}
+		for _, e := range edits {
+			e.Accept()
+		}
+	} else {
+		if err := validatePending(edits); err != nil {
+			return err
+		}
+		for _, e := range edits {
+			e.Reject()
+		}
+	}
+
+	return updateEdits(tx, edits)
+}
+
+// RejectEdit rejects the pending edit, along with the rest of its group,
+// without applying it.
+func RejectEdit(tx *sqlx.Tx, edit *models.Edit) error {
+	edits, err := groupEdits(tx, edit)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePending(edits); err != nil {
+		return err
+	}
+	for _, e := range edits {
+		e.Reject()
+	}
+
+	return updateEdits(tx, edits)
+}
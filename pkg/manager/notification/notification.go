@@ -0,0 +1,178 @@
+package notification
+
+import (
+	"errors"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// OnEditVote notifies the author of the edit of a vote cast by another user.
+func OnEditVote(tx *sqlx.Tx, edit *models.Edit, voter *models.User) error {
+	if edit.UserID == voter.ID {
+		return nil
+	}
+
+	return notify(tx, edit.UserID, edit, models.NotificationEnumEditVote)
+}
+
+// OnEditComment notifies the author of the edit of a comment made by another
+// user.
+func OnEditComment(tx *sqlx.Tx, edit *models.Edit, commenter *models.User) error {
+	if edit.UserID == commenter.ID {
+		return nil
+	}
+
+	return notify(tx, edit.UserID, edit, models.NotificationEnumEditComment)
+}
+
+// OnEditStatusChange notifies the author of the edit that voting on the edit
+// has closed. If the edit was applied, the users subscribed to its target or
+// merge sources are notified as well.
+func OnEditStatusChange(tx *sqlx.Tx, edit *models.Edit) error {
+	var notificationType models.NotificationEnum
+	switch edit.Status {
+	case models.VoteStatusEnumAccepted.String(), models.VoteStatusEnumImmediateAccepted.String():
+		notificationType = models.NotificationEnumEditApplied
+	case models.VoteStatusEnumRejected.String():
+		notificationType = models.NotificationEnumEditRejected
+	case models.VoteStatusEnumImmediateRejected.String():
+		notificationType = models.NotificationEnumEditCancelled
+	default:
+		return nil
+	}
+
+	if err := notify(tx, edit.UserID, edit, notificationType); err != nil {
+		return err
+	}
+
+	if !edit.Applied {
+		return nil
+	}
+
+	return notifySubscribers(tx, edit)
+}
+
+func notifySubscribers(tx *sqlx.Tx, edit *models.Edit) error {
+	targetIDs, err := findAffectedIDs(tx, edit)
+	if err != nil {
+		return err
+	}
+
+	nqb := models.NewNotificationQueryBuilder(tx)
+	subscriptions, err := nqb.FindSubscribers(targetIDs)
+	if err != nil {
+		return err
+	}
+
+	// users are notified once, however many of the affected objects they
+	// are subscribed to
+	notified := map[uuid.UUID]bool{
+		edit.UserID: true,
+	}
+	for _, s := range subscriptions {
+		if notified[s.UserID] {
+			continue
+		}
+		notified[s.UserID] = true
+
+		if err := notify(tx, s.UserID, edit, models.NotificationEnumWatchedEdit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findAffectedIDs returns the ids of the target and merge sources of the
+// applied edit.
+func findAffectedIDs(tx *sqlx.Tx, edit *models.Edit) ([]uuid.UUID, error) {
+	eqb := models.NewEditQueryBuilder(tx)
+
+	var targetID *uuid.UUID
+	var err error
+	switch edit.TargetType {
+	case models.TargetTypeEnumTag.String():
+		targetID, err = eqb.FindTagID(edit.ID)
+	case models.TargetTypeEnumPerformer.String():
+		targetID, err = eqb.FindPerformerID(edit.ID)
+	case models.TargetTypeEnumScene.String():
+		targetID, err = eqb.FindSceneID(edit.ID)
+	case models.TargetTypeEnumStudio.String():
+		targetID, err = eqb.FindStudioID(edit.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []uuid.UUID
+	if targetID != nil {
+		ret = append(ret, *targetID)
+	}
+
+	if data := edit.GetData(); data != nil {
+		for _, id := range data.MergeSources {
+			sourceID, err := uuid.FromString(id)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, sourceID)
+		}
+	}
+
+	return ret, nil
+}
+
+func notify(tx *sqlx.Tx, userID uuid.UUID, edit *models.Edit, notificationType models.NotificationEnum) error {
+	UUID, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	nqb := models.NewNotificationQueryBuilder(tx)
+	return nqb.Create(*models.NewNotification(UUID, userID, edit, notificationType))
+}
+
+// Subscribe subscribes the user to notifications on the edits applied to
+// the target, or unsubscribes the user if subscribed is false.
+func Subscribe(tx *sqlx.Tx, user *models.User, targetType models.TargetTypeEnum, targetID uuid.UUID, subscribed bool) error {
+	nqb := models.NewNotificationQueryBuilder(tx)
+	if !subscribed {
+		return nqb.DestroySubscription(user.ID, targetID)
+	}
+
+	found, err := targetExists(tx, targetType, targetID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("Target not found: " + targetID.String())
+	}
+
+	return nqb.CreateSubscription(*models.NewNotificationSubscription(user, targetType, targetID))
+}
+
+func targetExists(tx *sqlx.Tx, targetType models.TargetTypeEnum, targetID uuid.UUID) (bool, error) {
+	switch targetType {
+	case models.TargetTypeEnumTag:
+		qb := models.NewTagQueryBuilder(tx)
+		target, err := qb.Find(targetID)
+		return target != nil, err
+	case models.TargetTypeEnumPerformer:
+		qb := models.NewPerformerQueryBuilder(tx)
+		target, err := qb.Find(targetID)
+		return target != nil, err
+	case models.TargetTypeEnumScene:
+		qb := models.NewSceneQueryBuilder(tx)
+		target, err := qb.Find(targetID)
+		return target != nil, err
+	case models.TargetTypeEnumStudio:
+		qb := models.NewStudioQueryBuilder(tx)
+		target, err := qb.Find(targetID)
+		return target != nil, err
+	}
+
+	return false, errors.New("Not implemented: " + targetType.String())
+}
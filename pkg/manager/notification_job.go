@@ -0,0 +1,95 @@
+package manager
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/logger"
+	"github.com/stashapp/stashdb/pkg/manager/config"
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// StartNotificationDigestJob starts a background job that emails users a
+// digest of their unread notifications. The job is not started if the digest
+// interval is not configured.
+func (s *singleton) StartNotificationDigestJob() {
+	interval := config.GetNotificationDigestInterval()
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.sendNotificationDigests()
+		}
+	}()
+}
+
+func (s *singleton) sendNotificationDigests() {
+	nqb := models.NewNotificationQueryBuilder(nil)
+	notifications, err := nqb.FindUnreadNotEmailed()
+	if err != nil {
+		logger.Errorf("Error finding unread notifications: %s", err.Error())
+		return
+	}
+
+	byUser := make(map[uuid.UUID]models.Notifications)
+	var userIDs []uuid.UUID
+	for _, n := range notifications {
+		if _, found := byUser[n.UserID]; !found {
+			userIDs = append(userIDs, n.UserID)
+		}
+		byUser[n.UserID] = append(byUser[n.UserID], n)
+	}
+
+	uqb := models.NewUserQueryBuilder(nil)
+	for _, userID := range userIDs {
+		user, err := uqb.Find(userID)
+		if err != nil || user == nil || user.Email == "" {
+			continue
+		}
+
+		userNotifications := byUser[userID]
+		if err := s.EmailManager.SendNotification(user.Email, "Subject: stash-box notifications", notificationDigestBody(userNotifications)); err != nil {
+			logger.Errorf("Error emailing notifications to user %s: %s", user.Name, err.Error())
+			continue
+		}
+
+		var ids []uuid.UUID
+		for _, n := range userNotifications {
+			ids = append(ids, n.ID)
+		}
+		err = withTxn(func(tx *sqlx.Tx) error {
+			nqb := models.NewNotificationQueryBuilder(tx)
+			return nqb.MarkEmailed(ids)
+		})
+		if err != nil {
+			logger.Errorf("Error marking notifications as emailed: %s", err.Error())
+		}
+	}
+}
+
+var notificationDigestMessages = map[string]string{
+	models.NotificationEnumEditVote.String():      "Your edit received a vote",
+	models.NotificationEnumEditComment.String():   "Your edit received a comment",
+	models.NotificationEnumEditApplied.String():   "Your edit was applied",
+	models.NotificationEnumEditRejected.String():  "Your edit was rejected",
+	models.NotificationEnumEditCancelled.String(): "Your edit was cancelled",
+	models.NotificationEnumWatchedEdit.String():   "An edit was applied to an object you are watching",
+}
+
+func notificationDigestBody(notifications models.Notifications) string {
+	var lines []string
+	for _, n := range notifications {
+		link := config.GetHostURL() + "/edits/" + n.EditID.String()
+		lines = append(lines, notificationDigestMessages[n.Type]+": "+link)
+	}
+
+	return "You have unread stash-box notifications:\r\n\r\n" + strings.Join(lines, "\r\n")
+}
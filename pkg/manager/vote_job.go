@@ -0,0 +1,79 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/database"
+	"github.com/stashapp/stashdb/pkg/logger"
+	"github.com/stashapp/stashdb/pkg/manager/config"
+	"github.com/stashapp/stashdb/pkg/manager/edit"
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// how often pending edits are checked for an elapsed voting period
+const voteJobInterval = 5 * time.Minute
+
+// StartVoteJob starts a background job that closes voting on pending edits
+// once the voting period has elapsed. Edits with a positive vote count are
+// accepted and applied, all others are rejected.
+func (s *singleton) StartVoteJob() {
+	go func() {
+		ticker := time.NewTicker(voteJobInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			closeExpiredVotes()
+		}
+	}()
+}
+
+func closeExpiredVotes() {
+	eqb := models.NewEditQueryBuilder(nil)
+	expiry := time.Now().Add(-config.GetVotingPeriod())
+	edits, err := eqb.FindPendingBefore(expiry)
+	if err != nil {
+		logger.Errorf("Error finding pending edits: %s", err.Error())
+		return
+	}
+
+	// grouped edits are closed together with the first edit of their group
+	closedGroups := make(map[uuid.UUID]bool)
+	for _, pendingEdit := range edits {
+		if pendingEdit.GroupID.Valid {
+			if closedGroups[pendingEdit.GroupID.UUID] {
+				continue
+			}
+			closedGroups[pendingEdit.GroupID.UUID] = true
+		}
+
+		err := withTxn(func(tx *sqlx.Tx) error {
+			return edit.CloseVoting(tx, pendingEdit)
+		})
+		if err == nil {
+			continue
+		}
+
+		// edits that can no longer be applied are rejected
+		logger.Errorf("Error closing voting on edit %s: %s", pendingEdit.ID.String(), err.Error())
+		err = withTxn(func(tx *sqlx.Tx) error {
+			return edit.RejectEdit(tx, pendingEdit)
+		})
+		if err != nil {
+			logger.Errorf("Error rejecting edit %s: %s", pendingEdit.ID.String(), err.Error())
+		}
+	}
+}
+
+func withTxn(fn func(tx *sqlx.Tx) error) error {
+	tx := database.DB.MustBeginTx(context.Background(), nil)
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
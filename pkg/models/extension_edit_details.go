@@ -1,5 +1,7 @@
 package models
 
+import "database/sql"
+
 func (e TagEditDetailsInput) TagEditFromDiff(orig Tag) TagEditData {
 	newData := &TagEdit{}
 	oldData := &TagEdit{}
@@ -46,3 +48,217 @@ func (e TagEditDetailsInput) TagEditFromCreate() TagEditData {
 		New: newData,
 	}
 }
+
+func (e PerformerEditDetailsInput) PerformerEditFromDiff(orig Performer) PerformerEditData {
+	newData := &PerformerEdit{}
+	oldData := &PerformerEdit{}
+
+	if e.Name != nil && *e.Name != orig.Name {
+		newName := *e.Name
+		newData.Name = &newName
+		oldData.Name = &orig.Name
+	}
+
+	diffNullString(e.Disambiguation, orig.Disambiguation, &newData.Disambiguation, &oldData.Disambiguation)
+
+	if e.Gender != nil {
+		diffNullString(stringPtr(e.Gender.String()), orig.Gender, &newData.Gender, &oldData.Gender)
+	}
+
+	if e.Birthdate != nil {
+		diffNullString(&e.Birthdate.Date, sql.NullString{String: orig.Birthdate.String, Valid: orig.Birthdate.Valid}, &newData.Birthdate, &oldData.Birthdate)
+		diffNullString(stringPtr(e.Birthdate.Accuracy.String()), orig.BirthdateAccuracy, &newData.BirthdateAccuracy, &oldData.BirthdateAccuracy)
+	}
+
+	if e.Ethnicity != nil {
+		diffNullString(stringPtr(e.Ethnicity.String()), orig.Ethnicity, &newData.Ethnicity, &oldData.Ethnicity)
+	}
+
+	diffNullString(e.Country, orig.Country, &newData.Country, &oldData.Country)
+
+	if e.EyeColor != nil {
+		diffNullString(stringPtr(e.EyeColor.String()), orig.EyeColor, &newData.EyeColor, &oldData.EyeColor)
+	}
+
+	if e.HairColor != nil {
+		diffNullString(stringPtr(e.HairColor.String()), orig.HairColor, &newData.HairColor, &oldData.HairColor)
+	}
+
+	diffNullInt64(e.Height, orig.Height, &newData.Height, &oldData.Height)
+
+	if e.Measurements != nil {
+		diffNullString(e.Measurements.CupSize, orig.CupSize, &newData.CupSize, &oldData.CupSize)
+		diffNullInt64(e.Measurements.BandSize, orig.BandSize, &newData.BandSize, &oldData.BandSize)
+		diffNullInt64(e.Measurements.Waist, orig.WaistSize, &newData.WaistSize, &oldData.WaistSize)
+		diffNullInt64(e.Measurements.Hip, orig.HipSize, &newData.HipSize, &oldData.HipSize)
+	}
+
+	if e.BreastType != nil {
+		diffNullString(stringPtr(e.BreastType.String()), orig.BreastType, &newData.BreastType, &oldData.BreastType)
+	}
+
+	diffNullInt64(e.CareerStartYear, orig.CareerStartYear, &newData.CareerStartYear, &oldData.CareerStartYear)
+	diffNullInt64(e.CareerEndYear, orig.CareerEndYear, &newData.CareerEndYear, &oldData.CareerEndYear)
+
+	return PerformerEditData{
+		New: newData,
+		Old: oldData,
+	}
+}
+
+func (e PerformerEditDetailsInput) PerformerEditFromMerge(orig Performer, sources []string) PerformerEditData {
+	data := e.PerformerEditFromDiff(orig)
+	data.MergeSources = sources
+
+	return data
+}
+
+func (e PerformerEditDetailsInput) PerformerEditFromCreate() PerformerEditData {
+	newData := &PerformerEdit{
+		Name:            e.Name,
+		Disambiguation:  e.Disambiguation,
+		Country:         e.Country,
+		Height:          e.Height,
+		CareerStartYear: e.CareerStartYear,
+		CareerEndYear:   e.CareerEndYear,
+	}
+
+	if e.Gender != nil {
+		newData.Gender = stringPtr(e.Gender.String())
+	}
+	if e.Birthdate != nil {
+		newData.Birthdate = stringPtr(e.Birthdate.Date)
+		newData.BirthdateAccuracy = stringPtr(e.Birthdate.Accuracy.String())
+	}
+	if e.Ethnicity != nil {
+		newData.Ethnicity = stringPtr(e.Ethnicity.String())
+	}
+	if e.EyeColor != nil {
+		newData.EyeColor = stringPtr(e.EyeColor.String())
+	}
+	if e.HairColor != nil {
+		newData.HairColor = stringPtr(e.HairColor.String())
+	}
+	if e.Measurements != nil {
+		newData.CupSize = e.Measurements.CupSize
+		newData.BandSize = e.Measurements.BandSize
+		newData.WaistSize = e.Measurements.Waist
+		newData.HipSize = e.Measurements.Hip
+	}
+	if e.BreastType != nil {
+		newData.BreastType = stringPtr(e.BreastType.String())
+	}
+
+	return PerformerEditData{
+		New: newData,
+	}
+}
+
+func (e SceneEditDetailsInput) SceneEditFromDiff(orig Scene) SceneEditData {
+	newData := &SceneEdit{}
+	oldData := &SceneEdit{}
+
+	diffNullString(e.Title, orig.Title, &newData.Title, &oldData.Title)
+	diffNullString(e.Details, orig.Details, &newData.Details, &oldData.Details)
+	diffNullString(e.Date, sql.NullString{String: orig.Date.String, Valid: orig.Date.Valid}, &newData.Date, &oldData.Date)
+	diffNullString(e.StudioID, sql.NullString{String: orig.StudioID.UUID.String(), Valid: orig.StudioID.Valid}, &newData.StudioID, &oldData.StudioID)
+	diffNullInt64(e.Duration, orig.Duration, &newData.Duration, &oldData.Duration)
+	diffNullString(e.Director, orig.Director, &newData.Director, &oldData.Director)
+
+	return SceneEditData{
+		New: newData,
+		Old: oldData,
+	}
+}
+
+func (e SceneEditDetailsInput) SceneEditFromMerge(orig Scene, sources []string) SceneEditData {
+	data := e.SceneEditFromDiff(orig)
+	data.MergeSources = sources
+
+	return data
+}
+
+func (e SceneEditDetailsInput) SceneEditFromCreate() SceneEditData {
+	newData := &SceneEdit{
+		Title:    e.Title,
+		Details:  e.Details,
+		Date:     e.Date,
+		StudioID: e.StudioID,
+		Duration: e.Duration,
+		Director: e.Director,
+	}
+
+	return SceneEditData{
+		New: newData,
+	}
+}
+
+func (e StudioEditDetailsInput) StudioEditFromDiff(orig Studio) StudioEditData {
+	newData := &StudioEdit{}
+	oldData := &StudioEdit{}
+
+	if e.Name != nil && *e.Name != orig.Name {
+		newName := *e.Name
+		newData.Name = &newName
+		oldData.Name = &orig.Name
+	}
+
+	diffNullString(e.ParentID, sql.NullString{String: orig.ParentStudioID.UUID.String(), Valid: orig.ParentStudioID.Valid}, &newData.ParentID, &oldData.ParentID)
+
+	return StudioEditData{
+		New: newData,
+		Old: oldData,
+	}
+}
+
+func (e StudioEditDetailsInput) StudioEditFromMerge(orig Studio, sources []string) StudioEditData {
+	data := e.StudioEditFromDiff(orig)
+	data.MergeSources = sources
+
+	return data
+}
+
+func (e StudioEditDetailsInput) StudioEditFromCreate() StudioEditData {
+	newData := &StudioEdit{
+		Name:     e.Name,
+		ParentID: e.ParentID,
+	}
+
+	return StudioEditData{
+		New: newData,
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+// diffNullString sets the new and old values if the input value is
+// specified and differs from the original value
+func diffNullString(input *string, orig sql.NullString, newValue **string, oldValue **string) {
+	if input == nil || (orig.Valid && *input == orig.String) {
+		return
+	}
+
+	newStr := *input
+	*newValue = &newStr
+	if orig.Valid {
+		oldStr := orig.String
+		*oldValue = &oldStr
+	}
+}
+
+// diffNullInt64 sets the new and old values if the input value is
+// specified and differs from the original value
+func diffNullInt64(input *int, orig sql.NullInt64, newValue **int, oldValue **int) {
+	if input == nil || (orig.Valid && int64(*input) == orig.Int64) {
+		return
+	}
+
+	newInt := *input
+	*newValue = &newInt
+	if orig.Valid {
+		oldInt := int(orig.Int64)
+		*oldValue = &oldInt
+	}
+}
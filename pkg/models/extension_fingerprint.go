@@ -0,0 +1,69 @@
+package models
+
+import "sort"
+
+// MatchesDuration returns true if a fingerprint with the duration matches the
+// duration of the query, within the tolerance of the query. Queries and
+// fingerprints without a duration match fingerprints of any duration.
+func (f FingerprintQueryInput) MatchesDuration(duration int) bool {
+	if f.Duration == nil || *f.Duration <= 0 || duration <= 0 {
+		return true
+	}
+
+	tolerance := 0
+	if f.DurationTolerance != nil {
+		tolerance = *f.DurationTolerance
+	}
+
+	difference := duration - *f.Duration
+	if difference < 0 {
+		difference = -difference
+	}
+
+	return difference <= tolerance
+}
+
+// FingerprintDurations returns the consensus duration of the fingerprints -
+// the median of their durations, weighted by their number of submissions -
+// and the difference between the longest and shortest durations. Durations
+// of zero are unknown and ignored. Returns false if no duration is known.
+func FingerprintDurations(fingerprints []*Fingerprint) (consensus int, spread int, ok bool) {
+	var known []*Fingerprint
+	totalWeight := 0
+	for _, fingerprint := range fingerprints {
+		if fingerprint.Duration > 0 {
+			known = append(known, fingerprint)
+			totalWeight += fingerprintWeight(fingerprint)
+		}
+	}
+
+	if len(known) == 0 {
+		return 0, 0, false
+	}
+
+	sort.Slice(known, func(i, j int) bool {
+		return known[i].Duration < known[j].Duration
+	})
+
+	spread = known[len(known)-1].Duration - known[0].Duration
+
+	weight := 0
+	for _, fingerprint := range known {
+		weight += fingerprintWeight(fingerprint)
+		if weight*2 >= totalWeight {
+			consensus = fingerprint.Duration
+			break
+		}
+	}
+
+	return consensus, spread, true
+}
+
+// fingerprintWeight returns the weight of the fingerprint in the consensus
+// duration. Fingerprints added without being submitted count once.
+func fingerprintWeight(fingerprint *Fingerprint) int {
+	if fingerprint.Submissions > 1 {
+		return fingerprint.Submissions
+	}
+	return 1
+}
@@ -0,0 +1,63 @@
+package models
+
+import "testing"
+
+func TestMatchesDuration(t *testing.T) {
+	duration := 100
+	tolerance := 5
+	query := FingerprintQueryInput{Duration: &duration, DurationTolerance: &tolerance}
+
+	tests := []struct {
+		duration int
+		expected bool
+	}{
+		{100, true},
+		{95, true},
+		{105, true},
+		{94, false},
+		{106, false},
+		// unknown durations match
+		{0, true},
+	}
+
+	for _, test := range tests {
+		if got := query.MatchesDuration(test.duration); got != test.expected {
+			t.Errorf("MatchesDuration(%d): got %v want %v", test.duration, got, test.expected)
+		}
+	}
+
+	if !(FingerprintQueryInput{}).MatchesDuration(100) {
+		t.Error("query without duration should match any duration")
+	}
+
+	query.DurationTolerance = nil
+	if query.MatchesDuration(101) {
+		t.Error("query without tolerance should match the exact duration only")
+	}
+}
+
+func TestFingerprintDurations(t *testing.T) {
+	fingerprints := []*Fingerprint{
+		{Duration: 300, Submissions: 1},
+		{Duration: 0, Submissions: 10},
+		{Duration: 100, Submissions: 5},
+		{Duration: 102, Submissions: 0},
+	}
+
+	consensus, spread, ok := FingerprintDurations(fingerprints)
+	if !ok {
+		t.Fatal("expected durations to be known")
+	}
+
+	// weights of 5, 1 and 1 for 100, 102 and 300
+	if consensus != 100 {
+		t.Errorf("consensus: got %d want 100", consensus)
+	}
+	if spread != 200 {
+		t.Errorf("spread: got %d want 200", spread)
+	}
+
+	if _, _, ok := FingerprintDurations([]*Fingerprint{{Duration: 0}}); ok {
+		t.Error("expected unknown durations")
+	}
+}
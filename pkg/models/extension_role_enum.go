@@ -11,6 +11,11 @@ func (r RoleEnum) Implies(other RoleEnum) bool {
 		return true
 	}
 
+	// TRUSTED_EDIT implies EDIT
+	if r == RoleEnumTrustedEdit && other == RoleEnumEdit {
+		return true
+	}
+
 	// until we add a NONE value, all values imply read
 	if r.IsValid() && other == RoleEnumRead {
 		return true
@@ -2,7 +2,12 @@ package models
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -12,10 +17,9 @@ import (
 )
 
 const (
-	editTable   = "edits"
-	editJoinKey = "edit_id"
-
-	//voteTable = "votes"
+	editTable      = "edits"
+	editJoinKey    = "edit_id"
+	editGroupTable = "edit_groups"
 )
 
 var (
@@ -27,13 +31,33 @@ var (
 		return &EditTag{}
 	})
 
+	editPerformerTable = database.NewTableJoin(editTable, "performer_edits", editJoinKey, func() interface{} {
+		return &EditPerformer{}
+	})
+
+	editSceneTable = database.NewTableJoin(editTable, "scene_edits", editJoinKey, func() interface{} {
+		return &EditScene{}
+	})
+
+	editStudioTable = database.NewTableJoin(editTable, "studio_edits", editJoinKey, func() interface{} {
+		return &EditStudio{}
+	})
+
 	editCommentTable = database.NewTableJoin(editTable, "edit_comments", editJoinKey, func() interface{} {
 		return &EditComment{}
 	})
 
-	// voteDBTable = database.NewTable(editTable, func() interface{} {
-	// 	return &Edit{}
-	// })
+	editVoteTable = database.NewTableJoin(editTable, "edit_votes", editJoinKey, func() interface{} {
+		return &EditVote{}
+	})
+
+	editRevisionTable = database.NewTableJoin(editTable, "edit_revisions", editJoinKey, func() interface{} {
+		return &EditRevision{}
+	})
+
+	editGroupDBTable = database.NewTable(editGroupTable, func() interface{} {
+		return &EditGroup{}
+	})
 )
 
 type Edit struct {
@@ -47,14 +71,28 @@ type Edit struct {
 	Data       types.JSONText  `db:"data" json:"data"`
 	CreatedAt  SQLiteTimestamp `db:"created_at" json:"created_at"`
 	UpdatedAt  SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+	// TargetUpdatedAt is the last update time of the target when the edit
+	// was proposed
+	TargetUpdatedAt NullSQLiteTimestamp `db:"target_updated_at" json:"target_updated_at"`
+	// RevertsID is the id of the edit reverted by this edit
+	RevertsID uuid.NullUUID `db:"reverts_id" json:"reverts_id"`
+	// Snapshot is the state of the target and merge sources before the edit
+	// was applied, used to revert the edit
+	Snapshot types.JSONText `db:"snapshot" json:"snapshot"`
+	// GroupID is the id of the group of edits voted on and applied together
+	// with this edit
+	GroupID uuid.NullUUID `db:"group_id" json:"group_id"`
 }
 
 type EditComment struct {
-	ID        uuid.UUID       `db:"id" json:"id"`
-	EditID    uuid.UUID       `db:"edit_id" json:"edit_id"`
-	UserID    uuid.UUID       `db:"user_id" json:"user_id"`
-	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
-	Text      string          `db:"text" json:"text"`
+	ID        uuid.UUID           `db:"id" json:"id"`
+	EditID    uuid.UUID           `db:"edit_id" json:"edit_id"`
+	UserID    uuid.UUID           `db:"user_id" json:"user_id"`
+	CreatedAt SQLiteTimestamp     `db:"created_at" json:"created_at"`
+	Text      string              `db:"text" json:"text"`
+	ParentID  uuid.NullUUID       `db:"parent_id" json:"parent_id"`
+	EditedAt  NullSQLiteTimestamp `db:"edited_at" json:"edited_at"`
+	Hidden    bool                `db:"hidden" json:"hidden"`
 }
 
 func NewEdit(UUID uuid.UUID, user *User, targetType TargetTypeEnum, input *EditInput) *Edit {
@@ -73,6 +111,83 @@ func NewEdit(UUID uuid.UUID, user *User, targetType TargetTypeEnum, input *EditI
 	return ret
 }
 
+// NewRevertEdit returns a new applied edit recording the revert of the
+// original edit. The operation of the revert edit is the inverse of the
+// original operation.
+func NewRevertEdit(UUID uuid.UUID, user *User, original Edit) *Edit {
+	currentTime := time.Now()
+
+	ret := &Edit{
+		ID:         UUID,
+		UserID:     user.ID,
+		TargetType: original.TargetType,
+		Status:     VoteStatusEnumImmediateAccepted.String(),
+		Operation:  inverseOperation(original.Operation),
+		Applied:    true,
+		CreatedAt:  SQLiteTimestamp{Timestamp: currentTime},
+		UpdatedAt:  SQLiteTimestamp{Timestamp: currentTime},
+		RevertsID:  uuid.NullUUID{UUID: original.ID, Valid: true},
+	}
+
+	return ret
+}
+
+// inverseOperation returns the operation undoing the provided operation. A
+// created object is destroyed, and a destroyed object is created again by
+// restoring it. Modifications and merges are undone by modifying the target
+// back to its previous state, restoring any merge sources along the way.
+func inverseOperation(operation string) string {
+	switch operation {
+	case OperationEnumCreate.String():
+		return OperationEnumDestroy.String()
+	case OperationEnumDestroy.String():
+		return OperationEnumCreate.String()
+	}
+
+	return OperationEnumModify.String()
+}
+
+// EditGroup links edits that are voted on and applied as one unit.
+type EditGroup struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	UserID    uuid.UUID       `db:"user_id" json:"user_id"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+func NewEditGroup(UUID uuid.UUID, user *User) *EditGroup {
+	return &EditGroup{
+		ID:        UUID,
+		UserID:    user.ID,
+		CreatedAt: SQLiteTimestamp{Timestamp: time.Now()},
+	}
+}
+
+func (EditGroup) GetTable() database.Table {
+	return editGroupDBTable
+}
+
+func (p EditGroup) GetID() uuid.UUID {
+	return p.ID
+}
+
+type EditVote struct {
+	EditID    uuid.UUID       `db:"edit_id" json:"edit_id"`
+	UserID    uuid.UUID       `db:"user_id" json:"user_id"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+	Vote      string          `db:"vote" json:"vote"`
+	Comment   sql.NullString  `db:"comment" json:"comment"`
+	Outdated  bool            `db:"outdated" json:"outdated"`
+}
+
+// EditRevision holds a previous version of the data of an edit, kept when
+// the edit is amended by its author.
+type EditRevision struct {
+	ID        uuid.UUID       `db:"id" json:"id"`
+	EditID    uuid.UUID       `db:"edit_id" json:"edit_id"`
+	Data      types.JSONText  `db:"data" json:"data"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
 func NewEditComment(UUID uuid.UUID, user *User, edit *Edit, text string) *EditComment {
 	currentTime := time.Now()
 
@@ -87,6 +202,36 @@ func NewEditComment(UUID uuid.UUID, user *User, edit *Edit, text string) *EditCo
 	return ret
 }
 
+func NewEditVote(user *User, edit *Edit, vote VoteTypeEnum, comment *string) *EditVote {
+	currentTime := time.Now()
+
+	ret := &EditVote{
+		EditID:    edit.ID,
+		UserID:    user.ID,
+		CreatedAt: SQLiteTimestamp{Timestamp: currentTime},
+		Vote:      vote.String(),
+	}
+
+	if comment != nil {
+		ret.Comment = sql.NullString{String: *comment, Valid: true}
+	}
+
+	return ret
+}
+
+func NewEditRevision(UUID uuid.UUID, edit *Edit) *EditRevision {
+	currentTime := time.Now()
+
+	ret := &EditRevision{
+		ID:        UUID,
+		EditID:    edit.ID,
+		Data:      edit.Data,
+		CreatedAt: SQLiteTimestamp{Timestamp: currentTime},
+	}
+
+	return ret
+}
+
 func (Edit) GetTable() database.Table {
 	return editDBTable
 }
@@ -106,6 +251,32 @@ func (p *Edit) ImmediateReject() {
 	p.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
 }
 
+func (p *Edit) Accept() {
+	p.Status = VoteStatusEnumAccepted.String()
+	p.Applied = true
+	p.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
+}
+
+func (p *Edit) Reject() {
+	p.Status = VoteStatusEnumRejected.String()
+	p.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
+}
+
+// Amend replaces the data of the edit with that of the amended edit and
+// resets the vote count.
+func (p *Edit) Amend(amended Edit) {
+	p.Data = amended.Data
+	p.TargetUpdatedAt = amended.TargetUpdatedAt
+	p.VoteCount = 0
+	p.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
+}
+
+// SetTargetUpdatedAt records the last update time of the target, so that
+// later changes to the target can be detected.
+func (p *Edit) SetTargetUpdatedAt(updatedAt SQLiteTimestamp) {
+	p.TargetUpdatedAt = NullSQLiteTimestamp{Timestamp: updatedAt.Timestamp, Valid: true}
+}
+
 func (e *Edit) SetData(data interface{}) error {
 	buffer := &bytes.Buffer{}
 	encoder := json.NewEncoder(buffer)
@@ -118,6 +289,24 @@ func (e *Edit) SetData(data interface{}) error {
 	return nil
 }
 
+func (e *Edit) SetSnapshot(snapshot interface{}) error {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(snapshot); err != nil {
+		return err
+	}
+	e.Snapshot = buffer.Bytes()
+	return nil
+}
+
+func (e *Edit) GetSnapshot(snapshot interface{}) error {
+	if len(e.Snapshot) == 0 {
+		return errors.New("Edit has no snapshot")
+	}
+	return json.Unmarshal(e.Snapshot, snapshot)
+}
+
 func (e *Edit) GetData() *EditData {
 	data := EditData{}
 	err := json.Unmarshal(e.Data, &data)
@@ -133,6 +322,24 @@ func (e *Edit) GetTagData() (*TagEditData, error) {
 	return &data, nil
 }
 
+func (e *Edit) GetPerformerData() (*PerformerEditData, error) {
+	data := PerformerEditData{}
+	_ = json.Unmarshal(e.Data, &data)
+	return &data, nil
+}
+
+func (e *Edit) GetSceneData() (*SceneEditData, error) {
+	data := SceneEditData{}
+	_ = json.Unmarshal(e.Data, &data)
+	return &data, nil
+}
+
+func (e *Edit) GetStudioData() (*StudioEditData, error) {
+	data := StudioEditData{}
+	_ = json.Unmarshal(e.Data, &data)
+	return &data, nil
+}
+
 type Edits []*Edit
 
 func (p Edits) Each(fn func(interface{})) {
@@ -162,14 +369,56 @@ func (p *EditTags) Add(o interface{}) {
 	*p = append(*p, o.(*EditTag))
 }
 
-// type VoteComment struct {
-// 	ID      uuid.UUID      `db:"id" json:"id"`
-// 	EditID  uuid.UUID      `db:"edit_id" json:"edit_id"`
-// 	UserID  uuid.UUID      `db:"user_id" json:"user_id"`
-// 	Date    SQLiteDate     `db:"date" json:"date"`
-// 	Comment sql.NullString `db:"comment" json:"comment"`
-// 	Type    string         `db:"type" json:"type"`
-// }
+type EditPerformer struct {
+	EditID      uuid.UUID `db:"edit_id" json:"edit_id"`
+	PerformerID uuid.UUID `db:"performer_id" json:"performer_id"`
+}
+
+type EditPerformers []*EditPerformer
+
+func (p EditPerformers) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *EditPerformers) Add(o interface{}) {
+	*p = append(*p, o.(*EditPerformer))
+}
+
+type EditScene struct {
+	EditID  uuid.UUID `db:"edit_id" json:"edit_id"`
+	SceneID uuid.UUID `db:"scene_id" json:"scene_id"`
+}
+
+type EditScenes []*EditScene
+
+func (p EditScenes) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *EditScenes) Add(o interface{}) {
+	*p = append(*p, o.(*EditScene))
+}
+
+type EditStudio struct {
+	EditID   uuid.UUID `db:"edit_id" json:"edit_id"`
+	StudioID uuid.UUID `db:"studio_id" json:"studio_id"`
+}
+
+type EditStudios []*EditStudio
+
+func (p EditStudios) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *EditStudios) Add(o interface{}) {
+	*p = append(*p, o.(*EditStudio))
+}
 
 // func (p *Scene) CopyFromCreateInput(input SceneCreateInput) {
 // 	CopyFull(p, input)
@@ -192,6 +441,7 @@ type TagEdit struct {
 	Description    *string  `json:"description,omitempty"`
 	AddedAliases   []string `json:"added_aliases,omitempty"`
 	RemovedAliases []string `json:"removed_aliases,omitempty"`
+	ClearedFields  []string `json:"cleared_fields,omitempty"`
 }
 
 func (TagEdit) IsEditDetails() {}
@@ -202,6 +452,267 @@ type TagEditData struct {
 	MergeSources []string `json:"merge_sources,omitempty"`
 }
 
+// Inverse returns the edit data that undoes the changes of this edit data.
+func (d TagEditData) Inverse() TagEditData {
+	newData := TagEdit{}
+	if d.Old != nil {
+		newData = *d.Old
+	}
+	ret := TagEditData{
+		New:          &newData,
+		MergeSources: d.MergeSources,
+	}
+
+	if d.New != nil {
+		newData.ClearedFields = clearedEditFields(d.New, d.Old)
+		newData.AddedAliases = d.New.RemovedAliases
+		newData.RemovedAliases = d.New.AddedAliases
+
+		oldData := *d.New
+		oldData.ClearedFields = nil
+		oldData.AddedAliases = nil
+		oldData.RemovedAliases = nil
+		ret.Old = &oldData
+	}
+
+	return ret
+}
+
+type PerformerEdit struct {
+	Name              *string             `json:"name,omitempty"`
+	Disambiguation    *string             `json:"disambiguation,omitempty"`
+	AddedAliases      []string            `json:"added_aliases,omitempty"`
+	RemovedAliases    []string            `json:"removed_aliases,omitempty"`
+	Gender            *string             `json:"gender,omitempty"`
+	AddedUrls         []*URL              `json:"added_urls,omitempty"`
+	RemovedUrls       []*URL              `json:"removed_urls,omitempty"`
+	Birthdate         *string             `json:"birthdate,omitempty"`
+	BirthdateAccuracy *string             `json:"birthdate_accuracy,omitempty"`
+	Ethnicity         *string             `json:"ethnicity,omitempty"`
+	Country           *string             `json:"country,omitempty"`
+	EyeColor          *string             `json:"eye_color,omitempty"`
+	HairColor         *string             `json:"hair_color,omitempty"`
+	Height            *int                `json:"height,omitempty"`
+	CupSize           *string             `json:"cup_size,omitempty"`
+	BandSize          *int                `json:"band_size,omitempty"`
+	WaistSize         *int                `json:"waist_size,omitempty"`
+	HipSize           *int                `json:"hip_size,omitempty"`
+	BreastType        *string             `json:"breast_type,omitempty"`
+	CareerStartYear   *int                `json:"career_start_year,omitempty"`
+	CareerEndYear     *int                `json:"career_end_year,omitempty"`
+	AddedTattoos      []*BodyModification `json:"added_tattoos,omitempty"`
+	RemovedTattoos    []*BodyModification `json:"removed_tattoos,omitempty"`
+	AddedPiercings    []*BodyModification `json:"added_piercings,omitempty"`
+	RemovedPiercings  []*BodyModification `json:"removed_piercings,omitempty"`
+	AddedImages       []string            `json:"added_images,omitempty"`
+	RemovedImages     []string            `json:"removed_images,omitempty"`
+	ClearedFields     []string            `json:"cleared_fields,omitempty"`
+}
+
+func (PerformerEdit) IsEditDetails() {}
+
+type PerformerEditData struct {
+	New          *PerformerEdit `json:"new_data,omitempty"`
+	Old          *PerformerEdit `json:"old_data,omitempty"`
+	MergeSources []string       `json:"merge_sources,omitempty"`
+}
+
+// Inverse returns the edit data that undoes the changes of this edit data.
+func (d PerformerEditData) Inverse() PerformerEditData {
+	newData := PerformerEdit{}
+	if d.Old != nil {
+		newData = *d.Old
+	}
+	ret := PerformerEditData{
+		New:          &newData,
+		MergeSources: d.MergeSources,
+	}
+
+	if d.New != nil {
+		newData.ClearedFields = clearedEditFields(d.New, d.Old)
+		newData.AddedAliases = d.New.RemovedAliases
+		newData.RemovedAliases = d.New.AddedAliases
+		newData.AddedUrls = d.New.RemovedUrls
+		newData.RemovedUrls = d.New.AddedUrls
+		newData.AddedTattoos = d.New.RemovedTattoos
+		newData.RemovedTattoos = d.New.AddedTattoos
+		newData.AddedPiercings = d.New.RemovedPiercings
+		newData.RemovedPiercings = d.New.AddedPiercings
+		newData.AddedImages = d.New.RemovedImages
+		newData.RemovedImages = d.New.AddedImages
+
+		oldData := *d.New
+		oldData.ClearedFields = nil
+		oldData.AddedAliases = nil
+		oldData.RemovedAliases = nil
+		oldData.AddedUrls = nil
+		oldData.RemovedUrls = nil
+		oldData.AddedTattoos = nil
+		oldData.RemovedTattoos = nil
+		oldData.AddedPiercings = nil
+		oldData.RemovedPiercings = nil
+		oldData.AddedImages = nil
+		oldData.RemovedImages = nil
+		ret.Old = &oldData
+	}
+
+	return ret
+}
+
+type SceneEdit struct {
+	Title               *string                     `json:"title,omitempty"`
+	Details             *string                     `json:"details,omitempty"`
+	AddedUrls           []*URL                      `json:"added_urls,omitempty"`
+	RemovedUrls         []*URL                      `json:"removed_urls,omitempty"`
+	Date                *string                     `json:"date,omitempty"`
+	StudioID            *string                     `json:"studio_id,omitempty"`
+	AddedPerformers     []*PerformerAppearanceInput `json:"added_performers,omitempty"`
+	RemovedPerformers   []*PerformerAppearanceInput `json:"removed_performers,omitempty"`
+	AddedTags           []string                    `json:"added_tags,omitempty"`
+	RemovedTags         []string                    `json:"removed_tags,omitempty"`
+	AddedImages         []string                    `json:"added_images,omitempty"`
+	RemovedImages       []string                    `json:"removed_images,omitempty"`
+	AddedFingerprints   []*Fingerprint              `json:"added_fingerprints,omitempty"`
+	RemovedFingerprints []*Fingerprint              `json:"removed_fingerprints,omitempty"`
+	Duration            *int                        `json:"duration,omitempty"`
+	Director            *string                     `json:"director,omitempty"`
+	ClearedFields       []string                    `json:"cleared_fields,omitempty"`
+}
+
+func (SceneEdit) IsEditDetails() {}
+
+type SceneEditData struct {
+	New          *SceneEdit `json:"new_data,omitempty"`
+	Old          *SceneEdit `json:"old_data,omitempty"`
+	MergeSources []string   `json:"merge_sources,omitempty"`
+}
+
+// Inverse returns the edit data that undoes the changes of this edit data.
+func (d SceneEditData) Inverse() SceneEditData {
+	newData := SceneEdit{}
+	if d.Old != nil {
+		newData = *d.Old
+	}
+	ret := SceneEditData{
+		New:          &newData,
+		MergeSources: d.MergeSources,
+	}
+
+	if d.New != nil {
+		newData.ClearedFields = clearedEditFields(d.New, d.Old)
+		newData.AddedUrls = d.New.RemovedUrls
+		newData.RemovedUrls = d.New.AddedUrls
+		newData.AddedPerformers = d.New.RemovedPerformers
+		newData.RemovedPerformers = d.New.AddedPerformers
+		newData.AddedTags = d.New.RemovedTags
+		newData.RemovedTags = d.New.AddedTags
+		newData.AddedImages = d.New.RemovedImages
+		newData.RemovedImages = d.New.AddedImages
+		newData.AddedFingerprints = d.New.RemovedFingerprints
+		newData.RemovedFingerprints = d.New.AddedFingerprints
+
+		oldData := *d.New
+		oldData.ClearedFields = nil
+		oldData.AddedUrls = nil
+		oldData.RemovedUrls = nil
+		oldData.AddedPerformers = nil
+		oldData.RemovedPerformers = nil
+		oldData.AddedTags = nil
+		oldData.RemovedTags = nil
+		oldData.AddedImages = nil
+		oldData.RemovedImages = nil
+		oldData.AddedFingerprints = nil
+		oldData.RemovedFingerprints = nil
+		ret.Old = &oldData
+	}
+
+	return ret
+}
+
+type StudioEdit struct {
+	Name                *string  `json:"name,omitempty"`
+	AddedUrls           []*URL   `json:"added_urls,omitempty"`
+	RemovedUrls         []*URL   `json:"removed_urls,omitempty"`
+	ParentID            *string  `json:"parent_id,omitempty"`
+	RemoveParent        bool     `json:"remove_parent,omitempty"`
+	AddedChildStudios   []string `json:"added_child_studios,omitempty"`
+	RemovedChildStudios []string `json:"removed_child_studios,omitempty"`
+	AddedImages         []string `json:"added_images,omitempty"`
+	RemovedImages       []string `json:"removed_images,omitempty"`
+	ClearedFields       []string `json:"cleared_fields,omitempty"`
+}
+
+func (StudioEdit) IsEditDetails() {}
+
+type StudioEditData struct {
+	New          *StudioEdit `json:"new_data,omitempty"`
+	Old          *StudioEdit `json:"old_data,omitempty"`
+	MergeSources []string    `json:"merge_sources,omitempty"`
+}
+
+// Inverse returns the edit data that undoes the changes of this edit data.
+func (d StudioEditData) Inverse() StudioEditData {
+	newData := StudioEdit{}
+	if d.Old != nil {
+		newData = *d.Old
+	}
+	ret := StudioEditData{
+		New:          &newData,
+		MergeSources: d.MergeSources,
+	}
+
+	if d.New != nil {
+		newData.ClearedFields = clearedEditFields(d.New, d.Old)
+		newData.AddedUrls = d.New.RemovedUrls
+		newData.RemovedUrls = d.New.AddedUrls
+		newData.AddedChildStudios = d.New.RemovedChildStudios
+		newData.RemovedChildStudios = d.New.AddedChildStudios
+		newData.AddedImages = d.New.RemovedImages
+		newData.RemovedImages = d.New.AddedImages
+		// a parent set where there was none is removed again
+		newData.RemoveParent = d.New.ParentID != nil && newData.ParentID == nil
+
+		oldData := *d.New
+		oldData.ClearedFields = nil
+		oldData.RemoveParent = false
+		oldData.AddedUrls = nil
+		oldData.RemovedUrls = nil
+		oldData.AddedChildStudios = nil
+		oldData.RemovedChildStudios = nil
+		oldData.AddedImages = nil
+		oldData.RemovedImages = nil
+		ret.Old = &oldData
+	}
+
+	return ret
+}
+
+// clearedEditFields returns the json names of the fields set by the new edit
+// details that were null before, which are cleared again when the edit is
+// undone. The edit details must be pointers to structs, where fields set by
+// the edit are non-nil pointers.
+func clearedEditFields(newEdit interface{}, oldEdit interface{}) []string {
+	newValue := reflect.ValueOf(newEdit).Elem()
+	oldValue := reflect.ValueOf(oldEdit)
+
+	var ret []string
+	for i := 0; i < newValue.NumField(); i++ {
+		field := newValue.Field(i)
+		if field.Kind() != reflect.Ptr || field.IsNil() {
+			continue
+		}
+
+		if !oldValue.IsNil() && !oldValue.Elem().Field(i).IsNil() {
+			continue
+		}
+
+		name := strings.Split(newValue.Type().Field(i).Tag.Get("json"), ",")[0]
+		ret = append(ret, name)
+	}
+
+	return ret
+}
+
 type EditData struct {
 	New          *json.RawMessage `json:"new_data,omitempty"`
 	Old          *json.RawMessage `json:"old_data,omitempty"`
@@ -219,3 +730,131 @@ func (p EditComments) Each(fn func(interface{})) {
 func (p *EditComments) Add(o interface{}) {
 	*p = append(*p, o.(*EditComment))
 }
+
+type EditVotes []*EditVote
+
+func (p EditVotes) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *EditVotes) Add(o interface{}) {
+	*p = append(*p, o.(*EditVote))
+}
+
+type EditRevisions []*EditRevision
+
+func (p EditRevisions) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *EditRevisions) Add(o interface{}) {
+	*p = append(*p, o.(*EditRevision))
+}
+
+// EditConflict is a field of the edit target whose current value differs
+// from the value expected by the edit.
+type EditConflict struct {
+	Field    string  `json:"field"`
+	Expected *string `json:"expected"`
+	Current  *string `json:"current"`
+}
+
+func (c EditConflict) Error() string {
+	expected := ""
+	if c.Expected != nil {
+		expected = *c.Expected
+	}
+	current := ""
+	if c.Current != nil {
+		current = *c.Current
+	}
+	return "Invalid " + c.Field + ". Expected '" + expected + "'  but was '" + current + "'"
+}
+
+// editConflictField is a field modified by an edit, along with the value
+// expected by the edit and the current value of the target.
+type editConflictField struct {
+	name     string
+	modified bool
+	expected *string
+	current  *string
+}
+
+func findEditConflicts(fields []editConflictField) []*EditConflict {
+	var ret []*EditConflict
+	for _, f := range fields {
+		if !f.modified {
+			continue
+		}
+
+		expected := ""
+		if f.expected != nil {
+			expected = *f.expected
+		}
+		current := ""
+		if f.current != nil {
+			current = *f.current
+		}
+
+		if expected != current {
+			ret = append(ret, &EditConflict{
+				Field:    f.name,
+				Expected: f.expected,
+				Current:  f.current,
+			})
+		}
+	}
+
+	return ret
+}
+
+// validateEditConflicts returns the first conflict as an error, or nil if
+// there are no conflicts.
+func validateEditConflicts(conflicts []*EditConflict) error {
+	if len(conflicts) > 0 {
+		return *conflicts[0]
+	}
+
+	return nil
+}
+
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func nullInt64StringPtr(i sql.NullInt64) *string {
+	if !i.Valid {
+		return nil
+	}
+	ret := strconv.FormatInt(i.Int64, 10)
+	return &ret
+}
+
+func toNullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func toNullInt64(i *int) sql.NullInt64 {
+	if i == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*i), Valid: true}
+}
+
+func intStringPtr(i *int) *string {
+	if i == nil {
+		return nil
+	}
+	ret := strconv.Itoa(*i)
+	return &ret
+}
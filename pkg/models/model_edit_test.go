@@ -0,0 +1,84 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestNewRevertEdit(t *testing.T) {
+	user := &User{ID: uuid.Must(uuid.NewV4())}
+
+	tests := []struct {
+		operation OperationEnum
+		want      OperationEnum
+	}{
+		{OperationEnumCreate, OperationEnumDestroy},
+		{OperationEnumDestroy, OperationEnumCreate},
+		{OperationEnumModify, OperationEnumModify},
+		{OperationEnumMerge, OperationEnumModify},
+	}
+
+	for _, tt := range tests {
+		original := Edit{
+			ID:        uuid.Must(uuid.NewV4()),
+			Operation: tt.operation.String(),
+		}
+
+		revert := NewRevertEdit(uuid.Must(uuid.NewV4()), user, original)
+		if revert.Operation != tt.want.String() {
+			t.Errorf("NewRevertEdit(%s): got operation %s want %s", tt.operation, revert.Operation, tt.want)
+		}
+		if !revert.RevertsID.Valid || revert.RevertsID.UUID != original.ID {
+			t.Errorf("NewRevertEdit(%s): does not reference the original edit", tt.operation)
+		}
+	}
+}
+
+func TestPerformerEditDataInverse(t *testing.T) {
+	oldName := "old name"
+	newName := "new name"
+	country := "US"
+	data := PerformerEditData{
+		New: &PerformerEdit{Name: &newName, Country: &country},
+		Old: &PerformerEdit{Name: &oldName},
+	}
+
+	inverse := data.Inverse()
+	if inverse.New.Name == nil || *inverse.New.Name != oldName {
+		t.Errorf("Inverse: got name %v want %s", inverse.New.Name, oldName)
+	}
+	if inverse.New.Country != nil {
+		t.Errorf("Inverse: got country %s want nil", *inverse.New.Country)
+	}
+	if !reflect.DeepEqual(inverse.New.ClearedFields, []string{"country"}) {
+		t.Errorf("Inverse: got cleared fields %v want [country]", inverse.New.ClearedFields)
+	}
+	if inverse.Old.Country == nil || *inverse.Old.Country != country {
+		t.Errorf("Inverse: got old country %v want %s", inverse.Old.Country, country)
+	}
+	if inverse.Old.ClearedFields != nil {
+		t.Errorf("Inverse: got old cleared fields %v want nil", inverse.Old.ClearedFields)
+	}
+}
+
+func TestUndoPerformerEditInverse(t *testing.T) {
+	country := "US"
+	data := PerformerEditData{
+		New: &PerformerEdit{Country: &country},
+		Old: &PerformerEdit{},
+	}
+
+	// undoing the revert of the edit restores the value set by the edit
+	performer := Performer{}
+	performer.UndoPerformerEdit(data.Inverse())
+	if !performer.Country.Valid || performer.Country.String != country {
+		t.Errorf("UndoPerformerEdit: got country %v want %s", performer.Country, country)
+	}
+
+	performer.UndoPerformerEdit(data)
+	if performer.Country.Valid {
+		t.Errorf("UndoPerformerEdit: got country %s want null", performer.Country.String)
+	}
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"database/sql"
+	"errors"
+
 	"github.com/gofrs/uuid"
 
 	"github.com/stashapp/stashdb/pkg/database"
@@ -59,6 +61,56 @@ func (p *PerformersScenes) Add(o interface{}) {
 	*p = append(*p, o.(*PerformerScene))
 }
 
+func (p PerformersScenes) SceneIDs() []uuid.UUID {
+	var ret []uuid.UUID
+	for _, v := range p {
+		ret = append(ret, v.SceneID)
+	}
+	return ret
+}
+
+func (p *PerformersScenes) Remove(performerID uuid.UUID) {
+	for i, v := range *p {
+		if v.PerformerID == performerID {
+			*p = append((*p)[:i], (*p)[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *PerformersScenes) AddPerformers(newPerformers []*PerformerScene) error {
+	performerMap := map[uuid.UUID]bool{}
+	for _, x := range *p {
+		performerMap[x.PerformerID] = true
+	}
+	for _, v := range newPerformers {
+		if performerMap[v.PerformerID] {
+			return errors.New("Invalid performer addition. Performer already exists '" + v.PerformerID.String() + "'")
+		}
+	}
+	for _, v := range newPerformers {
+		p.Add(v)
+	}
+	return nil
+}
+
+func (p *PerformersScenes) RemovePerformers(oldPerformers []*PerformerAppearanceInput) error {
+	performerMap := map[uuid.UUID]sql.NullString{}
+	for _, x := range *p {
+		performerMap[x.PerformerID] = x.As
+	}
+	for _, v := range oldPerformers {
+		as, found := performerMap[uuid.FromStringOrNil(v.PerformerID)]
+		if !found || (v.As == nil && as.Valid) || (v.As != nil && (!as.Valid || as.String != *v.As)) {
+			return errors.New("Invalid performer removal. Performer appearance does not exist: '" + v.PerformerID + "'")
+		}
+	}
+	for _, v := range oldPerformers {
+		p.Remove(uuid.FromStringOrNil(v.PerformerID))
+	}
+	return nil
+}
+
 type SceneTag struct {
 	SceneID uuid.UUID `db:"scene_id" json:"scene_id"`
 	TagID   uuid.UUID `db:"tag_id" json:"tag_id"`
@@ -76,6 +128,55 @@ func (p *ScenesTags) Add(o interface{}) {
 	*p = append(*p, o.(*SceneTag))
 }
 
+func (p ScenesTags) SceneIDs() []uuid.UUID {
+	var ret []uuid.UUID
+	for _, v := range p {
+		ret = append(ret, v.SceneID)
+	}
+	return ret
+}
+
+func (p *ScenesTags) Remove(tagID uuid.UUID) {
+	for i, v := range *p {
+		if v.TagID == tagID {
+			*p = append((*p)[:i], (*p)[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *ScenesTags) AddTags(newTags []*SceneTag) error {
+	tagMap := map[uuid.UUID]bool{}
+	for _, x := range *p {
+		tagMap[x.TagID] = true
+	}
+	for _, v := range newTags {
+		if tagMap[v.TagID] {
+			return errors.New("Invalid tag addition. Tag already exists '" + v.TagID.String() + "'")
+		}
+	}
+	for _, v := range newTags {
+		p.Add(v)
+	}
+	return nil
+}
+
+func (p *ScenesTags) RemoveTags(oldTags []string) error {
+	tagMap := map[uuid.UUID]bool{}
+	for _, x := range *p {
+		tagMap[x.TagID] = true
+	}
+	for _, v := range oldTags {
+		if !tagMap[uuid.FromStringOrNil(v)] {
+			return errors.New("Invalid tag removal. Tag does not exist: '" + v + "'")
+		}
+	}
+	for _, v := range oldTags {
+		p.Remove(uuid.FromStringOrNil(v))
+	}
+	return nil
+}
+
 type SceneImage struct {
 	SceneID uuid.UUID `db:"scene_id" json:"scene_id"`
 	ImageID uuid.UUID `db:"image_id" json:"image_id"`
@@ -93,6 +194,47 @@ func (p *SceneImages) Add(o interface{}) {
 	*p = append(*p, o.(*SceneImage))
 }
 
+func (p *SceneImages) Remove(imageID uuid.UUID) {
+	for i, v := range *p {
+		if v.ImageID == imageID {
+			*p = append((*p)[:i], (*p)[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *SceneImages) AddImages(newImages []*SceneImage) error {
+	imageMap := map[uuid.UUID]bool{}
+	for _, x := range *p {
+		imageMap[x.ImageID] = true
+	}
+	for _, v := range newImages {
+		if imageMap[v.ImageID] {
+			return errors.New("Invalid image addition. Image already exists '" + v.ImageID.String() + "'")
+		}
+	}
+	for _, v := range newImages {
+		p.Add(v)
+	}
+	return nil
+}
+
+func (p *SceneImages) RemoveImages(oldImages []string) error {
+	imageMap := map[uuid.UUID]bool{}
+	for _, x := range *p {
+		imageMap[x.ImageID] = true
+	}
+	for _, v := range oldImages {
+		if !imageMap[uuid.FromStringOrNil(v)] {
+			return errors.New("Invalid image removal. Image does not exist: '" + v + "'")
+		}
+	}
+	for _, v := range oldImages {
+		p.Remove(uuid.FromStringOrNil(v))
+	}
+	return nil
+}
+
 type PerformerImage struct {
 	PerformerID uuid.UUID `db:"performer_id" json:"performer_id"`
 	ImageID     uuid.UUID `db:"image_id" json:"image_id"`
@@ -110,6 +252,47 @@ func (p *PerformerImages) Add(o interface{}) {
 	*p = append(*p, o.(*PerformerImage))
 }
 
+func (p *PerformerImages) Remove(imageID uuid.UUID) {
+	for i, v := range *p {
+		if v.ImageID == imageID {
+			*p = append((*p)[:i], (*p)[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *PerformerImages) AddImages(newImages []*PerformerImage) error {
+	imageMap := map[uuid.UUID]bool{}
+	for _, x := range *p {
+		imageMap[x.ImageID] = true
+	}
+	for _, v := range newImages {
+		if imageMap[v.ImageID] {
+			return errors.New("Invalid image addition. Image already exists '" + v.ImageID.String() + "'")
+		}
+	}
+	for _, v := range newImages {
+		p.Add(v)
+	}
+	return nil
+}
+
+func (p *PerformerImages) RemoveImages(oldImages []string) error {
+	imageMap := map[uuid.UUID]bool{}
+	for _, x := range *p {
+		imageMap[x.ImageID] = true
+	}
+	for _, v := range oldImages {
+		if !imageMap[uuid.FromStringOrNil(v)] {
+			return errors.New("Invalid image removal. Image does not exist: '" + v + "'")
+		}
+	}
+	for _, v := range oldImages {
+		p.Remove(uuid.FromStringOrNil(v))
+	}
+	return nil
+}
+
 type StudioImage struct {
 	StudioID uuid.UUID `db:"studio_id" json:"studio_id"`
 	ImageID  uuid.UUID `db:"image_id" json:"image_id"`
@@ -126,3 +309,44 @@ func (p StudioImages) Each(fn func(interface{})) {
 func (p *StudioImages) Add(o interface{}) {
 	*p = append(*p, o.(*StudioImage))
 }
+
+func (p *StudioImages) Remove(imageID uuid.UUID) {
+	for i, v := range *p {
+		if v.ImageID == imageID {
+			*p = append((*p)[:i], (*p)[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *StudioImages) AddImages(newImages []*StudioImage) error {
+	imageMap := map[uuid.UUID]bool{}
+	for _, x := range *p {
+		imageMap[x.ImageID] = true
+	}
+	for _, v := range newImages {
+		if imageMap[v.ImageID] {
+			return errors.New("Invalid image addition. Image already exists '" + v.ImageID.String() + "'")
+		}
+	}
+	for _, v := range newImages {
+		p.Add(v)
+	}
+	return nil
+}
+
+func (p *StudioImages) RemoveImages(oldImages []string) error {
+	imageMap := map[uuid.UUID]bool{}
+	for _, x := range *p {
+		imageMap[x.ImageID] = true
+	}
+	for _, v := range oldImages {
+		if !imageMap[uuid.FromStringOrNil(v)] {
+			return errors.New("Invalid image removal. Image does not exist: '" + v + "'")
+		}
+	}
+	for _, v := range oldImages {
+		p.Remove(uuid.FromStringOrNil(v))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stashdb/pkg/database"
+)
+
+const (
+	notificationTable = "notifications"
+)
+
+var (
+	notificationDBTable = database.NewTable(notificationTable, func() interface{} {
+		return &Notification{}
+	})
+
+	notificationSubscriptionTable = database.NewTableJoin(userTable, "notification_subscriptions", userJoinKey, func() interface{} {
+		return &NotificationSubscription{}
+	})
+)
+
+// Notification records an event on an edit of interest to the user.
+type Notification struct {
+	ID        uuid.UUID           `db:"id" json:"id"`
+	UserID    uuid.UUID           `db:"user_id" json:"user_id"`
+	EditID    uuid.UUID           `db:"edit_id" json:"edit_id"`
+	Type      string              `db:"type" json:"type"`
+	CreatedAt SQLiteTimestamp     `db:"created_at" json:"created_at"`
+	ReadAt    NullSQLiteTimestamp `db:"read_at" json:"read_at"`
+	// Emailed is set once the notification has been sent in an email digest
+	Emailed bool `db:"emailed" json:"emailed"`
+}
+
+func NewNotification(UUID uuid.UUID, userID uuid.UUID, edit *Edit, notificationType NotificationEnum) *Notification {
+	return &Notification{
+		ID:        UUID,
+		UserID:    userID,
+		EditID:    edit.ID,
+		Type:      notificationType.String(),
+		CreatedAt: SQLiteTimestamp{Timestamp: time.Now()},
+	}
+}
+
+func (Notification) GetTable() database.Table {
+	return notificationDBTable
+}
+
+func (p Notification) GetID() uuid.UUID {
+	return p.ID
+}
+
+type Notifications []*Notification
+
+func (p Notifications) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *Notifications) Add(o interface{}) {
+	*p = append(*p, o.(*Notification))
+}
+
+// NotificationSubscription subscribes the user to notifications on the edits
+// applied to the target.
+type NotificationSubscription struct {
+	UserID     uuid.UUID       `db:"user_id" json:"user_id"`
+	TargetType string          `db:"target_type" json:"target_type"`
+	TargetID   uuid.UUID       `db:"target_id" json:"target_id"`
+	CreatedAt  SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+func NewNotificationSubscription(user *User, targetType TargetTypeEnum, targetID uuid.UUID) *NotificationSubscription {
+	return &NotificationSubscription{
+		UserID:     user.ID,
+		TargetType: targetType.String(),
+		TargetID:   targetID,
+		CreatedAt:  SQLiteTimestamp{Timestamp: time.Now()},
+	}
+}
+
+type NotificationSubscriptions []*NotificationSubscription
+
+func (p NotificationSubscriptions) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *NotificationSubscriptions) Add(o interface{}) {
+	*p = append(*p, o.(*NotificationSubscription))
+}
@@ -2,9 +2,13 @@ package models
 
 import (
 	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/gofrs/uuid"
 
 	"github.com/stashapp/stashdb/pkg/database"
+	"github.com/stashapp/stashdb/pkg/utils"
 )
 
 const (
@@ -32,6 +36,14 @@ var (
 	performerPiercingTable = database.NewTableJoin(performerTable, "performer_piercings", performerJoinKey, func() interface{} {
 		return &PerformerBodyMod{}
 	})
+
+	performerSearchMatchTable = database.NewTable(performerTable, func() interface{} {
+		return &performerSearchMatch{}
+	})
+
+	performerRedirectTable = database.NewTableJoin(performerTable, "performer_redirects", "source_id", func() interface{} {
+		return &PerformerRedirect{}
+	})
 )
 
 type Performer struct {
@@ -78,6 +90,48 @@ func (p *Performers) Add(o interface{}) {
 	*p = append(*p, o.(*Performer))
 }
 
+// performerSearchMatch is a performer along with the value that best matched
+// a search term.
+type performerSearchMatch struct {
+	Performer
+	Score        float64 `db:"score"`
+	MatchedField string  `db:"matched_field"`
+	MatchedValue string  `db:"matched_value"`
+}
+
+type performerSearchMatches []*performerSearchMatch
+
+func (p *performerSearchMatches) Add(o interface{}) {
+	*p = append(*p, o.(*performerSearchMatch))
+}
+
+type PerformerRedirect struct {
+	SourceID uuid.UUID `db:"source_id" json:"source_id"`
+	TargetID uuid.UUID `db:"target_id" json:"target_id"`
+}
+
+func (p PerformerRedirect) GetSourceID() uuid.UUID {
+	return p.SourceID
+}
+
+// PerformerSnapshot is the state of a performer and its joins before an
+// edit was applied to it.
+type PerformerSnapshot struct {
+	Performer         Performer         `json:"performer"`
+	Aliases           PerformerAliases  `json:"aliases"`
+	Urls              PerformerUrls     `json:"urls"`
+	Tattoos           PerformerBodyMods `json:"tattoos"`
+	Piercings         PerformerBodyMods `json:"piercings"`
+	Images            PerformerImages   `json:"images"`
+	ScenePerformers   PerformersScenes  `json:"scene_performers"`
+	RedirectSourceIDs []uuid.UUID       `json:"redirect_source_ids"`
+}
+
+type PerformerEditSnapshot struct {
+	Target       *PerformerSnapshot   `json:"target,omitempty"`
+	MergeSources []*PerformerSnapshot `json:"merge_sources,omitempty"`
+}
+
 type PerformerAlias struct {
 	PerformerID uuid.UUID `db:"performer_id" json:"performer_id"`
 	Alias       string    `db:"alias" json:"alias"`
@@ -95,6 +149,47 @@ func (p *PerformerAliases) Add(o interface{}) {
 	*p = append(*p, o.(*PerformerAlias))
 }
 
+func (p *PerformerAliases) Remove(alias string) {
+	for i, a := range *p {
+		if a.Alias == alias {
+			*p = append((*p)[:i], (*p)[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *PerformerAliases) AddAliases(newAliases []*PerformerAlias) error {
+	aliasMap := map[string]bool{}
+	for _, x := range *p {
+		aliasMap[x.Alias] = true
+	}
+	for _, v := range newAliases {
+		if aliasMap[v.Alias] {
+			return errors.New("Invalid alias addition. Alias already exists '" + v.Alias + "'")
+		}
+	}
+	for _, v := range newAliases {
+		p.Add(v)
+	}
+	return nil
+}
+
+func (p *PerformerAliases) RemoveAliases(oldAliases []string) error {
+	aliasMap := map[string]bool{}
+	for _, x := range *p {
+		aliasMap[x.Alias] = true
+	}
+	for _, v := range oldAliases {
+		if !aliasMap[v] {
+			return errors.New("Invalid alias removal. Alias does not exist: '" + v + "'")
+		}
+	}
+	for _, v := range oldAliases {
+		p.Remove(v)
+	}
+	return nil
+}
+
 func (p PerformerAliases) ToAliases() []string {
 	var ret []string
 	for _, v := range p {
@@ -140,6 +235,57 @@ func (p *PerformerUrls) Add(o interface{}) {
 	*p = append(*p, o.(*PerformerUrl))
 }
 
+func (p *PerformerUrls) Remove(url *URL) {
+	for i, u := range *p {
+		if u.URL == url.URL && u.Type == url.Type {
+			*p = append((*p)[:i], (*p)[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *PerformerUrls) AddUrls(newUrls []*PerformerUrl) error {
+	urlMap := map[URL]bool{}
+	for _, x := range *p {
+		urlMap[x.ToURL()] = true
+	}
+	for _, v := range newUrls {
+		if urlMap[v.ToURL()] {
+			return errors.New("Invalid URL addition. URL already exists '" + v.URL + "'")
+		}
+	}
+	for _, v := range newUrls {
+		p.Add(v)
+	}
+	return nil
+}
+
+func (p *PerformerUrls) RemoveUrls(oldUrls []*URL) error {
+	urlMap := map[URL]bool{}
+	for _, x := range *p {
+		urlMap[x.ToURL()] = true
+	}
+	for _, v := range oldUrls {
+		if !urlMap[*v] {
+			return errors.New("Invalid URL removal. URL does not exist: '" + v.URL + "'")
+		}
+	}
+	for _, v := range oldUrls {
+		p.Remove(v)
+	}
+	return nil
+}
+
+func (p PerformerUrls) ToURLs() []*URL {
+	var ret []*URL
+	for _, v := range p {
+		url := v.ToURL()
+		ret = append(ret, &url)
+	}
+
+	return ret
+}
+
 func CreatePerformerUrls(performerId uuid.UUID, urls []*URLInput) PerformerUrls {
 	var ret PerformerUrls
 
@@ -183,6 +329,68 @@ func (p *PerformerBodyMods) Add(o interface{}) {
 	*p = append(*p, o.(*PerformerBodyMod))
 }
 
+func (m PerformerBodyMod) matches(mod BodyModification) bool {
+	if m.Location != mod.Location {
+		return false
+	}
+	if mod.Description == nil {
+		return !m.Description.Valid
+	}
+	return m.Description.Valid && m.Description.String == *mod.Description
+}
+
+func (p *PerformerBodyMods) Remove(mod *BodyModification) {
+	for i, m := range *p {
+		if m.matches(*mod) {
+			*p = append((*p)[:i], (*p)[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *PerformerBodyMods) AddBodyMods(newMods []*PerformerBodyMod) error {
+	for _, v := range newMods {
+		for _, x := range *p {
+			if x.matches(v.ToBodyModification()) {
+				return errors.New("Invalid body modification addition. Body modification already exists '" + v.Location + "'")
+			}
+		}
+	}
+	for _, v := range newMods {
+		p.Add(v)
+	}
+	return nil
+}
+
+func (p *PerformerBodyMods) RemoveBodyMods(oldMods []*BodyModification) error {
+	for _, v := range oldMods {
+		found := false
+		for _, x := range *p {
+			if x.matches(*v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.New("Invalid body modification removal. Body modification does not exist: '" + v.Location + "'")
+		}
+	}
+	for _, v := range oldMods {
+		p.Remove(v)
+	}
+	return nil
+}
+
+func (p PerformerBodyMods) ToBodyModifications() []*BodyModification {
+	var ret []*BodyModification
+	for _, v := range p {
+		mod := v.ToBodyModification()
+		ret = append(ret, &mod)
+	}
+
+	return ret
+}
+
 func CreatePerformerBodyMods(performerId uuid.UUID, urls []*BodyModificationInput) PerformerBodyMods {
 	var ret PerformerBodyMods
 
@@ -305,3 +513,207 @@ func CreatePerformerImages(performerID uuid.UUID, imageIds []string) PerformerIm
 
 	return imageJoins
 }
+
+func CreatePerformerEditUrls(performerID uuid.UUID, urls []*URL) PerformerUrls {
+	var ret PerformerUrls
+
+	for _, url := range urls {
+		ret = append(ret, &PerformerUrl{
+			PerformerID: performerID,
+			URL:         url.URL,
+			Type:        url.Type,
+		})
+	}
+
+	return ret
+}
+
+func CreatePerformerEditBodyMods(performerID uuid.UUID, mods []*BodyModification) PerformerBodyMods {
+	var ret PerformerBodyMods
+
+	for _, mod := range mods {
+		description := sql.NullString{}
+
+		if mod.Description != nil {
+			description.String = *mod.Description
+			description.Valid = true
+		}
+		ret = append(ret, &PerformerBodyMod{
+			PerformerID: performerID,
+			Location:    mod.Location,
+			Description: description,
+		})
+	}
+
+	return ret
+}
+
+func (p *Performer) CopyFromPerformerEdit(input PerformerEdit) {
+	if input.Name != nil {
+		p.Name = *input.Name
+	}
+	if input.Disambiguation != nil {
+		p.Disambiguation = sql.NullString{String: *input.Disambiguation, Valid: true}
+	}
+	if input.Gender != nil {
+		p.Gender = sql.NullString{String: *input.Gender, Valid: true}
+	}
+	if input.Birthdate != nil {
+		p.Birthdate = SQLiteDate{String: *input.Birthdate, Valid: true}
+	}
+	if input.BirthdateAccuracy != nil {
+		p.BirthdateAccuracy = sql.NullString{String: *input.BirthdateAccuracy, Valid: true}
+	}
+	if input.Ethnicity != nil {
+		p.Ethnicity = sql.NullString{String: *input.Ethnicity, Valid: true}
+	}
+	if input.Country != nil {
+		p.Country = sql.NullString{String: *input.Country, Valid: true}
+	}
+	if input.EyeColor != nil {
+		p.EyeColor = sql.NullString{String: *input.EyeColor, Valid: true}
+	}
+	if input.HairColor != nil {
+		p.HairColor = sql.NullString{String: *input.HairColor, Valid: true}
+	}
+	if input.Height != nil {
+		p.Height = sql.NullInt64{Int64: int64(*input.Height), Valid: true}
+	}
+	if input.CupSize != nil {
+		p.CupSize = sql.NullString{String: *input.CupSize, Valid: true}
+	}
+	if input.BandSize != nil {
+		p.BandSize = sql.NullInt64{Int64: int64(*input.BandSize), Valid: true}
+	}
+	if input.WaistSize != nil {
+		p.WaistSize = sql.NullInt64{Int64: int64(*input.WaistSize), Valid: true}
+	}
+	if input.HipSize != nil {
+		p.HipSize = sql.NullInt64{Int64: int64(*input.HipSize), Valid: true}
+	}
+	if input.BreastType != nil {
+		p.BreastType = sql.NullString{String: *input.BreastType, Valid: true}
+	}
+	if input.CareerStartYear != nil {
+		p.CareerStartYear = sql.NullInt64{Int64: int64(*input.CareerStartYear), Valid: true}
+	}
+	if input.CareerEndYear != nil {
+		p.CareerEndYear = sql.NullInt64{Int64: int64(*input.CareerEndYear), Valid: true}
+	}
+	p.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
+}
+
+// UndoPerformerEdit sets the fields changed by the edit back to their values
+// before the edit was applied, including the fields cleared by the edit.
+func (p *Performer) UndoPerformerEdit(edit PerformerEditData) {
+	if edit.New == nil {
+		return
+	}
+	old := edit.Old
+	if old == nil {
+		old = &PerformerEdit{}
+	}
+	changed := func(set bool, field string) bool {
+		return set || utils.StrInclude(edit.New.ClearedFields, field)
+	}
+
+	if edit.New.Name != nil && old.Name != nil {
+		p.Name = *old.Name
+	}
+	if changed(edit.New.Disambiguation != nil, "disambiguation") {
+		p.Disambiguation = toNullString(old.Disambiguation)
+	}
+	if changed(edit.New.Gender != nil, "gender") {
+		p.Gender = toNullString(old.Gender)
+	}
+	if changed(edit.New.Birthdate != nil, "birthdate") {
+		birthdate := toNullString(old.Birthdate)
+		p.Birthdate = SQLiteDate{String: birthdate.String, Valid: birthdate.Valid}
+	}
+	if changed(edit.New.BirthdateAccuracy != nil, "birthdate_accuracy") {
+		p.BirthdateAccuracy = toNullString(old.BirthdateAccuracy)
+	}
+	if changed(edit.New.Ethnicity != nil, "ethnicity") {
+		p.Ethnicity = toNullString(old.Ethnicity)
+	}
+	if changed(edit.New.Country != nil, "country") {
+		p.Country = toNullString(old.Country)
+	}
+	if changed(edit.New.EyeColor != nil, "eye_color") {
+		p.EyeColor = toNullString(old.EyeColor)
+	}
+	if changed(edit.New.HairColor != nil, "hair_color") {
+		p.HairColor = toNullString(old.HairColor)
+	}
+	if changed(edit.New.Height != nil, "height") {
+		p.Height = toNullInt64(old.Height)
+	}
+	if changed(edit.New.CupSize != nil, "cup_size") {
+		p.CupSize = toNullString(old.CupSize)
+	}
+	if changed(edit.New.BandSize != nil, "band_size") {
+		p.BandSize = toNullInt64(old.BandSize)
+	}
+	if changed(edit.New.WaistSize != nil, "waist_size") {
+		p.WaistSize = toNullInt64(old.WaistSize)
+	}
+	if changed(edit.New.HipSize != nil, "hip_size") {
+		p.HipSize = toNullInt64(old.HipSize)
+	}
+	if changed(edit.New.BreastType != nil, "breast_type") {
+		p.BreastType = toNullString(old.BreastType)
+	}
+	if changed(edit.New.CareerStartYear != nil, "career_start_year") {
+		p.CareerStartYear = toNullInt64(old.CareerStartYear)
+	}
+	if changed(edit.New.CareerEndYear != nil, "career_end_year") {
+		p.CareerEndYear = toNullInt64(old.CareerEndYear)
+	}
+}
+
+// ModifyEditConflicts returns the fields modified by the edit whose current
+// value differs from the value expected by the edit.
+func (p *Performer) ModifyEditConflicts(edit PerformerEditData) []*EditConflict {
+	if edit.New == nil || edit.Old == nil {
+		return nil
+	}
+
+	birthdate := sql.NullString{String: p.Birthdate.String, Valid: p.Birthdate.Valid}
+	return findEditConflicts([]editConflictField{
+		{"name", edit.New.Name != nil, edit.Old.Name, &p.Name},
+		{"disambiguation", edit.New.Disambiguation != nil, edit.Old.Disambiguation, nullStringPtr(p.Disambiguation)},
+		{"gender", edit.New.Gender != nil, edit.Old.Gender, nullStringPtr(p.Gender)},
+		{"birthdate", edit.New.Birthdate != nil, edit.Old.Birthdate, nullStringPtr(birthdate)},
+		{"birthdate accuracy", edit.New.BirthdateAccuracy != nil, edit.Old.BirthdateAccuracy, nullStringPtr(p.BirthdateAccuracy)},
+		{"ethnicity", edit.New.Ethnicity != nil, edit.Old.Ethnicity, nullStringPtr(p.Ethnicity)},
+		{"country", edit.New.Country != nil, edit.Old.Country, nullStringPtr(p.Country)},
+		{"eye color", edit.New.EyeColor != nil, edit.Old.EyeColor, nullStringPtr(p.EyeColor)},
+		{"hair color", edit.New.HairColor != nil, edit.Old.HairColor, nullStringPtr(p.HairColor)},
+		{"cup size", edit.New.CupSize != nil, edit.Old.CupSize, nullStringPtr(p.CupSize)},
+		{"breast type", edit.New.BreastType != nil, edit.Old.BreastType, nullStringPtr(p.BreastType)},
+		{"height", edit.New.Height != nil, intStringPtr(edit.Old.Height), nullInt64StringPtr(p.Height)},
+		{"band size", edit.New.BandSize != nil, intStringPtr(edit.Old.BandSize), nullInt64StringPtr(p.BandSize)},
+		{"waist size", edit.New.WaistSize != nil, intStringPtr(edit.Old.WaistSize), nullInt64StringPtr(p.WaistSize)},
+		{"hip size", edit.New.HipSize != nil, intStringPtr(edit.Old.HipSize), nullInt64StringPtr(p.HipSize)},
+		{"career start year", edit.New.CareerStartYear != nil, intStringPtr(edit.Old.CareerStartYear), nullInt64StringPtr(p.CareerStartYear)},
+		{"career end year", edit.New.CareerEndYear != nil, intStringPtr(edit.Old.CareerEndYear), nullInt64StringPtr(p.CareerEndYear)},
+	})
+}
+
+func (p *Performer) ValidateModifyEdit(edit PerformerEditData) error {
+	return validateEditConflicts(p.ModifyEditConflicts(edit))
+}
+
+// QueryPerformersResultType is a page of the performers matching a
+// performer filter.
+type QueryPerformersResultType struct {
+	Count      int
+	Performers []*Performer
+	Facets     *PerformerFacets
+}
+
+// PerformerFacets holds the performer filter of a query, so that each facet
+// is only counted when requested.
+type PerformerFacets struct {
+	Filter *PerformerFilterType
+}
@@ -2,6 +2,9 @@ package models
 
 import (
 	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/gofrs/uuid"
 
 	"github.com/stashapp/stashdb/pkg/database"
@@ -21,9 +24,33 @@ var (
 		return &SceneFingerprint{}
 	})
 
+	sceneFingerprintSubmissionTable = database.NewTableJoin(sceneTable, "scene_fingerprint_submissions", sceneJoinKey, func() interface{} {
+		return &SceneFingerprintSubmission{}
+	})
+
+	sceneFingerprintReportTable = database.NewTableJoin(sceneTable, "scene_fingerprint_reports", sceneJoinKey, func() interface{} {
+		return &SceneFingerprintReport{}
+	})
+
+	sceneFingerprintReportCountTable = database.NewTable("scene_fingerprints", func() interface{} {
+		return &sceneFingerprintReportCount{}
+	})
+
+	sceneFingerprintMatchTable = database.NewTable(sceneTable, func() interface{} {
+		return &sceneFingerprintMatch{}
+	})
+
+	sceneFingerprintCountTable = database.NewTable("scene_fingerprints", func() interface{} {
+		return &sceneFingerprintCount{}
+	})
+
 	sceneUrlTable = database.NewTableJoin(sceneTable, "scene_urls", sceneJoinKey, func() interface{} {
 		return &SceneUrl{}
 	})
+
+	sceneRedirectTable = database.NewTableJoin(sceneTable, "scene_redirects", "source_id", func() interface{} {
+		return &SceneRedirect{}
+	})
 )
 
 type Scene struct {
@@ -59,11 +86,150 @@ func (p *Scenes) Add(o interface{}) {
 	*p = append(*p, o.(*Scene))
 }
 
+// sceneFingerprintMatch is a scene along with the fingerprint it matched.
+type sceneFingerprintMatch struct {
+	Scene
+	FingerprintAlgorithm string `db:"fingerprint_algorithm"`
+	FingerprintHash      string `db:"fingerprint_hash"`
+	FingerprintDuration  int    `db:"fingerprint_duration"`
+}
+
+type sceneFingerprintMatches []*sceneFingerprintMatch
+
+func (p *sceneFingerprintMatches) Add(o interface{}) {
+	*p = append(*p, o.(*sceneFingerprintMatch))
+}
+
+type SceneRedirect struct {
+	SourceID uuid.UUID `db:"source_id" json:"source_id"`
+	TargetID uuid.UUID `db:"target_id" json:"target_id"`
+}
+
+func (p SceneRedirect) GetSourceID() uuid.UUID {
+	return p.SourceID
+}
+
+// SceneSnapshot is the state of a scene and its joins before an edit was
+// applied to it.
+type SceneSnapshot struct {
+	Scene             Scene             `json:"scene"`
+	Urls              SceneUrls         `json:"urls"`
+	Images            SceneImages       `json:"images"`
+	Performers        PerformersScenes  `json:"performers"`
+	Tags              ScenesTags        `json:"tags"`
+	Fingerprints      SceneFingerprints `json:"fingerprints"`
+	RedirectSourceIDs []uuid.UUID       `json:"redirect_source_ids"`
+}
+
+type SceneEditSnapshot struct {
+	Target       *SceneSnapshot   `json:"target,omitempty"`
+	MergeSources []*SceneSnapshot `json:"merge_sources,omitempty"`
+}
+
 type SceneFingerprint struct {
 	SceneID   uuid.UUID `db:"scene_id" json:"scene_id"`
 	Hash      string    `db:"hash" json:"hash"`
 	Algorithm string    `db:"algorithm" json:"algorithm"`
 	Duration  int       `db:"duration" json:"duration"`
+	// Phash is the integer value of PHASH fingerprints, set by the database
+	Phash     sql.NullInt64   `db:"phash" json:"-"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+	// UpdatedAt is the time of the latest submission of the fingerprint
+	UpdatedAt SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+}
+
+// Fingerprint is a fingerprint of a scene, or of the details of an edit. The
+// submission fields are only set for fingerprints of scenes, and are not
+// stored in edit details.
+type Fingerprint struct {
+	Hash        string               `json:"hash"`
+	Algorithm   FingerprintAlgorithm `json:"algorithm"`
+	Duration    int                  `json:"duration"`
+	Submissions int                  `json:"-"`
+	Created     time.Time            `json:"-"`
+	Updated     time.Time            `json:"-"`
+}
+
+// SceneFingerprintSubmission records the submission of a fingerprint of a
+// scene by a user.
+type SceneFingerprintSubmission struct {
+	SceneID   uuid.UUID       `db:"scene_id" json:"scene_id"`
+	Algorithm string          `db:"algorithm" json:"algorithm"`
+	Hash      string          `db:"hash" json:"hash"`
+	UserID    uuid.UUID       `db:"user_id" json:"user_id"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+func NewSceneFingerprintSubmission(sceneID uuid.UUID, fingerprint *FingerprintInput, user *User) *SceneFingerprintSubmission {
+	return &SceneFingerprintSubmission{
+		SceneID:   sceneID,
+		Algorithm: fingerprint.Algorithm.String(),
+		Hash:      fingerprint.Hash,
+		UserID:    user.ID,
+		CreatedAt: SQLiteTimestamp{Timestamp: time.Now()},
+	}
+}
+
+// SceneFingerprintReport records a report by a user that a fingerprint does
+// not belong to the scene.
+type SceneFingerprintReport struct {
+	SceneID   uuid.UUID       `db:"scene_id" json:"scene_id"`
+	Algorithm string          `db:"algorithm" json:"algorithm"`
+	Hash      string          `db:"hash" json:"hash"`
+	UserID    uuid.UUID       `db:"user_id" json:"user_id"`
+	Reason    string          `db:"reason" json:"reason"`
+	CreatedAt SQLiteTimestamp `db:"created_at" json:"created_at"`
+}
+
+func NewSceneFingerprintReport(sceneID uuid.UUID, input FingerprintReportInput, user *User) *SceneFingerprintReport {
+	return &SceneFingerprintReport{
+		SceneID:   sceneID,
+		Algorithm: input.Algorithm.String(),
+		Hash:      input.Hash,
+		UserID:    user.ID,
+		Reason:    input.Reason,
+		CreatedAt: SQLiteTimestamp{Timestamp: time.Now()},
+	}
+}
+
+type SceneFingerprintReports []*SceneFingerprintReport
+
+func (p *SceneFingerprintReports) Add(o interface{}) {
+	*p = append(*p, o.(*SceneFingerprintReport))
+}
+
+// ReportedFingerprint is a fingerprint of a scene along with its number of
+// reports.
+type ReportedFingerprint struct {
+	SceneID     uuid.UUID
+	Fingerprint *Fingerprint
+	ReportCount int
+}
+
+// sceneFingerprintCount is a fingerprint along with its number of
+// submissions.
+type sceneFingerprintCount struct {
+	SceneFingerprint
+	Submissions int `db:"submissions"`
+}
+
+type sceneFingerprintCounts []*sceneFingerprintCount
+
+func (p *sceneFingerprintCounts) Add(o interface{}) {
+	*p = append(*p, o.(*sceneFingerprintCount))
+}
+
+// sceneFingerprintReportCount is a fingerprint along with its number of
+// submissions and reports.
+type sceneFingerprintReportCount struct {
+	sceneFingerprintCount
+	Reports int `db:"reports"`
+}
+
+type sceneFingerprintReportCounts []*sceneFingerprintReportCount
+
+func (p *sceneFingerprintReportCounts) Add(o interface{}) {
+	*p = append(*p, o.(*sceneFingerprintReportCount))
 }
 
 type SceneUrl struct {
@@ -92,6 +258,57 @@ func (p *SceneUrls) Add(o interface{}) {
 	*p = append(*p, o.(*SceneUrl))
 }
 
+func (p *SceneUrls) Remove(url *URL) {
+	for i, u := range *p {
+		if u.URL == url.URL && u.Type == url.Type {
+			*p = append((*p)[:i], (*p)[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *SceneUrls) AddUrls(newUrls []*SceneUrl) error {
+	urlMap := map[URL]bool{}
+	for _, x := range *p {
+		urlMap[x.ToURL()] = true
+	}
+	for _, v := range newUrls {
+		if urlMap[v.ToURL()] {
+			return errors.New("Invalid URL addition. URL already exists '" + v.URL + "'")
+		}
+	}
+	for _, v := range newUrls {
+		p.Add(v)
+	}
+	return nil
+}
+
+func (p *SceneUrls) RemoveUrls(oldUrls []*URL) error {
+	urlMap := map[URL]bool{}
+	for _, x := range *p {
+		urlMap[x.ToURL()] = true
+	}
+	for _, v := range oldUrls {
+		if !urlMap[*v] {
+			return errors.New("Invalid URL removal. URL does not exist: '" + v.URL + "'")
+		}
+	}
+	for _, v := range oldUrls {
+		p.Remove(v)
+	}
+	return nil
+}
+
+func (p SceneUrls) ToURLs() []*URL {
+	var ret []*URL
+	for _, v := range p {
+		url := v.ToURL()
+		ret = append(ret, &url)
+	}
+
+	return ret
+}
+
 func CreateSceneUrls(sceneId uuid.UUID, urls []*URLInput) SceneUrls {
 	var ret SceneUrls
 
@@ -111,6 +328,8 @@ func (p SceneFingerprint) ToFingerprint() *Fingerprint {
 		Algorithm: FingerprintAlgorithm(p.Algorithm),
 		Hash:      p.Hash,
 		Duration:  p.Duration,
+		Created:   p.CreatedAt.Timestamp,
+		Updated:   p.UpdatedAt.Timestamp,
 	}
 }
 
@@ -126,6 +345,47 @@ func (p *SceneFingerprints) Add(o interface{}) {
 	*p = append(*p, o.(*SceneFingerprint))
 }
 
+func (p *SceneFingerprints) Remove(fingerprint *Fingerprint) {
+	for i, f := range *p {
+		if f.Hash == fingerprint.Hash && f.Algorithm == fingerprint.Algorithm.String() {
+			*p = append((*p)[:i], (*p)[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *SceneFingerprints) AddFingerprints(newFingerprints []*SceneFingerprint) error {
+	fingerprintMap := map[string]bool{}
+	for _, x := range *p {
+		fingerprintMap[x.Algorithm+x.Hash] = true
+	}
+	for _, v := range newFingerprints {
+		if fingerprintMap[v.Algorithm+v.Hash] {
+			return errors.New("Invalid fingerprint addition. Fingerprint already exists '" + v.Hash + "'")
+		}
+	}
+	for _, v := range newFingerprints {
+		p.Add(v)
+	}
+	return nil
+}
+
+func (p *SceneFingerprints) RemoveFingerprints(oldFingerprints []*Fingerprint) error {
+	fingerprintMap := map[string]bool{}
+	for _, x := range *p {
+		fingerprintMap[x.Algorithm+x.Hash] = true
+	}
+	for _, v := range oldFingerprints {
+		if !fingerprintMap[v.Algorithm.String()+v.Hash] {
+			return errors.New("Invalid fingerprint removal. Fingerprint does not exist: '" + v.Hash + "'")
+		}
+	}
+	for _, v := range oldFingerprints {
+		p.Remove(v)
+	}
+	return nil
+}
+
 func (p SceneFingerprints) ToFingerprints() []*Fingerprint {
 	var ret []*Fingerprint
 	for _, v := range p {
@@ -137,6 +397,39 @@ func (p SceneFingerprints) ToFingerprints() []*Fingerprint {
 
 func CreateSceneFingerprints(sceneID uuid.UUID, fingerprints []*FingerprintInput) SceneFingerprints {
 	var ret SceneFingerprints
+	currentTime := SQLiteTimestamp{Timestamp: time.Now()}
+
+	for _, fingerprint := range fingerprints {
+		ret = append(ret, &SceneFingerprint{
+			SceneID:   sceneID,
+			Hash:      fingerprint.Hash,
+			Algorithm: fingerprint.Algorithm.String(),
+			Duration:  fingerprint.Duration,
+			CreatedAt: currentTime,
+			UpdatedAt: currentTime,
+		})
+	}
+
+	return ret
+}
+
+func CreateSceneEditUrls(sceneID uuid.UUID, urls []*URL) SceneUrls {
+	var ret SceneUrls
+
+	for _, url := range urls {
+		ret = append(ret, &SceneUrl{
+			SceneID: sceneID,
+			URL:     url.URL,
+			Type:    url.Type,
+		})
+	}
+
+	return ret
+}
+
+func CreateSceneEditFingerprints(sceneID uuid.UUID, fingerprints []*Fingerprint) SceneFingerprints {
+	var ret SceneFingerprints
+	currentTime := SQLiteTimestamp{Timestamp: time.Now()}
 
 	for _, fingerprint := range fingerprints {
 		ret = append(ret, &SceneFingerprint{
@@ -144,6 +437,8 @@ func CreateSceneFingerprints(sceneID uuid.UUID, fingerprints []*FingerprintInput
 			Hash:      fingerprint.Hash,
 			Algorithm: fingerprint.Algorithm.String(),
 			Duration:  fingerprint.Duration,
+			CreatedAt: currentTime,
+			UpdatedAt: currentTime,
 		})
 	}
 
@@ -219,3 +514,74 @@ func (p *Scene) CopyFromUpdateInput(input SceneUpdateInput) {
 		p.setDate(*input.Date)
 	}
 }
+
+func (p *Scene) CopyFromSceneEdit(input SceneEdit) {
+	if input.Title != nil {
+		p.Title = sql.NullString{String: *input.Title, Valid: true}
+	}
+	if input.Details != nil {
+		p.Details = sql.NullString{String: *input.Details, Valid: true}
+	}
+	if input.Date != nil {
+		p.setDate(*input.Date)
+	}
+	if input.StudioID != nil {
+		studioID, err := uuid.FromString(*input.StudioID)
+		p.StudioID = uuid.NullUUID{UUID: studioID, Valid: err == nil}
+	}
+	if input.Duration != nil {
+		p.Duration = sql.NullInt64{Int64: int64(*input.Duration), Valid: true}
+	}
+	if input.Director != nil {
+		p.Director = sql.NullString{String: *input.Director, Valid: true}
+	}
+	p.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
+}
+
+// ModifyEditConflicts returns the fields modified by the edit whose current
+// value differs from the value expected by the edit.
+func (p *Scene) ModifyEditConflicts(edit SceneEditData) []*EditConflict {
+	if edit.New == nil || edit.Old == nil {
+		return nil
+	}
+
+	date := sql.NullString{String: p.Date.String, Valid: p.Date.Valid}
+	studioID := sql.NullString{String: p.StudioID.UUID.String(), Valid: p.StudioID.Valid}
+	return findEditConflicts([]editConflictField{
+		{"title", edit.New.Title != nil, edit.Old.Title, nullStringPtr(p.Title)},
+		{"details", edit.New.Details != nil, edit.Old.Details, nullStringPtr(p.Details)},
+		{"date", edit.New.Date != nil, edit.Old.Date, nullStringPtr(date)},
+		{"studio", edit.New.StudioID != nil, edit.Old.StudioID, nullStringPtr(studioID)},
+		{"director", edit.New.Director != nil, edit.Old.Director, nullStringPtr(p.Director)},
+		{"duration", edit.New.Duration != nil, intStringPtr(edit.Old.Duration), nullInt64StringPtr(p.Duration)},
+	})
+}
+
+func (p *Scene) ValidateModifyEdit(edit SceneEditData) error {
+	return validateEditConflicts(p.ModifyEditConflicts(edit))
+}
+
+// QueryScenesResultType is a page of the scenes matching a scene filter.
+type QueryScenesResultType struct {
+	Count  int
+	Scenes []*Scene
+	Facets *SceneFacets
+}
+
+// SceneFacets holds the scene filter of a query, so that each facet is only
+// counted when requested.
+type SceneFacets struct {
+	Filter *SceneFilterType
+}
+
+// StudioFacet is the number of scenes matching a filter from a studio.
+type StudioFacet struct {
+	StudioID uuid.UUID
+	Count    int
+}
+
+// TagFacet is the number of scenes matching a filter with a tag.
+type TagFacet struct {
+	TagID uuid.UUID
+	Count int
+}
@@ -1,6 +1,10 @@
 package models
 
 import (
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/gofrs/uuid"
 
 	"github.com/stashapp/stashdb/pkg/database"
@@ -19,6 +23,10 @@ var (
 	studioUrlTable = database.NewTableJoin(studioTable, "studio_urls", studioJoinKey, func() interface{} {
 		return &StudioUrl{}
 	})
+
+	studioRedirectTable = database.NewTableJoin(studioTable, "studio_redirects", "source_id", func() interface{} {
+		return &StudioRedirect{}
+	})
 )
 
 type Studio struct {
@@ -50,6 +58,34 @@ func (p *Studios) Add(o interface{}) {
 	*p = append(*p, o.(*Studio))
 }
 
+type StudioRedirect struct {
+	SourceID uuid.UUID `db:"source_id" json:"source_id"`
+	TargetID uuid.UUID `db:"target_id" json:"target_id"`
+}
+
+func (p StudioRedirect) GetSourceID() uuid.UUID {
+	return p.SourceID
+}
+
+// StudioSnapshot is the state of a studio and its joins before an edit was
+// applied to it. SceneIDs is only populated for merge sources.
+type StudioSnapshot struct {
+	Studio            Studio       `json:"studio"`
+	Urls              StudioUrls   `json:"urls"`
+	Images            StudioImages `json:"images"`
+	ChildStudioIDs    []uuid.UUID  `json:"child_studio_ids"`
+	SceneIDs          []uuid.UUID  `json:"scene_ids"`
+	RedirectSourceIDs []uuid.UUID  `json:"redirect_source_ids"`
+}
+
+type StudioEditSnapshot struct {
+	Target       *StudioSnapshot   `json:"target,omitempty"`
+	MergeSources []*StudioSnapshot `json:"merge_sources,omitempty"`
+	// AddedChildStudios are the studios added as children by the edit, with
+	// their previous parent studio
+	AddedChildStudios Studios `json:"added_child_studios,omitempty"`
+}
+
 type StudioUrl struct {
 	StudioID uuid.UUID `db:"studio_id" json:"studio_id"`
 	URL      string    `db:"url" json:"url"`
@@ -76,6 +112,57 @@ func (p *StudioUrls) Add(o interface{}) {
 	*p = append(*p, (o.(*StudioUrl)))
 }
 
+func (p *StudioUrls) Remove(url *URL) {
+	for i, u := range *p {
+		if u.URL == url.URL && u.Type == url.Type {
+			*p = append((*p)[:i], (*p)[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *StudioUrls) AddUrls(newUrls []*StudioUrl) error {
+	urlMap := map[URL]bool{}
+	for _, x := range *p {
+		urlMap[x.ToURL()] = true
+	}
+	for _, v := range newUrls {
+		if urlMap[v.ToURL()] {
+			return errors.New("Invalid URL addition. URL already exists '" + v.URL + "'")
+		}
+	}
+	for _, v := range newUrls {
+		p.Add(v)
+	}
+	return nil
+}
+
+func (p *StudioUrls) RemoveUrls(oldUrls []*URL) error {
+	urlMap := map[URL]bool{}
+	for _, x := range *p {
+		urlMap[x.ToURL()] = true
+	}
+	for _, v := range oldUrls {
+		if !urlMap[*v] {
+			return errors.New("Invalid URL removal. URL does not exist: '" + v.URL + "'")
+		}
+	}
+	for _, v := range oldUrls {
+		p.Remove(v)
+	}
+	return nil
+}
+
+func (p StudioUrls) ToURLs() []*URL {
+	var ret []*URL
+	for _, v := range p {
+		url := v.ToURL()
+		ret = append(ret, &url)
+	}
+
+	return ret
+}
+
 func CreateStudioUrls(studioId uuid.UUID, urls []*URLInput) StudioUrls {
 	var ret StudioUrls
 
@@ -90,6 +177,20 @@ func CreateStudioUrls(studioId uuid.UUID, urls []*URLInput) StudioUrls {
 	return ret
 }
 
+func CreateStudioEditUrls(studioID uuid.UUID, urls []*URL) StudioUrls {
+	var ret StudioUrls
+
+	for _, url := range urls {
+		ret = append(ret, &StudioUrl{
+			StudioID: studioID,
+			URL:      url.URL,
+			Type:     url.Type,
+		})
+	}
+
+	return ret
+}
+
 func (p *Studio) IsEditTarget() {
 }
 
@@ -128,3 +229,34 @@ func CreateStudioImages(studioID uuid.UUID, imageIds []string) StudioImages {
 
 	return imageJoins
 }
+
+func (p *Studio) CopyFromStudioEdit(input StudioEdit) {
+	if input.Name != nil {
+		p.Name = *input.Name
+	}
+	if input.ParentID != nil {
+		parentID, err := uuid.FromString(*input.ParentID)
+		p.ParentStudioID = uuid.NullUUID{UUID: parentID, Valid: err == nil}
+	} else if input.RemoveParent {
+		p.ParentStudioID = uuid.NullUUID{}
+	}
+	p.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
+}
+
+// ModifyEditConflicts returns the fields modified by the edit whose current
+// value differs from the value expected by the edit.
+func (p *Studio) ModifyEditConflicts(edit StudioEditData) []*EditConflict {
+	if edit.New == nil || edit.Old == nil {
+		return nil
+	}
+
+	parentID := sql.NullString{String: p.ParentStudioID.UUID.String(), Valid: p.ParentStudioID.Valid}
+	return findEditConflicts([]editConflictField{
+		{"name", edit.New.Name != nil, edit.Old.Name, &p.Name},
+		{"parent studio", edit.New.ParentID != nil || edit.New.RemoveParent, edit.Old.ParentID, nullStringPtr(parentID)},
+	})
+}
+
+func (p *Studio) ValidateModifyEdit(edit StudioEditData) error {
+	return validateEditConflicts(p.ModifyEditConflicts(edit))
+}
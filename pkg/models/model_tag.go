@@ -63,6 +63,24 @@ type TagRedirect struct {
 	TargetID uuid.UUID `db:"target_id" json:"target_id"`
 }
 
+func (p TagRedirect) GetSourceID() uuid.UUID {
+	return p.SourceID
+}
+
+// TagSnapshot is the state of a tag and its joins before an edit was
+// applied to it.
+type TagSnapshot struct {
+	Tag               Tag         `json:"tag"`
+	Aliases           TagAliases  `json:"aliases"`
+	SceneTags         ScenesTags  `json:"scene_tags"`
+	RedirectSourceIDs []uuid.UUID `json:"redirect_source_ids"`
+}
+
+type TagEditSnapshot struct {
+	Target       *TagSnapshot   `json:"target,omitempty"`
+	MergeSources []*TagSnapshot `json:"merge_sources,omitempty"`
+}
+
 type TagAlias struct {
 	TagID uuid.UUID `db:"tag_id" json:"tag_id"`
 	Alias string    `db:"alias" json:"alias"`
@@ -163,13 +181,19 @@ func (p *Tag) CopyFromTagEdit(input TagEdit) {
 	p.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
 }
 
-func (p *Tag) ValidateModifyEdit(edit TagEditData) error {
-	if edit.Old.Name != nil && *edit.Old.Name != p.Name {
-		return errors.New("Invalid name. Expected '" + *edit.Old.Name + "'  but was '" + p.Name + "'")
-	}
-	if edit.Old.Description != nil && *edit.Old.Description != p.Description.String {
-		return errors.New("Invalid description. Expected '" + *edit.Old.Description + "'  but was '" + p.Description.String + "'")
+// ModifyEditConflicts returns the fields modified by the edit whose current
+// value differs from the value expected by the edit.
+func (p *Tag) ModifyEditConflicts(edit TagEditData) []*EditConflict {
+	if edit.New == nil || edit.Old == nil {
+		return nil
 	}
 
-	return nil
+	return findEditConflicts([]editConflictField{
+		{"name", edit.New.Name != nil, edit.Old.Name, &p.Name},
+		{"description", edit.New.Description != nil, edit.Old.Description, nullStringPtr(p.Description)},
+	})
+}
+
+func (p *Tag) ValidateModifyEdit(edit TagEditData) error {
+	return validateEditConflicts(p.ModifyEditConflicts(edit))
 }
@@ -22,6 +22,10 @@ var (
 	userRolesTable = database.NewTableJoin(userTable, "user_roles", userJoinKey, func() interface{} {
 		return &UserRole{}
 	})
+
+	userEditStatsTable = database.NewTable(userTable, func() interface{} {
+		return &UserEditStats{}
+	})
 )
 
 type User struct {
@@ -52,6 +56,28 @@ func (p *User) RemoveSensitiveFields() {
 	p.APIKey = ""
 }
 
+// UserEditStats holds the number of edits of a user and of votes cast by the
+// user, by outcome of the edit.
+type UserEditStats struct {
+	UserID            uuid.UUID `db:"user_id"`
+	SuccessfulEdits   int       `db:"successful_edits"`
+	UnsuccessfulEdits int       `db:"unsuccessful_edits"`
+	SuccessfulVotes   int       `db:"successful_votes"`
+	UnsuccessfulVotes int       `db:"unsuccessful_votes"`
+}
+
+type UserEditStatsList []*UserEditStats
+
+func (p UserEditStatsList) Each(fn func(interface{})) {
+	for _, v := range p {
+		fn(*v)
+	}
+}
+
+func (p *UserEditStatsList) Add(o interface{}) {
+	*p = append(*p, o.(*UserEditStats))
+}
+
 type Users []*User
 
 func (p Users) Each(fn func(interface{})) {
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// phashSegments is the number of 16-bit segments of a PHASH indexed by the
+// database.
+const phashSegments = 4
+
+// phashMaxSegmentDistance is the largest segment distance for which candidate
+// segment values are enumerated. Searches for greater distances compare the
+// hash against every PHASH fingerprint.
+const phashMaxSegmentDistance = 4
+
+// ParsePhash returns the 64-bit integer value of a PHASH fingerprint, given as
+// up to 16 hexadecimal digits.
+func ParsePhash(hash string) (int64, error) {
+	value, err := strconv.ParseUint(hash, 16, 64)
+	if err != nil {
+		return 0, errors.New("Invalid PHASH: " + hash)
+	}
+
+	return int64(value), nil
+}
+
+// FormatPhash returns the 16 digit hexadecimal form of the PHASH value.
+func FormatPhash(phash int64) string {
+	return fmt.Sprintf("%016x", uint64(phash))
+}
+
+// ValidateFingerprints validates the hashes of the fingerprints, normalising
+// PHASH hashes to their 16 digit hexadecimal form so that equal hashes are
+// stored and matched identically.
+func ValidateFingerprints(fingerprints []*FingerprintInput) error {
+	for _, fingerprint := range fingerprints {
+		if fingerprint.Algorithm == FingerprintAlgorithmPhash {
+			phash, err := ParsePhash(fingerprint.Hash)
+			if err != nil {
+				return err
+			}
+			fingerprint.Hash = FormatPhash(phash)
+		}
+	}
+
+	return nil
+}
+
+// ValidateFingerprintQueries validates the hashes of the fingerprint queries,
+// normalising PHASH hashes as they are stored.
+func ValidateFingerprintQueries(fingerprints []*FingerprintQueryInput) error {
+	for _, fingerprint := range fingerprints {
+		if fingerprint.Algorithm == FingerprintAlgorithmPhash {
+			phash, err := ParsePhash(fingerprint.Hash)
+			if err != nil {
+				return err
+			}
+			fingerprint.Hash = FormatPhash(phash)
+		}
+	}
+
+	return nil
+}
+
+// phashSegment returns the 16-bit segment of the PHASH value, where segment
+// 0 holds the most significant bits.
+func phashSegment(phash int64, segment int) int {
+	shift := uint(16 * (phashSegments - 1 - segment))
+	return int((uint64(phash) >> shift) & 0xffff)
+}
+
+// phashSegmentCandidates returns the 16-bit values within the Hamming
+// distance of the segment value.
+func phashSegmentCandidates(value int, distance int) []int {
+	ret := []int{value}
+	if distance <= 0 {
+		return ret
+	}
+
+	// flip each combination of up to distance bits, flipping bits in
+	// increasing order so that each combination is produced once
+	var flip func(value int, from int, remaining int)
+	flip = func(value int, from int, remaining int) {
+		for bit := from; bit < 16; bit++ {
+			flipped := value ^ (1 << uint(bit))
+			ret = append(ret, flipped)
+			if remaining > 1 {
+				flip(flipped, bit+1, remaining-1)
+			}
+		}
+	}
+	flip(value, 0, distance)
+
+	return ret
+}
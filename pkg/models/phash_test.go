@@ -0,0 +1,69 @@
+package models
+
+import (
+	"math/bits"
+	"testing"
+)
+
+func TestParsePhash(t *testing.T) {
+	phash, err := ParsePhash("ffffffffffffffff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if phash != -1 {
+		t.Errorf("ParsePhash: got %d want -1", phash)
+	}
+
+	if _, err := ParsePhash("not a hash"); err == nil {
+		t.Error("expected error parsing invalid hash")
+	}
+	if _, err := ParsePhash("1ffffffffffffffff"); err == nil {
+		t.Error("expected error parsing hash longer than 64 bits")
+	}
+}
+
+func TestValidateFingerprints(t *testing.T) {
+	phash := &FingerprintInput{Hash: "ABC", Algorithm: FingerprintAlgorithmPhash}
+	md5 := &FingerprintInput{Hash: "ABC", Algorithm: FingerprintAlgorithmMd5}
+	if err := ValidateFingerprints([]*FingerprintInput{phash, md5}); err != nil {
+		t.Fatal(err)
+	}
+
+	if phash.Hash != "0000000000000abc" {
+		t.Errorf("PHASH hash: got %s want 0000000000000abc", phash.Hash)
+	}
+	if md5.Hash != "ABC" {
+		t.Errorf("MD5 hash: got %s want ABC", md5.Hash)
+	}
+}
+
+func TestPhashSegment(t *testing.T) {
+	phash, _ := ParsePhash("123456789abcdef0")
+	expected := []int{0x1234, 0x5678, 0x9abc, 0xdef0}
+	for i, want := range expected {
+		if got := phashSegment(phash, i); got != want {
+			t.Errorf("segment %d: got %x want %x", i, got, want)
+		}
+	}
+}
+
+func TestPhashSegmentCandidates(t *testing.T) {
+	// 1 + 16 + 120 + 560 values within a distance of 3
+	value := 0x5a5a
+	candidates := phashSegmentCandidates(value, 3)
+	if len(candidates) != 697 {
+		t.Errorf("candidates: got %d want 697", len(candidates))
+	}
+
+	seen := make(map[int]bool)
+	for _, c := range candidates {
+		if seen[c] {
+			t.Errorf("duplicate candidate %x", c)
+		}
+		seen[c] = true
+
+		if d := bits.OnesCount16(uint16(c ^ value)); d > 3 {
+			t.Errorf("candidate %x at distance %d", c, d)
+		}
+	}
+}
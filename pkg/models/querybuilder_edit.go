@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx"
@@ -62,6 +63,54 @@ func (qb *EditQueryBuilder) FindTagID(id uuid.UUID) (*uuid.UUID, error) {
 	return &joins[0].TagID, nil
 }
 
+func (qb *EditQueryBuilder) CreateEditPerformer(newJoin EditPerformer) error {
+	return qb.dbi.InsertJoin(editPerformerTable, newJoin, false)
+}
+
+func (qb *EditQueryBuilder) FindPerformerID(id uuid.UUID) (*uuid.UUID, error) {
+	joins := EditPerformers{}
+	err := qb.dbi.FindJoins(editPerformerTable, id, &joins)
+	if err != nil {
+		return nil, err
+	}
+	if len(joins) == 0 {
+		return nil, errors.New("performer edit not found")
+	}
+	return &joins[0].PerformerID, nil
+}
+
+func (qb *EditQueryBuilder) CreateEditScene(newJoin EditScene) error {
+	return qb.dbi.InsertJoin(editSceneTable, newJoin, false)
+}
+
+func (qb *EditQueryBuilder) FindSceneID(id uuid.UUID) (*uuid.UUID, error) {
+	joins := EditScenes{}
+	err := qb.dbi.FindJoins(editSceneTable, id, &joins)
+	if err != nil {
+		return nil, err
+	}
+	if len(joins) == 0 {
+		return nil, errors.New("scene edit not found")
+	}
+	return &joins[0].SceneID, nil
+}
+
+func (qb *EditQueryBuilder) CreateEditStudio(newJoin EditStudio) error {
+	return qb.dbi.InsertJoin(editStudioTable, newJoin, false)
+}
+
+func (qb *EditQueryBuilder) FindStudioID(id uuid.UUID) (*uuid.UUID, error) {
+	joins := EditStudios{}
+	err := qb.dbi.FindJoins(editStudioTable, id, &joins)
+	if err != nil {
+		return nil, err
+	}
+	if len(joins) == 0 {
+		return nil, errors.New("studio edit not found")
+	}
+	return &joins[0].StudioID, nil
+}
+
 // func (qb *SceneQueryBuilder) FindByStudioID(sceneID int) ([]*Scene, error) {
 // 	query := `
 // 		SELECT scenes.* FROM scenes
@@ -128,6 +177,21 @@ func (qb *EditQueryBuilder) Query(editFilter *EditFilterType, findFilter *QueryS
 			query.AddWhere(editTagTable.Name() + ".tag_id = ? OR " + editDBTable.Name() + ".data->'merge_sources' @> ?")
 			jsonID, _ := json.Marshal(*q)
 			query.AddArg(*q, jsonID)
+		} else if *editFilter.TargetType == "PERFORMER" {
+			query.AddJoin(editPerformerTable.Table, editPerformerTable.Name()+".edit_id = edits.id")
+			query.AddWhere(editPerformerTable.Name() + ".performer_id = ? OR " + editDBTable.Name() + ".data->'merge_sources' @> ?")
+			jsonID, _ := json.Marshal(*q)
+			query.AddArg(*q, jsonID)
+		} else if *editFilter.TargetType == "SCENE" {
+			query.AddJoin(editSceneTable.Table, editSceneTable.Name()+".edit_id = edits.id")
+			query.AddWhere(editSceneTable.Name() + ".scene_id = ? OR " + editDBTable.Name() + ".data->'merge_sources' @> ?")
+			jsonID, _ := json.Marshal(*q)
+			query.AddArg(*q, jsonID)
+		} else if *editFilter.TargetType == "STUDIO" {
+			query.AddJoin(editStudioTable.Table, editStudioTable.Name()+".edit_id = edits.id")
+			query.AddWhere(editStudioTable.Name() + ".studio_id = ? OR " + editDBTable.Name() + ".data->'merge_sources' @> ?")
+			jsonID, _ := json.Marshal(*q)
+			query.AddArg(*q, jsonID)
 		} else {
 			panic("TargetType is not yet supported: " + *editFilter.TargetType)
 		}
@@ -181,6 +245,25 @@ func (qb *EditQueryBuilder) CreateComment(newJoin EditComment) error {
 	return qb.dbi.InsertJoin(editCommentTable, newJoin, false)
 }
 
+func (qb *EditQueryBuilder) FindComment(id uuid.UUID) (*EditComment, error) {
+	query := "SELECT * FROM " + editCommentTable.Name() + " WHERE id = ?"
+	args := []interface{}{id}
+	output := EditComments{}
+	if err := qb.dbi.RawQuery(editCommentTable.Table, query, args, &output); err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return nil, nil
+	}
+	return output[0], nil
+}
+
+func (qb *EditQueryBuilder) UpdateComment(updatedComment EditComment) error {
+	query := "UPDATE " + editCommentTable.Name() + " SET text = ?, edited_at = ?, hidden = ? WHERE id = ?"
+	args := []interface{}{updatedComment.Text, updatedComment.EditedAt, updatedComment.Hidden, updatedComment.ID}
+	return qb.dbi.RawQuery(editCommentTable.Table, query, args, nil)
+}
+
 func (qb *EditQueryBuilder) GetComments(id uuid.UUID) (EditComments, error) {
 	joins := EditComments{}
 	err := qb.dbi.FindJoins(editCommentTable, id, &joins)
@@ -188,6 +271,114 @@ func (qb *EditQueryBuilder) GetComments(id uuid.UUID) (EditComments, error) {
 	return joins, err
 }
 
+// CreateVote adds a vote to the edit, replacing any existing vote by the
+// same user.
+func (qb *EditQueryBuilder) CreateVote(newJoin EditVote) error {
+	query := "DELETE FROM " + editVoteTable.Name() + " WHERE edit_id = ? AND user_id = ?"
+	args := []interface{}{newJoin.EditID, newJoin.UserID}
+	if err := qb.dbi.RawQuery(editVoteTable.Table, query, args, nil); err != nil {
+		return err
+	}
+
+	return qb.dbi.InsertJoin(editVoteTable, newJoin, false)
+}
+
+func (qb *EditQueryBuilder) GetVotes(id uuid.UUID) (EditVotes, error) {
+	joins := EditVotes{}
+	err := qb.dbi.FindJoins(editVoteTable, id, &joins)
+
+	return joins, err
+}
+
+// OutdateVotes flags the existing votes on the edit as outdated, so that
+// they no longer count towards the vote count.
+func (qb *EditQueryBuilder) OutdateVotes(id uuid.UUID) error {
+	query := "UPDATE " + editVoteTable.Name() + " SET outdated = TRUE WHERE edit_id = ?"
+	args := []interface{}{id}
+	return qb.dbi.RawQuery(editVoteTable.Table, query, args, nil)
+}
+
+// UpdateVoteCount sets the vote count of the edit. The count is written
+// explicitly since a count of zero is skipped by Update.
+func (qb *EditQueryBuilder) UpdateVoteCount(id uuid.UUID, votes int) error {
+	query := "UPDATE " + editDBTable.Name() + " SET votes = ? WHERE id = ?"
+	args := []interface{}{votes, id}
+	return qb.dbi.RawQuery(editDBTable, query, args, nil)
+}
+
+func (qb *EditQueryBuilder) CreateRevision(newJoin EditRevision) error {
+	return qb.dbi.InsertJoin(editRevisionTable, newJoin, false)
+}
+
+func (qb *EditQueryBuilder) GetRevisions(id uuid.UUID) (EditRevisions, error) {
+	joins := EditRevisions{}
+	err := qb.dbi.FindJoins(editRevisionTable, id, &joins)
+
+	return joins, err
+}
+
+// FindRevertOf returns the edit reverting the edit with the provided id, or
+// nil if the edit has not been reverted.
+func (qb *EditQueryBuilder) FindRevertOf(id uuid.UUID) (*Edit, error) {
+	query := `
+        SELECT edits.* FROM edits
+        WHERE edits.reverts_id = ?`
+	args := []interface{}{id}
+	edits, err := qb.queryEdits(query, args)
+	if err != nil || len(edits) == 0 {
+		return nil, err
+	}
+	return edits[0], nil
+}
+
+func (qb *EditQueryBuilder) CreateGroup(newGroup EditGroup) (*EditGroup, error) {
+	ret, err := qb.dbi.Insert(newGroup)
+	if ret == nil {
+		return nil, err
+	}
+	return ret.(*EditGroup), err
+}
+
+func (qb *EditQueryBuilder) FindGroup(id uuid.UUID) (*EditGroup, error) {
+	ret, err := qb.dbi.Find(id, editGroupDBTable)
+	if ret == nil {
+		return nil, err
+	}
+	return ret.(*EditGroup), err
+}
+
+// AddToGroup adds the edit to the edit group and resets its vote count.
+func (qb *EditQueryBuilder) AddToGroup(id uuid.UUID, groupID uuid.UUID) error {
+	query := "UPDATE " + editDBTable.Name() + " SET group_id = ?, votes = 0 WHERE id = ?"
+	args := []interface{}{groupID, id}
+	return qb.dbi.RawQuery(editDBTable, query, args, nil)
+}
+
+// FindByGroupID returns the edits of the edit group, in order of creation.
+func (qb *EditQueryBuilder) FindByGroupID(id uuid.UUID) ([]*Edit, error) {
+	query := `
+        SELECT edits.* FROM edits
+        WHERE edits.group_id = ?
+        ORDER BY edits.created_at, edits.id`
+	args := []interface{}{id}
+	return qb.queryEdits(query, args)
+}
+
+// FindPendingBefore returns the pending edits created before the provided
+// time, oldest first. Grouped edits are returned once their group was created
+// before the provided time, in order of creation within the group.
+func (qb *EditQueryBuilder) FindPendingBefore(before time.Time) ([]*Edit, error) {
+	query := `
+        SELECT edits.* FROM edits
+        LEFT JOIN edit_groups
+        ON edit_groups.id = edits.group_id
+        WHERE edits.status = ?
+        AND COALESCE(edit_groups.created_at, edits.created_at) < ?
+        ORDER BY COALESCE(edit_groups.created_at, edits.created_at), edits.created_at, edits.id`
+	args := []interface{}{VoteStatusEnumPending.String(), before}
+	return qb.queryEdits(query, args)
+}
+
 func (qb *EditQueryBuilder) FindByTagID(id uuid.UUID) ([]*Edit, error) {
 	query := `
         SELECT edits.* FROM edits
@@ -197,3 +388,55 @@ func (qb *EditQueryBuilder) FindByTagID(id uuid.UUID) ([]*Edit, error) {
 	args := []interface{}{id}
 	return qb.queryEdits(query, args)
 }
+
+func (qb *EditQueryBuilder) FindByPerformerID(id uuid.UUID) ([]*Edit, error) {
+	query := `
+        SELECT edits.* FROM edits
+        JOIN performer_edits
+        ON performer_edits.edit_id = edits.id
+        WHERE performer_edits.performer_id = ?`
+	args := []interface{}{id}
+	return qb.queryEdits(query, args)
+}
+
+func (qb *EditQueryBuilder) FindBySceneID(id uuid.UUID) ([]*Edit, error) {
+	query := `
+        SELECT edits.* FROM edits
+        JOIN scene_edits
+        ON scene_edits.edit_id = edits.id
+        WHERE scene_edits.scene_id = ?`
+	args := []interface{}{id}
+	return qb.queryEdits(query, args)
+}
+
+func (qb *EditQueryBuilder) FindByStudioID(id uuid.UUID) ([]*Edit, error) {
+	query := `
+        SELECT edits.* FROM edits
+        JOIN studio_edits
+        ON studio_edits.edit_id = edits.id
+        WHERE studio_edits.studio_id = ?`
+	args := []interface{}{id}
+	return qb.queryEdits(query, args)
+}
+
+// FindAppliedByPerformerID returns the edits targeting the performer, or
+// merging it into another performer, that were applied at or after the
+// provided time, most recently applied first.
+func (qb *EditQueryBuilder) FindAppliedByPerformerID(id uuid.UUID, since time.Time) ([]*Edit, error) {
+	query := `
+        SELECT edits.* FROM edits
+        WHERE (
+            EXISTS (
+                SELECT 1 FROM performer_edits
+                WHERE performer_edits.edit_id = edits.id
+                AND performer_edits.performer_id = ?
+            )
+            OR edits.data->'merge_sources' @> ?
+        )
+        AND edits.applied = TRUE
+        AND edits.updated_at >= ?
+        ORDER BY edits.updated_at DESC`
+	jsonID, _ := json.Marshal(id.String())
+	args := []interface{}{id, jsonID, since}
+	return qb.queryEdits(query, args)
+}
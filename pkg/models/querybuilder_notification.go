@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/stashapp/stashdb/pkg/database"
+)
+
+type NotificationQueryBuilder struct {
+	dbi database.DBI
+}
+
+func NewNotificationQueryBuilder(tx *sqlx.Tx) NotificationQueryBuilder {
+	return NotificationQueryBuilder{
+		dbi: database.DBIWithTxn(tx),
+	}
+}
+
+func (qb *NotificationQueryBuilder) Create(newNotification Notification) error {
+	_, err := qb.dbi.Insert(newNotification)
+	return err
+}
+
+func (qb *NotificationQueryBuilder) queryNotifications(query string, args []interface{}) (Notifications, error) {
+	output := Notifications{}
+	err := qb.dbi.RawQuery(notificationDBTable, query, args, &output)
+	return output, err
+}
+
+// FindByUserID returns the notifications of the user, most recent first.
+func (qb *NotificationQueryBuilder) FindByUserID(userID uuid.UUID, unreadOnly bool) (Notifications, error) {
+	query := `
+        SELECT notifications.* FROM notifications
+        WHERE notifications.user_id = ?`
+	if unreadOnly {
+		query += " AND notifications.read_at IS NULL"
+	}
+	query += " ORDER BY notifications.created_at DESC"
+	args := []interface{}{userID}
+	return qb.queryNotifications(query, args)
+}
+
+// FindUnreadNotEmailed returns the unread notifications that have not yet
+// been sent in an email digest, ordered by user.
+func (qb *NotificationQueryBuilder) FindUnreadNotEmailed() (Notifications, error) {
+	query := `
+        SELECT notifications.* FROM notifications
+        WHERE notifications.read_at IS NULL AND notifications.emailed = FALSE
+        ORDER BY notifications.user_id, notifications.created_at`
+	return qb.queryNotifications(query, nil)
+}
+
+// MarkRead marks the notifications of the user with the provided ids as
+// read. All unread notifications of the user are marked if no ids are
+// provided.
+func (qb *NotificationQueryBuilder) MarkRead(userID uuid.UUID, ids []uuid.UUID) error {
+	query := "UPDATE " + notificationDBTable.Name() + " SET read_at = ? WHERE user_id = ? AND read_at IS NULL"
+	args := []interface{}{time.Now(), userID}
+	if len(ids) > 0 {
+		query += " AND id IN " + getInBinding(len(ids))
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	}
+	return qb.dbi.RawQuery(notificationDBTable, query, args, nil)
+}
+
+func (qb *NotificationQueryBuilder) MarkEmailed(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := "UPDATE " + notificationDBTable.Name() + " SET emailed = TRUE WHERE id IN " + getInBinding(len(ids))
+	var args []interface{}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	return qb.dbi.RawQuery(notificationDBTable, query, args, nil)
+}
+
+// CreateSubscription subscribes the user to the target. Existing
+// subscriptions are left unchanged.
+func (qb *NotificationQueryBuilder) CreateSubscription(newJoin NotificationSubscription) error {
+	return qb.dbi.InsertJoin(notificationSubscriptionTable, newJoin, true)
+}
+
+func (qb *NotificationQueryBuilder) DestroySubscription(userID uuid.UUID, targetID uuid.UUID) error {
+	query := "DELETE FROM " + notificationSubscriptionTable.Name() + " WHERE user_id = ? AND target_id = ?"
+	args := []interface{}{userID, targetID}
+	return qb.dbi.RawQuery(notificationSubscriptionTable.Table, query, args, nil)
+}
+
+func (qb *NotificationQueryBuilder) GetSubscriptions(userID uuid.UUID) (NotificationSubscriptions, error) {
+	joins := NotificationSubscriptions{}
+	err := qb.dbi.FindJoins(notificationSubscriptionTable, userID, &joins)
+
+	return joins, err
+}
+
+// FindSubscribers returns the subscriptions to any of the targets.
+func (qb *NotificationQueryBuilder) FindSubscribers(targetIDs []uuid.UUID) (NotificationSubscriptions, error) {
+	output := NotificationSubscriptions{}
+	if len(targetIDs) == 0 {
+		return output, nil
+	}
+
+	query := "SELECT * FROM " + notificationSubscriptionTable.Name() + " WHERE target_id IN " + getInBinding(len(targetIDs))
+	var args []interface{}
+	for _, id := range targetIDs {
+		args = append(args, id)
+	}
+	err := qb.dbi.RawQuery(notificationSubscriptionTable.Table, query, args, &output)
+	return output, err
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"strconv"
 	"time"
 
@@ -43,6 +44,48 @@ func (qb *PerformerQueryBuilder) Destroy(id uuid.UUID) error {
 	return qb.dbi.Delete(id, performerDBTable)
 }
 
+func (qb *PerformerQueryBuilder) SoftDelete(performer Performer) (*Performer, error) {
+	// Delete performer aliases
+	if err := qb.dbi.DeleteJoins(performerAliasTable, performer.ID); err != nil {
+		return nil, err
+	}
+	ret, err := qb.dbi.SoftDelete(performer)
+	return qb.toModel(ret), err
+}
+
+func (qb *PerformerQueryBuilder) DeleteScenePerformers(id uuid.UUID) error {
+	// Delete scene_performers joins
+	return qb.dbi.DeleteJoins(performerSceneTable, id)
+}
+
+func (qb *PerformerQueryBuilder) CreateRedirect(newJoin PerformerRedirect) error {
+	return qb.dbi.InsertJoin(performerRedirectTable, newJoin, false)
+}
+
+func (qb *PerformerQueryBuilder) UpdateRedirects(oldTargetID uuid.UUID, newTargetID uuid.UUID) error {
+	query := "UPDATE " + performerRedirectTable.Table.Name() + " SET target_id = ? WHERE target_id = ?"
+	args := []interface{}{newTargetID, oldTargetID}
+	return qb.dbi.RawQuery(performerRedirectTable.Table, query, args, nil)
+}
+
+func (qb *PerformerQueryBuilder) UpdateScenePerformers(oldPerformerID uuid.UUID, newPerformerID uuid.UUID) error {
+	// Insert new joins for any scenes that have the old performer
+	query := `INSERT INTO scene_performers (scene_id, "as", performer_id)
+            SELECT scene_id, "as", ?
+            FROM scene_performers WHERE performer_id = ?
+            ON CONFLICT DO NOTHING`
+	args := []interface{}{newPerformerID, oldPerformerID}
+	err := qb.dbi.RawQuery(scenePerformerTable.Table, query, args, nil)
+	if err != nil {
+		return err
+	}
+
+	// Delete any joins with the old performer
+	query = `DELETE FROM scene_performers WHERE performer_id = ?`
+	args = []interface{}{oldPerformerID}
+	return qb.dbi.RawQuery(scenePerformerTable.Table, query, args, nil)
+}
+
 func (qb *PerformerQueryBuilder) CreateAliases(newJoins PerformerAliases) error {
 	return qb.dbi.InsertJoins(performerAliasTable, &newJoins)
 }
@@ -75,6 +118,14 @@ func (qb *PerformerQueryBuilder) UpdatePiercings(performerID uuid.UUID, updatedJ
 	return qb.dbi.ReplaceJoins(performerPiercingTable, performerID, &updatedJoins)
 }
 
+func (qb *PerformerQueryBuilder) CreateImages(newJoins PerformerImages) error {
+	return qb.dbi.InsertJoins(performerImageTable, &newJoins)
+}
+
+func (qb *PerformerQueryBuilder) UpdateImages(performerID uuid.UUID, updatedJoins PerformerImages) error {
+	return qb.dbi.ReplaceJoins(performerImageTable, performerID, &updatedJoins)
+}
+
 func (qb *PerformerQueryBuilder) Find(id uuid.UUID) (*Performer, error) {
 	ret, err := qb.dbi.Find(id, performerDBTable)
 	return qb.toModel(ret), err
@@ -153,13 +204,13 @@ func (qb *PerformerQueryBuilder) Count() (int, error) {
 	return runCountQuery(buildCountQuery("SELECT performers.id FROM performers"), nil)
 }
 
-func (qb *PerformerQueryBuilder) Query(performerFilter *PerformerFilterType, findFilter *QuerySpec) ([]*Performer, int) {
+// buildFilterQuery returns a query builder for the performers matching the
+// filter. The query lists and facet counts share it, so that the counts
+// always match the filtered list.
+func (qb *PerformerQueryBuilder) buildFilterQuery(performerFilter *PerformerFilterType) *database.QueryBuilder {
 	if performerFilter == nil {
 		performerFilter = &PerformerFilterType{}
 	}
-	if findFilter == nil {
-		findFilter = &QuerySpec{}
-	}
 
 	query := database.NewQueryBuilder(performerDBTable)
 
@@ -193,6 +244,15 @@ func (qb *PerformerQueryBuilder) Query(performerFilter *PerformerFilterType, fin
 	//handleStringCriterion("piercings", performerFilter.Piercings, &query)
 	//handleStringCriterion("aliases", performerFilter.Aliases, &query)
 
+	return query
+}
+
+func (qb *PerformerQueryBuilder) Query(performerFilter *PerformerFilterType, findFilter *QuerySpec) ([]*Performer, int) {
+	if findFilter == nil {
+		findFilter = &QuerySpec{}
+	}
+
+	query := qb.buildFilterQuery(performerFilter)
 	query.SortAndPagination = qb.getPerformerSort(findFilter) + getPagination(findFilter)
 	var performers Performers
 	countResult, err := qb.dbi.Query(*query, &performers)
@@ -205,6 +265,74 @@ func (qb *PerformerQueryBuilder) Query(performerFilter *PerformerFilterType, fin
 	return performers, countResult
 }
 
+// GetGenderFacets returns the number of performers matching the filter for
+// each gender.
+func (qb *PerformerQueryBuilder) GetGenderFacets(performerFilter *PerformerFilterType) ([]*GenderFacet, error) {
+	query := qb.buildFilterQuery(performerFilter)
+	counts, err := qb.dbi.Facet(*query, "filtered.gender", "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*GenderFacet
+	for _, count := range counts {
+		gender := GenderEnum(count.Value)
+		if !gender.IsValid() {
+			continue
+		}
+		ret = append(ret, &GenderFacet{
+			Gender: gender,
+			Count:  count.Count,
+		})
+	}
+
+	return ret, nil
+}
+
+// GetEthnicityFacets returns the number of performers matching the filter
+// for each ethnicity.
+func (qb *PerformerQueryBuilder) GetEthnicityFacets(performerFilter *PerformerFilterType) ([]*EthnicityFacet, error) {
+	query := qb.buildFilterQuery(performerFilter)
+	counts, err := qb.dbi.Facet(*query, "filtered.ethnicity", "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*EthnicityFacet
+	for _, count := range counts {
+		ethnicity := EthnicityEnum(count.Value)
+		if !ethnicity.IsValid() {
+			continue
+		}
+		ret = append(ret, &EthnicityFacet{
+			Ethnicity: ethnicity,
+			Count:     count.Count,
+		})
+	}
+
+	return ret, nil
+}
+
+// GetCountryFacets returns the number of performers matching the filter for
+// each country, limited to the limit most frequent countries.
+func (qb *PerformerQueryBuilder) GetCountryFacets(performerFilter *PerformerFilterType, limit int) ([]*CountryFacet, error) {
+	query := qb.buildFilterQuery(performerFilter)
+	counts, err := qb.dbi.Facet(*query, "filtered.country", "", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*CountryFacet
+	for _, count := range counts {
+		ret = append(ret, &CountryFacet{
+			Country: count.Value,
+			Count:   count.Count,
+		})
+	}
+
+	return ret, nil
+}
+
 func getBirthYearFilterClause(criterionModifier CriterionModifier, value int) ([]string, []interface{}) {
 	var clauses []string
 	var args []interface{}
@@ -295,10 +423,15 @@ func (qb *PerformerQueryBuilder) queryPerformers(query string, args []interface{
 	return output, err
 }
 
-func (qb *PerformerQueryBuilder) GetAliases(id uuid.UUID) ([]string, error) {
+func (qb *PerformerQueryBuilder) GetRawAliases(id uuid.UUID) (PerformerAliases, error) {
 	joins := PerformerAliases{}
 	err := qb.dbi.FindJoins(performerAliasTable, id, &joins)
 
+	return joins, err
+}
+
+func (qb *PerformerQueryBuilder) GetAliases(id uuid.UUID) ([]string, error) {
+	joins, err := qb.GetRawAliases(id)
 	return joins.ToAliases(), err
 }
 
@@ -419,13 +552,369 @@ func (qb *PerformerQueryBuilder) GetAllPiercings(ids []uuid.UUID) ([][]*BodyModi
 	return result, nil
 }
 
-func (qb *PerformerQueryBuilder) SearchPerformers(term string) (Performers, error) {
+func (qb *PerformerQueryBuilder) GetImages(id uuid.UUID) (PerformerImages, error) {
+	joins := PerformerImages{}
+	err := qb.dbi.FindJoins(performerImageTable, id, &joins)
+
+	return joins, err
+}
+
+// SearchPerformers returns the performers whose name, alias, or name and
+// disambiguation are most similar to the term, along with the best matching
+// value of each performer.
+func (qb *PerformerQueryBuilder) SearchPerformers(term string) ([]*PerformerSearchResult, error) {
+	// each performer is returned once, with the most similar of its values,
+	// preferring names over aliases over disambiguations of equal similarity
 	query := `
-        SELECT * FROM performers
-        WHERE name % $1
-        AND similarity(name, $1) > 0.5
-        ORDER BY similarity(name, $1) DESC
+        SELECT performers.*, matches.score, matches.matched_field, matches.matched_value
+        FROM (
+            SELECT DISTINCT ON (id) id, score, matched_field, matched_value
+            FROM (
+                SELECT id, similarity(name, $1) AS score, 1 AS priority,
+                    'NAME' AS matched_field, name AS matched_value
+                FROM performers
+                WHERE name % $1
+                UNION ALL
+                SELECT performer_id, similarity(alias, $1), 2, 'ALIAS', alias
+                FROM performer_aliases
+                WHERE alias % $1
+                UNION ALL
+                SELECT id, similarity(name || ' ' || disambiguation, $1), 3,
+                    'DISAMBIGUATION', name || ' ' || disambiguation
+                FROM performers
+                WHERE disambiguation IS NOT NULL
+                AND (name || ' ' || disambiguation) % $1
+            ) candidates
+            ORDER BY id, score DESC, priority
+        ) matches
+        JOIN performers ON performers.id = matches.id
+        WHERE matches.score > 0.5 AND NOT performers.deleted
+        ORDER BY matches.score DESC, performers.name
         LIMIT 5`
 	args := []interface{}{term}
-	return qb.queryPerformers(query, args)
+
+	output := performerSearchMatches{}
+	if err := qb.dbi.RawQuery(performerSearchMatchTable, query, args, &output); err != nil {
+		return nil, err
+	}
+
+	var ret []*PerformerSearchResult
+	for _, match := range output {
+		performer := match.Performer
+		ret = append(ret, &PerformerSearchResult{
+			Performer:    &performer,
+			Score:        match.Score,
+			MatchedField: PerformerSearchField(match.MatchedField),
+			MatchedValue: match.MatchedValue,
+		})
+	}
+
+	return ret, nil
+}
+
+func (qb *PerformerQueryBuilder) MergeInto(sourceID uuid.UUID, targetID uuid.UUID) error {
+	performer, err := qb.Find(sourceID)
+	if err != nil {
+		return err
+	}
+	if performer == nil {
+		return errors.New("Merge source performer not found: " + sourceID.String())
+	}
+	if performer.Deleted {
+		return errors.New("Merge source performer is deleted: " + sourceID.String())
+	}
+	_, err = qb.SoftDelete(*performer)
+	if err != nil {
+		return err
+	}
+	if err := qb.UpdateRedirects(sourceID, targetID); err != nil {
+		return err
+	}
+	if err := qb.UpdateScenePerformers(sourceID, targetID); err != nil {
+		return err
+	}
+	redirect := PerformerRedirect{SourceID: sourceID, TargetID: targetID}
+	return qb.CreateRedirect(redirect)
+}
+
+func (qb *PerformerQueryBuilder) ApplyEdit(edit Edit, operation OperationEnum, performer *Performer) (*Performer, error) {
+	data, err := edit.GetPerformerData()
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation {
+	case OperationEnumCreate:
+		now := time.Now()
+		UUID, err := uuid.NewV4()
+		if err != nil {
+			return nil, err
+		}
+		newPerformer := Performer{
+			ID:        UUID,
+			CreatedAt: SQLiteTimestamp{Timestamp: now},
+		}
+		if data.New.Name == nil {
+			return nil, errors.New("Missing performer name")
+		}
+		newPerformer.CopyFromPerformerEdit(*data.New)
+
+		performer, err = qb.Create(newPerformer)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := qb.applyEditJoins(performer.ID, *data.New); err != nil {
+			return nil, err
+		}
+
+		return performer, nil
+	case OperationEnumDestroy:
+		updatedPerformer, err := qb.SoftDelete(*performer)
+		if err != nil {
+			return nil, err
+		}
+		err = qb.DeleteScenePerformers(performer.ID)
+		return updatedPerformer, err
+	case OperationEnumModify:
+		if err := performer.ValidateModifyEdit(*data); err != nil {
+			return nil, err
+		}
+
+		performer.CopyFromPerformerEdit(*data.New)
+		updatedPerformer, err := qb.Update(*performer)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := qb.applyEditJoins(updatedPerformer.ID, *data.New); err != nil {
+			return nil, err
+		}
+
+		return updatedPerformer, nil
+	case OperationEnumMerge:
+		if err := performer.ValidateModifyEdit(*data); err != nil {
+			return nil, err
+		}
+
+		performer.CopyFromPerformerEdit(*data.New)
+		updatedPerformer, err := qb.Update(*performer)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range data.MergeSources {
+			sourceUUID, _ := uuid.FromString(v)
+			if err := qb.MergeInto(sourceUUID, performer.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := qb.applyEditJoins(updatedPerformer.ID, *data.New); err != nil {
+			return nil, err
+		}
+
+		return updatedPerformer, nil
+	default:
+		return nil, errors.New("Unsupported operation: " + operation.String())
+	}
+}
+
+// applyEditJoins adds and removes the aliases, urls, tattoos, piercings and
+// images specified in the edit data to/from the performer.
+func (qb *PerformerQueryBuilder) applyEditJoins(performerID uuid.UUID, data PerformerEdit) error {
+	currentAliases, err := qb.GetRawAliases(performerID)
+	if err != nil {
+		return err
+	}
+	if err := currentAliases.AddAliases(CreatePerformerAliases(performerID, data.AddedAliases)); err != nil {
+		return err
+	}
+	if err := currentAliases.RemoveAliases(data.RemovedAliases); err != nil {
+		return err
+	}
+	if err := qb.UpdateAliases(performerID, currentAliases); err != nil {
+		return err
+	}
+
+	currentUrls, err := qb.GetUrls(performerID)
+	if err != nil {
+		return err
+	}
+	if err := currentUrls.AddUrls(CreatePerformerEditUrls(performerID, data.AddedUrls)); err != nil {
+		return err
+	}
+	if err := currentUrls.RemoveUrls(data.RemovedUrls); err != nil {
+		return err
+	}
+	if err := qb.UpdateUrls(performerID, currentUrls); err != nil {
+		return err
+	}
+
+	currentTattoos, err := qb.GetTattoos(performerID)
+	if err != nil {
+		return err
+	}
+	if err := currentTattoos.AddBodyMods(CreatePerformerEditBodyMods(performerID, data.AddedTattoos)); err != nil {
+		return err
+	}
+	if err := currentTattoos.RemoveBodyMods(data.RemovedTattoos); err != nil {
+		return err
+	}
+	if err := qb.UpdateTattoos(performerID, currentTattoos); err != nil {
+		return err
+	}
+
+	currentPiercings, err := qb.GetPiercings(performerID)
+	if err != nil {
+		return err
+	}
+	if err := currentPiercings.AddBodyMods(CreatePerformerEditBodyMods(performerID, data.AddedPiercings)); err != nil {
+		return err
+	}
+	if err := currentPiercings.RemoveBodyMods(data.RemovedPiercings); err != nil {
+		return err
+	}
+	if err := qb.UpdatePiercings(performerID, currentPiercings); err != nil {
+		return err
+	}
+
+	currentImages, err := qb.GetImages(performerID)
+	if err != nil {
+		return err
+	}
+	if err := currentImages.AddImages(CreatePerformerImages(performerID, data.AddedImages)); err != nil {
+		return err
+	}
+	if err := currentImages.RemoveImages(data.RemovedImages); err != nil {
+		return err
+	}
+	return qb.UpdateImages(performerID, currentImages)
+}
+
+func (qb *PerformerQueryBuilder) getSnapshot(id uuid.UUID) (*PerformerSnapshot, error) {
+	performer, err := qb.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if performer == nil {
+		return nil, errors.New("Performer not found: " + id.String())
+	}
+
+	ret := &PerformerSnapshot{Performer: *performer}
+	if ret.Aliases, err = qb.GetRawAliases(id); err != nil {
+		return nil, err
+	}
+	if ret.Urls, err = qb.GetUrls(id); err != nil {
+		return nil, err
+	}
+	if ret.Tattoos, err = qb.GetTattoos(id); err != nil {
+		return nil, err
+	}
+	if ret.Piercings, err = qb.GetPiercings(id); err != nil {
+		return nil, err
+	}
+	if ret.Images, err = qb.GetImages(id); err != nil {
+		return nil, err
+	}
+	if err := qb.dbi.FindJoins(performerSceneTable, id, &ret.ScenePerformers); err != nil {
+		return nil, err
+	}
+	if ret.RedirectSourceIDs, err = findRedirectSourceIDs(qb.dbi, performerRedirectTable, id); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// GetEditSnapshot returns the current state of the performers affected by
+// the edit, so that the edit can be reverted once applied.
+func (qb *PerformerQueryBuilder) GetEditSnapshot(edit Edit, performer *Performer) (*PerformerEditSnapshot, error) {
+	data, err := edit.GetPerformerData()
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := qb.getSnapshot(performer.ID)
+	if err != nil {
+		return nil, err
+	}
+	ret := &PerformerEditSnapshot{Target: target}
+
+	for _, v := range data.MergeSources {
+		sourceUUID, _ := uuid.FromString(v)
+		source, err := qb.getSnapshot(sourceUUID)
+		if err != nil {
+			return nil, err
+		}
+		ret.MergeSources = append(ret.MergeSources, source)
+	}
+
+	return ret, nil
+}
+
+func (qb *PerformerQueryBuilder) restoreSnapshot(snapshot PerformerSnapshot) error {
+	performer := snapshot.Performer
+	performer.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
+	if _, err := qb.dbi.Update(performer, true); err != nil {
+		return err
+	}
+	if err := qb.UpdateAliases(performer.ID, snapshot.Aliases); err != nil {
+		return err
+	}
+	if err := qb.UpdateUrls(performer.ID, snapshot.Urls); err != nil {
+		return err
+	}
+	if err := qb.UpdateTattoos(performer.ID, snapshot.Tattoos); err != nil {
+		return err
+	}
+	if err := qb.UpdatePiercings(performer.ID, snapshot.Piercings); err != nil {
+		return err
+	}
+	if err := qb.UpdateImages(performer.ID, snapshot.Images); err != nil {
+		return err
+	}
+	return restoreRedirects(qb.dbi, performerRedirectTable, performer.ID, snapshot.RedirectSourceIDs)
+}
+
+// RevertEdit undoes the changes of an applied edit to the performer,
+// restoring the performer and any merge sources from the snapshot stored
+// with the edit.
+func (qb *PerformerQueryBuilder) RevertEdit(edit Edit, operation OperationEnum, performer *Performer) error {
+	if operation == OperationEnumCreate {
+		if _, err := qb.SoftDelete(*performer); err != nil {
+			return err
+		}
+		return qb.DeleteScenePerformers(performer.ID)
+	}
+
+	var snapshot PerformerEditSnapshot
+	if err := edit.GetSnapshot(&snapshot); err != nil {
+		return err
+	}
+	if snapshot.Target == nil {
+		return errors.New("Edit snapshot does not contain the performer")
+	}
+
+	if err := qb.restoreSnapshot(*snapshot.Target); err != nil {
+		return err
+	}
+
+	if operation == OperationEnumDestroy {
+		return qb.dbi.InsertJoinsWithoutConflict(scenePerformerTable, &snapshot.Target.ScenePerformers)
+	}
+
+	for _, source := range snapshot.MergeSources {
+		if err := qb.restoreSnapshot(*source); err != nil {
+			return err
+		}
+		if err := qb.dbi.InsertJoinsWithoutConflict(scenePerformerTable, &source.ScenePerformers); err != nil {
+			return err
+		}
+		if err := deleteMergedSceneJoins(qb.dbi, scenePerformerTable, "performer_id", performer.ID, source.ScenePerformers.SceneIDs(), snapshot.Target.ScenePerformers.SceneIDs()); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/stashapp/stashdb/pkg/database"
+)
+
+type redirect interface {
+	GetSourceID() uuid.UUID
+}
+
+type redirectSourceIDs []uuid.UUID
+
+func (p *redirectSourceIDs) Add(o interface{}) {
+	*p = append(*p, o.(redirect).GetSourceID())
+}
+
+// findRedirectSourceIDs returns the ids of the objects that redirect to the
+// object with the provided id.
+func findRedirectSourceIDs(dbi database.DBI, redirectTable database.TableJoin, id uuid.UUID) ([]uuid.UUID, error) {
+	query := "SELECT * FROM " + redirectTable.Table.Name() + " WHERE target_id = ?"
+	args := []interface{}{id}
+	var output redirectSourceIDs
+	err := dbi.RawQuery(redirectTable.Table, query, args, &output)
+	return output, err
+}
+
+// restoreRedirects deletes the redirect from the object with the provided id
+// and points the redirects of the provided source ids back to the object.
+func restoreRedirects(dbi database.DBI, redirectTable database.TableJoin, id uuid.UUID, sourceIDs []uuid.UUID) error {
+	if err := dbi.DeleteJoins(redirectTable, id); err != nil {
+		return err
+	}
+
+	query := "UPDATE " + redirectTable.Table.Name() + " SET target_id = ? WHERE source_id = ?"
+	for _, sourceID := range sourceIDs {
+		args := []interface{}{id, sourceID}
+		if err := dbi.RawQuery(redirectTable.Table, query, args, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteMergedSceneJoins deletes the scene joins of the merge target that
+// were only created by moving the joins of a merge source. The scene id
+// slices are the scenes joined to the source and target before the merge.
+func deleteMergedSceneJoins(dbi database.DBI, joinTable database.TableJoin, targetColumn string, targetID uuid.UUID, sourceSceneIDs []uuid.UUID, targetSceneIDs []uuid.UUID) error {
+	targetScenes := map[uuid.UUID]bool{}
+	for _, id := range targetSceneIDs {
+		targetScenes[id] = true
+	}
+
+	query := "DELETE FROM " + joinTable.Table.Name() + " WHERE scene_id = ? AND " + targetColumn + " = ?"
+	for _, sceneID := range sourceSceneIDs {
+		if targetScenes[sceneID] {
+			continue
+		}
+		args := []interface{}{sceneID, targetID}
+		if err := dbi.RawQuery(joinTable.Table, query, args, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
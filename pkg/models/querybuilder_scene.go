@@ -1,7 +1,11 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx"
@@ -41,6 +45,75 @@ func (qb *SceneQueryBuilder) Destroy(id uuid.UUID) error {
 	return qb.dbi.Delete(id, sceneDBTable)
 }
 
+func (qb *SceneQueryBuilder) SoftDelete(scene Scene) (*Scene, error) {
+	// Delete scene fingerprints
+	if err := qb.dbi.DeleteJoins(sceneFingerprintTable, scene.ID); err != nil {
+		return nil, err
+	}
+	ret, err := qb.dbi.SoftDelete(scene)
+	return qb.toModel(ret), err
+}
+
+func (qb *SceneQueryBuilder) CreateRedirect(newJoin SceneRedirect) error {
+	return qb.dbi.InsertJoin(sceneRedirectTable, newJoin, false)
+}
+
+func (qb *SceneQueryBuilder) UpdateRedirects(oldTargetID uuid.UUID, newTargetID uuid.UUID) error {
+	query := "UPDATE " + sceneRedirectTable.Table.Name() + " SET target_id = ? WHERE target_id = ?"
+	args := []interface{}{newTargetID, oldTargetID}
+	return qb.dbi.RawQuery(sceneRedirectTable.Table, query, args, nil)
+}
+
+func (qb *SceneQueryBuilder) MoveFingerprints(oldSceneID uuid.UUID, newSceneID uuid.UUID) error {
+	// Insert fingerprints of the old scene for the new scene
+	query := `INSERT INTO scene_fingerprints (scene_id, hash, algorithm, duration, created_at, updated_at)
+            SELECT ?, hash, algorithm, duration, created_at, updated_at
+            FROM scene_fingerprints WHERE scene_id = ?
+            ON CONFLICT DO NOTHING`
+	args := []interface{}{newSceneID, oldSceneID}
+	err := qb.dbi.RawQuery(sceneFingerprintTable.Table, query, args, nil)
+	if err != nil {
+		return err
+	}
+
+	// Move the submissions of the fingerprints along with them
+	query = `INSERT INTO scene_fingerprint_submissions (scene_id, algorithm, hash, user_id, created_at)
+            SELECT ?, algorithm, hash, user_id, created_at
+            FROM scene_fingerprint_submissions WHERE scene_id = ?
+            ON CONFLICT DO NOTHING`
+	err = qb.dbi.RawQuery(sceneFingerprintSubmissionTable.Table, query, args, nil)
+	if err != nil {
+		return err
+	}
+
+	// and their reports
+	query = `INSERT INTO scene_fingerprint_reports (scene_id, algorithm, hash, user_id, reason, created_at)
+            SELECT ?, algorithm, hash, user_id, reason, created_at
+            FROM scene_fingerprint_reports WHERE scene_id = ?
+            ON CONFLICT DO NOTHING`
+	err = qb.dbi.RawQuery(sceneFingerprintReportTable.Table, query, args, nil)
+	if err != nil {
+		return err
+	}
+
+	query = `DELETE FROM scene_fingerprint_submissions WHERE scene_id = ?`
+	args = []interface{}{oldSceneID}
+	err = qb.dbi.RawQuery(sceneFingerprintSubmissionTable.Table, query, args, nil)
+	if err != nil {
+		return err
+	}
+
+	query = `DELETE FROM scene_fingerprint_reports WHERE scene_id = ?`
+	err = qb.dbi.RawQuery(sceneFingerprintReportTable.Table, query, args, nil)
+	if err != nil {
+		return err
+	}
+
+	// Delete the fingerprints of the old scene
+	query = `DELETE FROM scene_fingerprints WHERE scene_id = ?`
+	return qb.dbi.RawQuery(sceneFingerprintTable.Table, query, args, nil)
+}
+
 func (qb *SceneQueryBuilder) CreateUrls(newJoins SceneUrls) error {
 	return qb.dbi.InsertJoins(sceneUrlTable, &newJoins)
 }
@@ -53,8 +126,160 @@ func (qb *SceneQueryBuilder) CreateFingerprints(newJoins SceneFingerprints) erro
 	return qb.dbi.InsertJoinsWithoutConflict(sceneFingerprintTable, &newJoins)
 }
 
+// CreateFingerprintSubmission records the submission of a fingerprint by a
+// user, and updates the time of the latest submission of the fingerprint.
+// Submitting the same fingerprint again has no effect.
+func (qb *SceneQueryBuilder) CreateFingerprintSubmission(submission SceneFingerprintSubmission) error {
+	if err := qb.dbi.InsertJoin(sceneFingerprintSubmissionTable, submission, true); err != nil {
+		return err
+	}
+
+	query := `UPDATE scene_fingerprints SET updated_at = ?
+		WHERE scene_id = ? AND algorithm = ? AND hash = ?`
+	args := []interface{}{submission.CreatedAt, submission.SceneID, submission.Algorithm, submission.Hash}
+	return qb.dbi.RawQuery(sceneFingerprintTable.Table, query, args, nil)
+}
+
+// DestroyFingerprintSubmission retracts the submission of a fingerprint by a
+// user. The fingerprint is removed if it was submitted and no submissions
+// remain.
+func (qb *SceneQueryBuilder) DestroyFingerprintSubmission(submission SceneFingerprintSubmission) error {
+	// the statement sees the submissions as they were before the retraction,
+	// so other submissions are those of other users
+	query := `
+		WITH retracted AS (
+			DELETE FROM scene_fingerprint_submissions
+			WHERE scene_id = ? AND algorithm = ? AND hash = ? AND user_id = ?
+			RETURNING scene_id, algorithm, hash, user_id
+		)
+		DELETE FROM scene_fingerprints USING retracted
+		WHERE scene_fingerprints.scene_id = retracted.scene_id
+		AND scene_fingerprints.algorithm = retracted.algorithm
+		AND scene_fingerprints.hash = retracted.hash
+		AND NOT EXISTS (
+			SELECT 1 FROM scene_fingerprint_submissions AS submissions
+			WHERE submissions.scene_id = retracted.scene_id
+			AND submissions.algorithm = retracted.algorithm
+			AND submissions.hash = retracted.hash
+			AND submissions.user_id <> retracted.user_id
+		)`
+	args := []interface{}{submission.SceneID, submission.Algorithm, submission.Hash, submission.UserID}
+	return qb.dbi.RawQuery(sceneFingerprintTable.Table, query, args, nil)
+}
+
+// CreateFingerprintReport records the report of a fingerprint by a user.
+// Reporting the same fingerprint again replaces the reason of the report.
+func (qb *SceneQueryBuilder) CreateFingerprintReport(report SceneFingerprintReport) error {
+	query := `INSERT INTO scene_fingerprint_reports (scene_id, algorithm, hash, user_id, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (scene_id, algorithm, hash, user_id)
+		DO UPDATE SET reason = EXCLUDED.reason, created_at = EXCLUDED.created_at`
+	args := []interface{}{report.SceneID, report.Algorithm, report.Hash, report.UserID, report.Reason, report.CreatedAt}
+	return qb.dbi.RawQuery(sceneFingerprintReportTable.Table, query, args, nil)
+}
+
+// GetFingerprintReports returns the reports of a fingerprint of the scene,
+// the latest first.
+func (qb *SceneQueryBuilder) GetFingerprintReports(sceneID uuid.UUID, algorithm FingerprintAlgorithm, hash string) (SceneFingerprintReports, error) {
+	query := `SELECT * FROM scene_fingerprint_reports
+		WHERE scene_id = ? AND algorithm = ? AND hash = ?
+		ORDER BY created_at DESC`
+	args := []interface{}{sceneID, algorithm.String(), hash}
+
+	reports := SceneFingerprintReports{}
+	err := qb.dbi.RawQuery(sceneFingerprintReportTable.Table, query, args, &reports)
+	return reports, err
+}
+
+// QueryReportedFingerprints returns a page of the reported fingerprints, the
+// most reported first, along with the number of reported fingerprints.
+func (qb *SceneQueryBuilder) QueryReportedFingerprints(findFilter *QuerySpec) ([]*ReportedFingerprint, int, error) {
+	body := `
+		SELECT scene_fingerprints.*, ` + sceneFingerprintSubmissionCountQuery + ` AS submissions, reports.reports
+		FROM scene_fingerprints
+		JOIN (
+			SELECT scene_id, algorithm, hash, COUNT(*) AS reports, MAX(created_at) AS reported_at
+			FROM scene_fingerprint_reports
+			GROUP BY scene_id, algorithm, hash
+		) AS reports ON reports.scene_id = scene_fingerprints.scene_id
+		AND reports.algorithm = scene_fingerprints.algorithm
+		AND reports.hash = scene_fingerprints.hash`
+
+	count, err := runCountQuery(buildCountQuery(body), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := body + " ORDER BY reports.reports DESC, reports.reported_at DESC" + getPagination(findFilter)
+	output := sceneFingerprintReportCounts{}
+	if err := qb.dbi.RawQuery(sceneFingerprintReportCountTable, query, nil, &output); err != nil {
+		return nil, 0, err
+	}
+
+	var ret []*ReportedFingerprint
+	for _, join := range output {
+		fingerprint := join.ToFingerprint()
+		fingerprint.Submissions = join.Submissions
+		ret = append(ret, &ReportedFingerprint{
+			SceneID:     join.SceneID,
+			Fingerprint: fingerprint,
+			ReportCount: join.Reports,
+		})
+	}
+
+	return ret, count, nil
+}
+
+// DestroyFingerprint removes a fingerprint of the scene along with its
+// submissions and reports.
+func (qb *SceneQueryBuilder) DestroyFingerprint(sceneID uuid.UUID, algorithm FingerprintAlgorithm, hash string) error {
+	args := []interface{}{sceneID, algorithm.String(), hash}
+
+	query := `DELETE FROM scene_fingerprint_submissions WHERE scene_id = ? AND algorithm = ? AND hash = ?`
+	if err := qb.dbi.RawQuery(sceneFingerprintSubmissionTable.Table, query, args, nil); err != nil {
+		return err
+	}
+
+	query = `DELETE FROM scene_fingerprint_reports WHERE scene_id = ? AND algorithm = ? AND hash = ?`
+	if err := qb.dbi.RawQuery(sceneFingerprintReportTable.Table, query, args, nil); err != nil {
+		return err
+	}
+
+	query = `DELETE FROM scene_fingerprints WHERE scene_id = ? AND algorithm = ? AND hash = ?`
+	return qb.dbi.RawQuery(sceneFingerprintTable.Table, query, args, nil)
+}
+
+// UpdateFingerprints replaces the fingerprints of the scene, removing the
+// submissions and reports of the fingerprints that are no longer present.
 func (qb *SceneQueryBuilder) UpdateFingerprints(sceneID uuid.UUID, updatedJoins SceneFingerprints) error {
-	return qb.dbi.ReplaceJoins(sceneFingerprintTable, sceneID, &updatedJoins)
+	if err := qb.dbi.ReplaceJoins(sceneFingerprintTable, sceneID, &updatedJoins); err != nil {
+		return err
+	}
+
+	args := []interface{}{sceneID}
+	query := `DELETE FROM scene_fingerprint_submissions AS submissions
+		WHERE submissions.scene_id = ? AND NOT EXISTS (
+			SELECT 1 FROM scene_fingerprints
+			WHERE scene_fingerprints.scene_id = submissions.scene_id
+			AND scene_fingerprints.algorithm = submissions.algorithm
+			AND scene_fingerprints.hash = submissions.hash
+		)`
+	if err := qb.dbi.RawQuery(sceneFingerprintSubmissionTable.Table, query, args, nil); err != nil {
+		return err
+	}
+
+	query = `DELETE FROM scene_fingerprint_reports AS reports
+		WHERE reports.scene_id = ? AND NOT EXISTS (
+			SELECT 1 FROM scene_fingerprints
+			WHERE scene_fingerprints.scene_id = reports.scene_id
+			AND scene_fingerprints.algorithm = reports.algorithm
+			AND scene_fingerprints.hash = reports.hash
+		)`
+	return qb.dbi.RawQuery(sceneFingerprintReportTable.Table, query, args, nil)
+}
+
+func (qb *SceneQueryBuilder) UpdateImages(sceneID uuid.UUID, updatedJoins SceneImages) error {
+	return qb.dbi.ReplaceJoins(sceneImageTable, sceneID, &updatedJoins)
 }
 
 func (qb *SceneQueryBuilder) Find(id uuid.UUID) (*Scene, error) {
@@ -88,6 +313,127 @@ func (qb *SceneQueryBuilder) FindByFingerprints(fingerprints []string) ([]*Scene
 	return qb.queryScenes(query, args)
 }
 
+// fingerprintQueryChunkSize is the number of fingerprints matched by each
+// query, keeping the number of query parameters within the database limit.
+const fingerprintQueryChunkSize = 1000
+
+// sceneFingerprintSubmissionCountQuery is the number of submissions of the
+// fingerprint of the scene.
+const sceneFingerprintSubmissionCountQuery = `(
+	SELECT COUNT(*) FROM scene_fingerprint_submissions AS submissions
+	WHERE submissions.scene_id = scene_fingerprints.scene_id
+	AND submissions.algorithm = scene_fingerprints.algorithm
+	AND submissions.hash = scene_fingerprints.hash
+)`
+
+// sceneFingerprintReportCountQuery is the number of reports of the
+// fingerprint of the scene.
+const sceneFingerprintReportCountQuery = `(
+	SELECT COUNT(*) FROM scene_fingerprint_reports AS reports
+	WHERE reports.scene_id = scene_fingerprints.scene_id
+	AND reports.algorithm = scene_fingerprints.algorithm
+	AND reports.hash = scene_fingerprints.hash
+)`
+
+// FindByFingerprintQueries returns the scenes matching the algorithm, hash and
+// duration of each of the fingerprints, in the order of the fingerprints.
+// Fingerprints with more reports than submissions by more than the report
+// margin are not matched.
+func (qb *SceneQueryBuilder) FindByFingerprintQueries(fingerprints []*FingerprintQueryInput, reportMargin int) ([][]*Scene, error) {
+	matches := make(map[string]sceneFingerprintMatches)
+	fingerprintKey := func(algorithm string, hash string) string {
+		return algorithm + ":" + hash
+	}
+
+	for start := 0; start < len(fingerprints); start += fingerprintQueryChunkSize {
+		end := start + fingerprintQueryChunkSize
+		if end > len(fingerprints) {
+			end = len(fingerprints)
+		}
+
+		var clauses []string
+		var args []interface{}
+		for _, fingerprint := range fingerprints[start:end] {
+			clauses = append(clauses, "(?, ?)")
+			args = append(args, fingerprint.Algorithm.String(), fingerprint.Hash)
+		}
+
+		query := `
+			SELECT scenes.*, scene_fingerprints.algorithm AS fingerprint_algorithm,
+			scene_fingerprints.hash AS fingerprint_hash, scene_fingerprints.duration AS fingerprint_duration
+			FROM scenes
+			JOIN scene_fingerprints ON scene_fingerprints.scene_id = scenes.id
+			WHERE (scene_fingerprints.algorithm, scene_fingerprints.hash) IN (` + strings.Join(clauses, ", ") + `)
+			AND ` + sceneFingerprintReportCountQuery + ` <= ` + sceneFingerprintSubmissionCountQuery + ` + ?
+			ORDER BY scenes.id`
+		args = append(args, reportMargin)
+
+		output := sceneFingerprintMatches{}
+		if err := qb.dbi.RawQuery(sceneFingerprintMatchTable, query, args, &output); err != nil {
+			return nil, err
+		}
+
+		for _, match := range output {
+			key := fingerprintKey(match.FingerprintAlgorithm, match.FingerprintHash)
+			matches[key] = append(matches[key], match)
+		}
+	}
+
+	result := make([][]*Scene, len(fingerprints))
+	for i, fingerprint := range fingerprints {
+		for _, match := range matches[fingerprintKey(fingerprint.Algorithm.String(), fingerprint.Hash)] {
+			if fingerprint.MatchesDuration(match.FingerprintDuration) {
+				scene := match.Scene
+				result[i] = append(result[i], &scene)
+			}
+		}
+	}
+	return result, nil
+}
+
+// FindByPhash returns the scenes with a PHASH fingerprint within the Hamming
+// distance of the hash, ordered by distance. Any match has at least one
+// 16-bit segment within a quarter of the distance of the same segment of the
+// hash, so candidates are found using the segment indexes rather than
+// comparing the hash against every fingerprint.
+func (qb *SceneQueryBuilder) FindByPhash(phash int64, maxDistance int) ([]*Scene, error) {
+	var args []interface{}
+	where := "phash IS NOT NULL"
+
+	segmentDistance := maxDistance / phashSegments
+	if segmentDistance <= phashMaxSegmentDistance {
+		var clauses []string
+		for i := 0; i < phashSegments; i++ {
+			shift := 16 * (phashSegments - 1 - i)
+			clauses = append(clauses, fmt.Sprintf("((phash >> %d) & 65535) IN (?)", shift))
+			args = append(args, phashSegmentCandidates(phashSegment(phash, i), segmentDistance))
+		}
+		where += " AND (" + strings.Join(clauses, " OR ") + ")"
+	}
+
+	// the hamming distance is the number of set bits in the exclusive or
+	query := `
+		SELECT scenes.* FROM scenes
+		JOIN (
+			SELECT scene_id, MIN(distance) AS distance FROM (
+				SELECT scene_id, LENGTH(REPLACE((phash # ?)::bit(64)::text, '0', '')) AS distance
+				FROM scene_fingerprints
+				WHERE ` + where + `
+			) candidates
+			WHERE distance <= ?
+			GROUP BY scene_id
+		) matches ON matches.scene_id = scenes.id
+		ORDER BY matches.distance, scenes.id`
+
+	args = append([]interface{}{phash}, args...)
+	args = append(args, maxDistance)
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return qb.queryScenes(query, args)
+}
+
 // func (qb *SceneQueryBuilder) FindByStudioID(sceneID int) ([]*Scene, error) {
 // 	query := `
 // 		SELECT scenes.* FROM scenes
@@ -138,13 +484,20 @@ func (qb *SceneQueryBuilder) Count() (int, error) {
 	return runCountQuery(buildCountQuery("SELECT scenes.id FROM scenes"), nil)
 }
 
-func (qb *SceneQueryBuilder) Query(sceneFilter *SceneFilterType, findFilter *QuerySpec) ([]*Scene, int) {
+// sceneFingerprintDurationSpreadQuery is the difference between the longest
+// and shortest known durations of the fingerprints of the scene.
+const sceneFingerprintDurationSpreadQuery = `(
+	SELECT COALESCE(MAX(duration) - MIN(duration), 0) FROM scene_fingerprints
+	WHERE scene_fingerprints.scene_id = scenes.id AND duration > 0
+)`
+
+// buildFilterQuery returns a query builder for the scenes matching the
+// filter. The query lists and facet counts share it, so that the counts
+// always match the filtered list.
+func (qb *SceneQueryBuilder) buildFilterQuery(sceneFilter *SceneFilterType) *database.QueryBuilder {
 	if sceneFilter == nil {
 		sceneFilter = &SceneFilterType{}
 	}
-	if findFilter == nil {
-		findFilter = &QuerySpec{}
-	}
 
 	query := database.NewQueryBuilder(sceneDBTable)
 
@@ -216,8 +569,19 @@ func (qb *SceneQueryBuilder) Query(sceneFilter *SceneFilterType, findFilter *Que
 		}
 	}
 
+	addSubqueryCriterion(query, sceneFingerprintDurationSpreadQuery, sceneFilter.FingerprintDurationSpread)
+
 	// TODO - other filters
 
+	return query
+}
+
+func (qb *SceneQueryBuilder) Query(sceneFilter *SceneFilterType, findFilter *QuerySpec) ([]*Scene, int) {
+	if findFilter == nil {
+		findFilter = &QuerySpec{}
+	}
+
+	query := qb.buildFilterQuery(sceneFilter)
 	query.SortAndPagination = qb.getSceneSort(findFilter) + getPagination(findFilter)
 
 	var scenes Scenes
@@ -231,6 +595,79 @@ func (qb *SceneQueryBuilder) Query(sceneFilter *SceneFilterType, findFilter *Que
 	return scenes, countResult
 }
 
+// GetStudioFacets returns the number of scenes matching the filter for each
+// studio, limited to the limit most frequent studios.
+func (qb *SceneQueryBuilder) GetStudioFacets(sceneFilter *SceneFilterType, limit int) ([]*StudioFacet, error) {
+	query := qb.buildFilterQuery(sceneFilter)
+	counts, err := qb.dbi.Facet(*query, "filtered.studio_id", "", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*StudioFacet
+	for _, count := range counts {
+		studioID, err := uuid.FromString(count.Value)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &StudioFacet{
+			StudioID: studioID,
+			Count:    count.Count,
+		})
+	}
+
+	return ret, nil
+}
+
+// GetTagFacets returns the number of scenes matching the filter for each
+// tag, limited to the limit most frequent tags.
+func (qb *SceneQueryBuilder) GetTagFacets(sceneFilter *SceneFilterType, limit int) ([]*TagFacet, error) {
+	query := qb.buildFilterQuery(sceneFilter)
+	join := "JOIN " + sceneTagTable.Name() + " AS facet_tags ON facet_tags.scene_id = filtered.id"
+	counts, err := qb.dbi.Facet(*query, "facet_tags.tag_id", join, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*TagFacet
+	for _, count := range counts {
+		tagID, err := uuid.FromString(count.Value)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &TagFacet{
+			TagID: tagID,
+			Count: count.Count,
+		})
+	}
+
+	return ret, nil
+}
+
+// GetYearFacets returns the number of scenes matching the filter for each
+// release year.
+func (qb *SceneQueryBuilder) GetYearFacets(sceneFilter *SceneFilterType) ([]*YearFacet, error) {
+	query := qb.buildFilterQuery(sceneFilter)
+	counts, err := qb.dbi.Facet(*query, "CAST(EXTRACT(YEAR FROM filtered.date) AS INTEGER)", "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*YearFacet
+	for _, count := range counts {
+		year, err := strconv.Atoi(count.Value)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, &YearFacet{
+			Year:  year,
+			Count: count.Count,
+		})
+	}
+
+	return ret, nil
+}
+
 func getMultiCriterionClause(joinTable database.TableJoin, joinTableField string, criterion *MultiIDCriterionInput) (string, string) {
 	joinTableName := joinTable.Name()
 	whereClause := ""
@@ -271,23 +708,38 @@ func (qb *SceneQueryBuilder) queryScenes(query string, args []interface{}) (Scen
 	return output, err
 }
 
-func (qb *SceneQueryBuilder) GetFingerprints(id uuid.UUID) ([]*Fingerprint, error) {
+func (qb *SceneQueryBuilder) GetRawFingerprints(id uuid.UUID) (SceneFingerprints, error) {
 	joins := SceneFingerprints{}
 	err := qb.dbi.FindJoins(sceneFingerprintTable, id, &joins)
 
+	return joins, err
+}
+
+func (qb *SceneQueryBuilder) GetFingerprints(id uuid.UUID) ([]*Fingerprint, error) {
+	joins, err := qb.GetRawFingerprints(id)
 	return joins.ToFingerprints(), err
 }
 
+// GetAllFingerprints returns the fingerprints of each of the scenes, along
+// with their number of submissions.
 func (qb *SceneQueryBuilder) GetAllFingerprints(ids []uuid.UUID) ([][]*Fingerprint, []error) {
-	joins := SceneFingerprints{}
-	err := qb.dbi.FindAllJoins(sceneFingerprintTable, ids, &joins)
-	if err != nil {
+	query := `
+		SELECT scene_fingerprints.*, ` + sceneFingerprintSubmissionCountQuery + ` AS submissions
+		FROM scene_fingerprints
+		WHERE scene_fingerprints.scene_id IN (?)
+		ORDER BY scene_fingerprints.created_at, scene_fingerprints.hash`
+	query, args, _ := sqlx.In(query, ids)
+
+	joins := sceneFingerprintCounts{}
+	if err := qb.dbi.RawQuery(sceneFingerprintCountTable, query, args, &joins); err != nil {
 		return nil, utils.DuplicateError(err, len(ids))
 	}
 
 	m := make(map[uuid.UUID][]*Fingerprint)
 	for _, join := range joins {
-		m[join.SceneID] = append(m[join.SceneID], join.ToFingerprint())
+		fingerprint := join.ToFingerprint()
+		fingerprint.Submissions = join.Submissions
+		m[join.SceneID] = append(m[join.SceneID], fingerprint)
 	}
 
 	result := make([][]*Fingerprint, len(ids))
@@ -323,6 +775,20 @@ func (qb *SceneQueryBuilder) GetAllAppearances(ids []uuid.UUID) ([]PerformersSce
 	return result, nil
 }
 
+func (qb *SceneQueryBuilder) GetTags(id uuid.UUID) (ScenesTags, error) {
+	joins := ScenesTags{}
+	err := qb.dbi.FindJoins(sceneTagTable, id, &joins)
+
+	return joins, err
+}
+
+func (qb *SceneQueryBuilder) GetImages(id uuid.UUID) (SceneImages, error) {
+	joins := SceneImages{}
+	err := qb.dbi.FindJoins(sceneImageTable, id, &joins)
+
+	return joins, err
+}
+
 func (qb *SceneQueryBuilder) GetUrls(id uuid.UUID) (SceneUrls, error) {
 	joins := SceneUrls{}
 	err := qb.dbi.FindJoins(sceneUrlTable, id, &joins)
@@ -353,18 +819,401 @@ func (qb *SceneQueryBuilder) GetAllUrls(ids []uuid.UUID) ([][]*URL, []error) {
 	return result, nil
 }
 
+// sceneSearchVector is the text search vector of the search row of a scene,
+// as indexed by the database.
+const sceneSearchVector = `(
+	to_tsvector('simple', COALESCE(scene_date, '')) ||
+	to_tsvector('english', studio_name) ||
+	to_tsvector('english', COALESCE(performer_names, '')) ||
+	to_tsvector('english', COALESCE(tag_names, '')) ||
+	to_tsvector('english', scene_title)
+)`
+
 func (qb *SceneQueryBuilder) SearchScenes(term string) ([]*Scene, error) {
+	if filename := ParseSceneFilename(term); filename != nil {
+		return qb.searchSceneFilename(filename)
+	}
+
 	query := `
         SELECT S.* FROM scenes S
         LEFT JOIN scene_search SS ON SS.scene_id = S.id
-        WHERE (
-			to_tsvector('simple', COALESCE(scene_date, '')) ||
-			to_tsvector('english', studio_name) ||
-			to_tsvector('english', COALESCE(performer_names, '')) ||
-			to_tsvector('english', scene_title)
-        ) @@ plainto_tsquery(?)
+        WHERE ` + sceneSearchVector + ` @@ plainto_tsquery(?)
         LIMIT 10`
 	var args []interface{}
 	args = append(args, term)
 	return qb.queryScenes(query, args)
 }
+
+// searchSceneFilename returns the scenes of the studio and date of the
+// filename, ranked by how well they match the remaining words. If no scenes
+// match the studio and date, scenes are ranked by how well they match any of
+// the words of the filename.
+func (qb *SceneQueryBuilder) searchSceneFilename(filename *SceneFilename) ([]*Scene, error) {
+	if filename.Studio != "" || filename.Date != "" {
+		scenes, err := qb.findSceneFilename(filename)
+		if err != nil || len(scenes) > 0 {
+			return scenes, err
+		}
+	}
+
+	var words []string
+	if filename.Studio != "" {
+		words = append(words, filename.Studio)
+	}
+	words = append(words, filename.Tokens...)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	// the words are alphanumeric, so may be joined into a query directly
+	tsquery := strings.Join(words, " | ")
+	query := `
+        SELECT S.* FROM scenes S
+        JOIN scene_search SS ON SS.scene_id = S.id
+        WHERE ` + sceneSearchVector + ` @@ to_tsquery('english', ?)
+        ORDER BY ts_rank(` + sceneSearchVector + `, to_tsquery('english', ?)) DESC, S.title
+        LIMIT 10`
+	args := []interface{}{tsquery, tsquery}
+	return qb.queryScenes(query, args)
+}
+
+// sceneFilenameStudioMatch matches the studio alias if its name, without
+// spaces and punctuation, or the initials of its name equal the lowercase
+// abbreviation.
+func sceneFilenameStudioMatch(alias string) string {
+	return `(LOWER(REGEXP_REPLACE(` + alias + `.name, '[^a-zA-Z0-9]', '', 'g')) = ?
+		OR LOWER(REGEXP_REPLACE(` + alias + `.name, '([a-zA-Z0-9])[a-zA-Z0-9]*[^a-zA-Z0-9]*', '\1', 'g')) = ?)`
+}
+
+// findSceneFilename returns the scenes with the studio and date of the
+// filename, ranked by how well they match the remaining words. The scenes of
+// child studios match the abbreviation of their parent studio.
+func (qb *SceneQueryBuilder) findSceneFilename(filename *SceneFilename) ([]*Scene, error) {
+	query := `
+        SELECT S.* FROM scenes S
+        LEFT JOIN scene_search SS ON SS.scene_id = S.id
+        LEFT JOIN studios T ON T.id = S.studio_id
+        LEFT JOIN studios TP ON TP.id = T.parent_studio_id
+        WHERE NOT S.deleted`
+	var args []interface{}
+
+	if filename.Date != "" {
+		query += " AND S.date = ?"
+		args = append(args, filename.Date)
+	}
+
+	if filename.Studio != "" {
+		studio := strings.ToLower(filename.Studio)
+		query += " AND (" + sceneFilenameStudioMatch("T") + " OR " + sceneFilenameStudioMatch("TP") + ")"
+		args = append(args, studio, studio, studio, studio)
+	}
+
+	if len(filename.Tokens) > 0 {
+		query += " ORDER BY ts_rank(" + sceneSearchVector + ", to_tsquery('english', ?)) DESC NULLS LAST, S.title"
+		args = append(args, strings.Join(filename.Tokens, " | "))
+	} else {
+		query += " ORDER BY S.title"
+	}
+
+	query += " LIMIT 10"
+	return qb.queryScenes(query, args)
+}
+
+// RebuildSearch replaces the search rows of all scenes. The rows are
+// maintained by database triggers, so this is only needed to recover from
+// changes made while the triggers were disabled.
+func (qb *SceneQueryBuilder) RebuildSearch() error {
+	query := `DELETE FROM scene_search`
+	if err := qb.dbi.RawQuery(sceneDBTable, query, nil, nil); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO scene_search (scene_id, scene_title, scene_date, studio_name, performer_names, tag_names)
+		SELECT scene_id, scene_title, scene_date, studio_name, performer_names, tag_names FROM scene_search_source`
+	return qb.dbi.RawQuery(sceneDBTable, query, nil, nil)
+}
+
+func (qb *SceneQueryBuilder) MergeInto(sourceID uuid.UUID, targetID uuid.UUID) error {
+	scene, err := qb.Find(sourceID)
+	if err != nil {
+		return err
+	}
+	if scene == nil {
+		return errors.New("Merge source scene not found: " + sourceID.String())
+	}
+	if scene.Deleted {
+		return errors.New("Merge source scene is deleted: " + sourceID.String())
+	}
+	if err := qb.MoveFingerprints(sourceID, targetID); err != nil {
+		return err
+	}
+	_, err = qb.SoftDelete(*scene)
+	if err != nil {
+		return err
+	}
+	if err := qb.UpdateRedirects(sourceID, targetID); err != nil {
+		return err
+	}
+	redirect := SceneRedirect{SourceID: sourceID, TargetID: targetID}
+	return qb.CreateRedirect(redirect)
+}
+
+func (qb *SceneQueryBuilder) ApplyEdit(edit Edit, operation OperationEnum, scene *Scene) (*Scene, error) {
+	data, err := edit.GetSceneData()
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation {
+	case OperationEnumCreate:
+		now := time.Now()
+		UUID, err := uuid.NewV4()
+		if err != nil {
+			return nil, err
+		}
+		newScene := Scene{
+			ID:        UUID,
+			CreatedAt: SQLiteTimestamp{Timestamp: now},
+		}
+		newScene.CopyFromSceneEdit(*data.New)
+
+		scene, err = qb.Create(newScene)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := qb.applyEditJoins(scene.ID, *data.New); err != nil {
+			return nil, err
+		}
+
+		return scene, nil
+	case OperationEnumDestroy:
+		return qb.SoftDelete(*scene)
+	case OperationEnumModify:
+		if err := scene.ValidateModifyEdit(*data); err != nil {
+			return nil, err
+		}
+
+		scene.CopyFromSceneEdit(*data.New)
+		updatedScene, err := qb.Update(*scene)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := qb.applyEditJoins(updatedScene.ID, *data.New); err != nil {
+			return nil, err
+		}
+
+		return updatedScene, nil
+	case OperationEnumMerge:
+		if err := scene.ValidateModifyEdit(*data); err != nil {
+			return nil, err
+		}
+
+		scene.CopyFromSceneEdit(*data.New)
+		updatedScene, err := qb.Update(*scene)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range data.MergeSources {
+			sourceUUID, _ := uuid.FromString(v)
+			if err := qb.MergeInto(sourceUUID, scene.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := qb.applyEditJoins(updatedScene.ID, *data.New); err != nil {
+			return nil, err
+		}
+
+		return updatedScene, nil
+	default:
+		return nil, errors.New("Unsupported operation: " + operation.String())
+	}
+}
+
+// applyEditJoins adds and removes the urls, performers, tags, images and
+// fingerprints specified in the edit data to/from the scene.
+func (qb *SceneQueryBuilder) applyEditJoins(sceneID uuid.UUID, data SceneEdit) error {
+	currentUrls, err := qb.GetUrls(sceneID)
+	if err != nil {
+		return err
+	}
+	if err := currentUrls.RemoveUrls(data.RemovedUrls); err != nil {
+		return err
+	}
+	if err := currentUrls.AddUrls(CreateSceneEditUrls(sceneID, data.AddedUrls)); err != nil {
+		return err
+	}
+	if err := qb.UpdateUrls(sceneID, currentUrls); err != nil {
+		return err
+	}
+
+	// remove first, so that performers with a modified alias are replaced
+	currentPerformers, err := qb.GetPerformers(sceneID)
+	if err != nil {
+		return err
+	}
+	if err := currentPerformers.RemovePerformers(data.RemovedPerformers); err != nil {
+		return err
+	}
+	if err := currentPerformers.AddPerformers(CreateScenePerformers(sceneID, data.AddedPerformers)); err != nil {
+		return err
+	}
+	if err := qb.dbi.ReplaceJoins(scenePerformerTable, sceneID, &currentPerformers); err != nil {
+		return err
+	}
+
+	currentTags, err := qb.GetTags(sceneID)
+	if err != nil {
+		return err
+	}
+	if err := currentTags.RemoveTags(data.RemovedTags); err != nil {
+		return err
+	}
+	if err := currentTags.AddTags(CreateSceneTags(sceneID, data.AddedTags)); err != nil {
+		return err
+	}
+	if err := qb.dbi.ReplaceJoins(sceneTagTable, sceneID, &currentTags); err != nil {
+		return err
+	}
+
+	currentImages, err := qb.GetImages(sceneID)
+	if err != nil {
+		return err
+	}
+	if err := currentImages.RemoveImages(data.RemovedImages); err != nil {
+		return err
+	}
+	if err := currentImages.AddImages(CreateSceneImages(sceneID, data.AddedImages)); err != nil {
+		return err
+	}
+	if err := qb.UpdateImages(sceneID, currentImages); err != nil {
+		return err
+	}
+
+	currentFingerprints, err := qb.GetRawFingerprints(sceneID)
+	if err != nil {
+		return err
+	}
+	if err := currentFingerprints.RemoveFingerprints(data.RemovedFingerprints); err != nil {
+		return err
+	}
+	if err := currentFingerprints.AddFingerprints(CreateSceneEditFingerprints(sceneID, data.AddedFingerprints)); err != nil {
+		return err
+	}
+	return qb.UpdateFingerprints(sceneID, currentFingerprints)
+}
+
+func (qb *SceneQueryBuilder) getSnapshot(id uuid.UUID) (*SceneSnapshot, error) {
+	scene, err := qb.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if scene == nil {
+		return nil, errors.New("Scene not found: " + id.String())
+	}
+
+	ret := &SceneSnapshot{Scene: *scene}
+	if ret.Urls, err = qb.GetUrls(id); err != nil {
+		return nil, err
+	}
+	if ret.Images, err = qb.GetImages(id); err != nil {
+		return nil, err
+	}
+	if ret.Performers, err = qb.GetPerformers(id); err != nil {
+		return nil, err
+	}
+	if ret.Tags, err = qb.GetTags(id); err != nil {
+		return nil, err
+	}
+	if ret.Fingerprints, err = qb.GetRawFingerprints(id); err != nil {
+		return nil, err
+	}
+	if ret.RedirectSourceIDs, err = findRedirectSourceIDs(qb.dbi, sceneRedirectTable, id); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// GetEditSnapshot returns the current state of the scenes affected by the
+// edit, so that the edit can be reverted once applied.
+func (qb *SceneQueryBuilder) GetEditSnapshot(edit Edit, scene *Scene) (*SceneEditSnapshot, error) {
+	data, err := edit.GetSceneData()
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := qb.getSnapshot(scene.ID)
+	if err != nil {
+		return nil, err
+	}
+	ret := &SceneEditSnapshot{Target: target}
+
+	for _, v := range data.MergeSources {
+		sourceUUID, _ := uuid.FromString(v)
+		source, err := qb.getSnapshot(sourceUUID)
+		if err != nil {
+			return nil, err
+		}
+		ret.MergeSources = append(ret.MergeSources, source)
+	}
+
+	return ret, nil
+}
+
+func (qb *SceneQueryBuilder) restoreSnapshot(snapshot SceneSnapshot) error {
+	scene := snapshot.Scene
+	scene.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
+	if _, err := qb.dbi.Update(scene, true); err != nil {
+		return err
+	}
+	if err := qb.UpdateUrls(scene.ID, snapshot.Urls); err != nil {
+		return err
+	}
+	if err := qb.UpdateImages(scene.ID, snapshot.Images); err != nil {
+		return err
+	}
+	if err := qb.dbi.ReplaceJoins(scenePerformerTable, scene.ID, &snapshot.Performers); err != nil {
+		return err
+	}
+	if err := qb.dbi.ReplaceJoins(sceneTagTable, scene.ID, &snapshot.Tags); err != nil {
+		return err
+	}
+	if err := qb.UpdateFingerprints(scene.ID, snapshot.Fingerprints); err != nil {
+		return err
+	}
+	return restoreRedirects(qb.dbi, sceneRedirectTable, scene.ID, snapshot.RedirectSourceIDs)
+}
+
+// RevertEdit undoes the changes of an applied edit to the scene, restoring
+// the scene and any merge sources from the snapshot stored with the edit.
+func (qb *SceneQueryBuilder) RevertEdit(edit Edit, operation OperationEnum, scene *Scene) error {
+	if operation == OperationEnumCreate {
+		_, err := qb.SoftDelete(*scene)
+		return err
+	}
+
+	var snapshot SceneEditSnapshot
+	if err := edit.GetSnapshot(&snapshot); err != nil {
+		return err
+	}
+	if snapshot.Target == nil {
+		return errors.New("Edit snapshot does not contain the scene")
+	}
+
+	// the target is restored first, so that the fingerprints moved from the
+	// merge sources are removed before being restored to the sources
+	if err := qb.restoreSnapshot(*snapshot.Target); err != nil {
+		return err
+	}
+	for _, source := range snapshot.MergeSources {
+		if err := qb.restoreSnapshot(*source); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
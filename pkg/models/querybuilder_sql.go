@@ -255,6 +255,28 @@ func runIdsQuery(query string, args []interface{}) ([]uuid.UUID, error) {
 	return vsm, nil
 }
 
+// addSubqueryCriterion adds a where clause comparing the value of the
+// subquery against the criterion.
+func addSubqueryCriterion(query *database.QueryBuilder, subquery string, criterion *IntCriterionInput) {
+	if criterion == nil {
+		return
+	}
+
+	switch criterion.Modifier {
+	case CriterionModifierEquals:
+		query.AddWhere(subquery + " = ?")
+	case CriterionModifierNotEquals:
+		query.AddWhere(subquery + " != ?")
+	case CriterionModifierGreaterThan:
+		query.AddWhere(subquery + " > ?")
+	case CriterionModifierLessThan:
+		query.AddWhere(subquery + " < ?")
+	default:
+		return
+	}
+	query.AddArg(criterion.Value)
+}
+
 func runCountQuery(query string, args []interface{}) (int, error) {
 	// Perform query and fetch result
 	result := struct {
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"errors"
+	"time"
+
 	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stashdb/pkg/database"
@@ -39,6 +42,53 @@ func (qb *StudioQueryBuilder) Destroy(id uuid.UUID) error {
 	return qb.dbi.Delete(id, studioDBTable)
 }
 
+func (qb *StudioQueryBuilder) SoftDelete(studio Studio) (*Studio, error) {
+	ret, err := qb.dbi.SoftDelete(studio)
+	return qb.toModel(ret), err
+}
+
+func (qb *StudioQueryBuilder) CreateRedirect(newJoin StudioRedirect) error {
+	return qb.dbi.InsertJoin(studioRedirectTable, newJoin, false)
+}
+
+func (qb *StudioQueryBuilder) UpdateRedirects(oldTargetID uuid.UUID, newTargetID uuid.UUID) error {
+	query := "UPDATE " + studioRedirectTable.Table.Name() + " SET target_id = ? WHERE target_id = ?"
+	args := []interface{}{newTargetID, oldTargetID}
+	return qb.dbi.RawQuery(studioRedirectTable.Table, query, args, nil)
+}
+
+func (qb *StudioQueryBuilder) UpdateSceneStudios(oldStudioID uuid.UUID, newStudioID uuid.UUID) error {
+	query := "UPDATE " + sceneDBTable.Name() + " SET studio_id = ? WHERE studio_id = ?"
+	args := []interface{}{newStudioID, oldStudioID}
+	return qb.dbi.RawQuery(sceneDBTable, query, args, nil)
+}
+
+func (qb *StudioQueryBuilder) UpdateChildStudios(oldParentID uuid.UUID, newParentID uuid.UUID) error {
+	// the new parent cannot be its own child
+	query := "UPDATE " + studioDBTable.Name() + " SET parent_studio_id = ? WHERE parent_studio_id = ? AND id != ?"
+	args := []interface{}{newParentID, oldParentID, newParentID}
+	return qb.dbi.RawQuery(studioDBTable, query, args, nil)
+}
+
+func (qb *StudioQueryBuilder) UpdateParent(studioID uuid.UUID, parentID uuid.NullUUID) error {
+	query := "UPDATE " + studioDBTable.Name() + " SET parent_studio_id = ? WHERE id = ?"
+	args := []interface{}{parentID, studioID}
+	return qb.dbi.RawQuery(studioDBTable, query, args, nil)
+}
+
+// ClearReferences removes the studio from its scenes, and as the parent of
+// its child studios.
+func (qb *StudioQueryBuilder) ClearReferences(id uuid.UUID) error {
+	query := "UPDATE " + sceneDBTable.Name() + " SET studio_id = NULL WHERE studio_id = ?"
+	args := []interface{}{id}
+	if err := qb.dbi.RawQuery(sceneDBTable, query, args, nil); err != nil {
+		return err
+	}
+
+	query = "UPDATE " + studioDBTable.Name() + " SET parent_studio_id = NULL WHERE parent_studio_id = ?"
+	return qb.dbi.RawQuery(studioDBTable, query, args, nil)
+}
+
 func (qb *StudioQueryBuilder) CreateUrls(newJoins StudioUrls) error {
 	return qb.dbi.InsertJoins(studioUrlTable, &newJoins)
 }
@@ -47,11 +97,38 @@ func (qb *StudioQueryBuilder) UpdateUrls(studioID uuid.UUID, updatedJoins Studio
 	return qb.dbi.ReplaceJoins(studioUrlTable, studioID, &updatedJoins)
 }
 
+func (qb *StudioQueryBuilder) UpdateImages(studioID uuid.UUID, updatedJoins StudioImages) error {
+	return qb.dbi.ReplaceJoins(studioImageTable, studioID, &updatedJoins)
+}
+
 func (qb *StudioQueryBuilder) Find(id uuid.UUID) (*Studio, error) {
 	ret, err := qb.dbi.Find(id, studioDBTable)
 	return qb.toModel(ret), err
 }
 
+func (qb *StudioQueryBuilder) FindByIds(ids []uuid.UUID) ([]*Studio, []error) {
+	query := `
+		SELECT studios.* FROM studios
+		WHERE id IN (?)
+	`
+	query, args, _ := sqlx.In(query, ids)
+	studios, err := qb.queryStudios(query, args)
+	if err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID]*Studio)
+	for _, studio := range studios {
+		m[studio.ID] = studio
+	}
+
+	result := make([]*Studio, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+	}
+	return result, nil
+}
+
 func (qb *StudioQueryBuilder) FindBySceneID(sceneID int) (Studios, error) {
 	query := `
 		SELECT studios.* FROM studios
@@ -142,6 +219,32 @@ func (qb *StudioQueryBuilder) queryStudios(query string, args []interface{}) (St
 	return output, err
 }
 
+// SearchStudios returns the studios whose name, or whose parent studio name,
+// contains words similar to the term, the most similar first. Studios matched
+// by the name of their parent rank below the parent itself.
+func (qb *StudioQueryBuilder) SearchStudios(term string) (Studios, error) {
+	query := `
+        SELECT studios.* FROM (
+            SELECT id, MAX(score) AS score FROM (
+                SELECT id, word_similarity($1, name) AS score
+                FROM studios
+                WHERE $1 <% name
+                UNION ALL
+                SELECT S.id, word_similarity($1, P.name) / 2
+                FROM studios S
+                JOIN studios P ON P.id = S.parent_studio_id
+                WHERE $1 <% P.name
+            ) candidates
+            GROUP BY id
+        ) matches
+        JOIN studios ON studios.id = matches.id
+        WHERE studios.deleted = FALSE
+        ORDER BY matches.score DESC, studios.name
+        LIMIT 10`
+	args := []interface{}{term}
+	return qb.queryStudios(query, args)
+}
+
 func (qb *StudioQueryBuilder) GetUrls(id uuid.UUID) (StudioUrls, error) {
 	joins := StudioUrls{}
 	err := qb.dbi.FindJoins(studioUrlTable, id, &joins)
@@ -149,6 +252,13 @@ func (qb *StudioQueryBuilder) GetUrls(id uuid.UUID) (StudioUrls, error) {
 	return joins, err
 }
 
+func (qb *StudioQueryBuilder) GetImages(id uuid.UUID) (StudioImages, error) {
+	joins := StudioImages{}
+	err := qb.dbi.FindJoins(studioImageTable, id, &joins)
+
+	return joins, err
+}
+
 func (qb *StudioQueryBuilder) GetAllUrls(ids []uuid.UUID) ([][]*URL, []error) {
 	joins := StudioUrls{}
 	err := qb.dbi.FindAllJoins(studioUrlTable, ids, &joins)
@@ -171,3 +281,379 @@ func (qb *StudioQueryBuilder) GetAllUrls(ids []uuid.UUID) ([][]*URL, []error) {
 	}
 	return result, nil
 }
+
+func (qb *StudioQueryBuilder) MergeInto(sourceID uuid.UUID, targetID uuid.UUID) error {
+	studio, err := qb.Find(sourceID)
+	if err != nil {
+		return err
+	}
+	if studio == nil {
+		return errors.New("Merge source studio not found: " + sourceID.String())
+	}
+	if studio.Deleted {
+		return errors.New("Merge source studio is deleted: " + sourceID.String())
+	}
+
+	// a target that is a child of the source takes over the source's parent
+	target, err := qb.Find(targetID)
+	if err != nil {
+		return err
+	}
+	if target != nil && target.ParentStudioID.Valid && target.ParentStudioID.UUID == sourceID {
+		if err := qb.UpdateParent(targetID, studio.ParentStudioID); err != nil {
+			return err
+		}
+	}
+
+	if err := qb.UpdateChildStudios(sourceID, targetID); err != nil {
+		return err
+	}
+	if err := qb.UpdateSceneStudios(sourceID, targetID); err != nil {
+		return err
+	}
+	_, err = qb.SoftDelete(*studio)
+	if err != nil {
+		return err
+	}
+	if err := qb.UpdateRedirects(sourceID, targetID); err != nil {
+		return err
+	}
+	redirect := StudioRedirect{SourceID: sourceID, TargetID: targetID}
+	return qb.CreateRedirect(redirect)
+}
+
+func (qb *StudioQueryBuilder) ApplyEdit(edit Edit, operation OperationEnum, studio *Studio) (*Studio, error) {
+	data, err := edit.GetStudioData()
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation {
+	case OperationEnumCreate:
+		now := time.Now()
+		UUID, err := uuid.NewV4()
+		if err != nil {
+			return nil, err
+		}
+		newStudio := Studio{
+			ID:        UUID,
+			CreatedAt: SQLiteTimestamp{Timestamp: now},
+		}
+		newStudio.CopyFromStudioEdit(*data.New)
+
+		studio, err = qb.Create(newStudio)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := qb.applyEditJoins(studio.ID, *data.New); err != nil {
+			return nil, err
+		}
+
+		return studio, nil
+	case OperationEnumDestroy:
+		updatedStudio, err := qb.SoftDelete(*studio)
+		if err != nil {
+			return nil, err
+		}
+		err = qb.ClearReferences(studio.ID)
+		return updatedStudio, err
+	case OperationEnumModify:
+		if err := studio.ValidateModifyEdit(*data); err != nil {
+			return nil, err
+		}
+
+		studio.CopyFromStudioEdit(*data.New)
+		updatedStudio, err := qb.Update(*studio)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := qb.applyEditJoins(updatedStudio.ID, *data.New); err != nil {
+			return nil, err
+		}
+
+		return updatedStudio, nil
+	case OperationEnumMerge:
+		if err := studio.ValidateModifyEdit(*data); err != nil {
+			return nil, err
+		}
+
+		studio.CopyFromStudioEdit(*data.New)
+		updatedStudio, err := qb.Update(*studio)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range data.MergeSources {
+			sourceUUID, _ := uuid.FromString(v)
+			if err := qb.MergeInto(sourceUUID, studio.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := qb.applyEditJoins(updatedStudio.ID, *data.New); err != nil {
+			return nil, err
+		}
+
+		return qb.Find(updatedStudio.ID)
+	default:
+		return nil, errors.New("Unsupported operation: " + operation.String())
+	}
+}
+
+// applyEditJoins removes the parent if the edit clears it, adds and removes
+// the urls, child studios and images specified in the edit data to/from the
+// studio, and then checks that the parent chain of the studio does not
+// contain a cycle.
+func (qb *StudioQueryBuilder) applyEditJoins(studioID uuid.UUID, data StudioEdit) error {
+	// a cleared parent is skipped when updating the studio
+	if data.RemoveParent {
+		if err := qb.UpdateParent(studioID, uuid.NullUUID{}); err != nil {
+			return err
+		}
+	}
+
+	currentUrls, err := qb.GetUrls(studioID)
+	if err != nil {
+		return err
+	}
+	if err := currentUrls.RemoveUrls(data.RemovedUrls); err != nil {
+		return err
+	}
+	if err := currentUrls.AddUrls(CreateStudioEditUrls(studioID, data.AddedUrls)); err != nil {
+		return err
+	}
+	if err := qb.UpdateUrls(studioID, currentUrls); err != nil {
+		return err
+	}
+
+	currentImages, err := qb.GetImages(studioID)
+	if err != nil {
+		return err
+	}
+	if err := currentImages.RemoveImages(data.RemovedImages); err != nil {
+		return err
+	}
+	if err := currentImages.AddImages(CreateStudioImages(studioID, data.AddedImages)); err != nil {
+		return err
+	}
+	if err := qb.UpdateImages(studioID, currentImages); err != nil {
+		return err
+	}
+
+	for _, v := range data.RemovedChildStudios {
+		childID, _ := uuid.FromString(v)
+		child, err := qb.Find(childID)
+		if err != nil {
+			return err
+		}
+		if child == nil || !child.ParentStudioID.Valid || child.ParentStudioID.UUID != studioID {
+			return errors.New("Invalid child studio removal. Child studio does not exist: '" + v + "'")
+		}
+		if err := qb.UpdateParent(childID, uuid.NullUUID{}); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range data.AddedChildStudios {
+		childID, _ := uuid.FromString(v)
+		child, err := qb.Find(childID)
+		if err != nil {
+			return err
+		}
+		if child == nil {
+			return errors.New("Invalid child studio addition. Studio not found: '" + v + "'")
+		}
+		if child.ParentStudioID.Valid && child.ParentStudioID.UUID == studioID {
+			return errors.New("Invalid child studio addition. Child studio already exists '" + v + "'")
+		}
+		if err := qb.UpdateParent(childID, uuid.NullUUID{UUID: studioID, Valid: true}); err != nil {
+			return err
+		}
+	}
+
+	// adding a child can only create a cycle that includes the studio itself
+	return qb.validateParentChain(studioID)
+}
+
+// validateParentChain returns an error if following the parent studios of
+// the provided studio leads back to a studio already visited.
+func (qb *StudioQueryBuilder) validateParentChain(studioID uuid.UUID) error {
+	visited := map[uuid.UUID]bool{}
+	currentID := studioID
+	for {
+		if visited[currentID] {
+			return errors.New("Invalid parent studio. Studio '" + studioID.String() + "' would be its own ancestor")
+		}
+		visited[currentID] = true
+
+		studio, err := qb.Find(currentID)
+		if err != nil {
+			return err
+		}
+		if studio == nil || !studio.ParentStudioID.Valid {
+			return nil
+		}
+		currentID = studio.ParentStudioID.UUID
+	}
+}
+
+func (qb *StudioQueryBuilder) getSnapshot(id uuid.UUID) (*StudioSnapshot, error) {
+	studio, err := qb.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if studio == nil {
+		return nil, errors.New("Studio not found: " + id.String())
+	}
+
+	ret := &StudioSnapshot{Studio: *studio}
+	if ret.Urls, err = qb.GetUrls(id); err != nil {
+		return nil, err
+	}
+	if ret.Images, err = qb.GetImages(id); err != nil {
+		return nil, err
+	}
+	children, err := qb.FindByParentID(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		ret.ChildStudioIDs = append(ret.ChildStudioIDs, child.ID)
+	}
+	if ret.RedirectSourceIDs, err = findRedirectSourceIDs(qb.dbi, studioRedirectTable, id); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (qb *StudioQueryBuilder) findSceneIDs(id uuid.UUID) ([]uuid.UUID, error) {
+	query := "SELECT * FROM " + sceneDBTable.Name() + " WHERE studio_id = ?"
+	args := []interface{}{id}
+	var scenes Scenes
+	if err := qb.dbi.RawQuery(sceneDBTable, query, args, &scenes); err != nil {
+		return nil, err
+	}
+
+	var ret []uuid.UUID
+	for _, scene := range scenes {
+		ret = append(ret, scene.ID)
+	}
+	return ret, nil
+}
+
+// GetEditSnapshot returns the current state of the studios affected by the
+// edit, so that the edit can be reverted once applied.
+func (qb *StudioQueryBuilder) GetEditSnapshot(edit Edit, studio *Studio) (*StudioEditSnapshot, error) {
+	data, err := edit.GetStudioData()
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := qb.getSnapshot(studio.ID)
+	if err != nil {
+		return nil, err
+	}
+	ret := &StudioEditSnapshot{Target: target}
+
+	// destroying the studio removes it from its scenes
+	if edit.Operation == OperationEnumDestroy.String() {
+		if target.SceneIDs, err = qb.findSceneIDs(studio.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, v := range data.MergeSources {
+		sourceUUID, _ := uuid.FromString(v)
+		source, err := qb.getSnapshot(sourceUUID)
+		if err != nil {
+			return nil, err
+		}
+		if source.SceneIDs, err = qb.findSceneIDs(sourceUUID); err != nil {
+			return nil, err
+		}
+		ret.MergeSources = append(ret.MergeSources, source)
+	}
+
+	if data.New != nil {
+		for _, v := range data.New.AddedChildStudios {
+			childID, _ := uuid.FromString(v)
+			child, err := qb.Find(childID)
+			if err != nil {
+				return nil, err
+			}
+			if child != nil {
+				ret.AddedChildStudios = append(ret.AddedChildStudios, child)
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+func (qb *StudioQueryBuilder) restoreSnapshot(snapshot StudioSnapshot) error {
+	studio := snapshot.Studio
+	studio.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
+	if _, err := qb.dbi.Update(studio, true); err != nil {
+		return err
+	}
+	if err := qb.UpdateUrls(studio.ID, snapshot.Urls); err != nil {
+		return err
+	}
+	if err := qb.UpdateImages(studio.ID, snapshot.Images); err != nil {
+		return err
+	}
+
+	parentID := uuid.NullUUID{UUID: studio.ID, Valid: true}
+	for _, childID := range snapshot.ChildStudioIDs {
+		if err := qb.UpdateParent(childID, parentID); err != nil {
+			return err
+		}
+	}
+
+	query := "UPDATE " + sceneDBTable.Name() + " SET studio_id = ? WHERE id = ?"
+	for _, sceneID := range snapshot.SceneIDs {
+		args := []interface{}{studio.ID, sceneID}
+		if err := qb.dbi.RawQuery(sceneDBTable, query, args, nil); err != nil {
+			return err
+		}
+	}
+
+	return restoreRedirects(qb.dbi, studioRedirectTable, studio.ID, snapshot.RedirectSourceIDs)
+}
+
+// RevertEdit undoes the changes of an applied edit to the studio, restoring
+// the studio, any merge sources and the parents of any added child studios
+// from the snapshot stored with the edit.
+func (qb *StudioQueryBuilder) RevertEdit(edit Edit, operation OperationEnum, studio *Studio) error {
+	if operation == OperationEnumCreate {
+		_, err := qb.SoftDelete(*studio)
+		return err
+	}
+
+	var snapshot StudioEditSnapshot
+	if err := edit.GetSnapshot(&snapshot); err != nil {
+		return err
+	}
+	if snapshot.Target == nil {
+		return errors.New("Edit snapshot does not contain the studio")
+	}
+
+	for _, child := range snapshot.AddedChildStudios {
+		if err := qb.UpdateParent(child.ID, child.ParentStudioID); err != nil {
+			return err
+		}
+	}
+	if err := qb.restoreSnapshot(*snapshot.Target); err != nil {
+		return err
+	}
+	for _, source := range snapshot.MergeSources {
+		if err := qb.restoreSnapshot(*source); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
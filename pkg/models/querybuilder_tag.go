@@ -256,6 +256,36 @@ func (qb *TagQueryBuilder) queryTags(query string, args []interface{}) (Tags, er
 	return output, err
 }
 
+// SearchTags returns the tags whose name or aliases contain words similar to
+// the term, or whose description contains the words of the term. Tags matched
+// by name or alias come first, the most similar first, followed by the tags
+// matched by description, ranked by their text search rank.
+func (qb *TagQueryBuilder) SearchTags(term string) (Tags, error) {
+	query := `
+        SELECT tags.* FROM (
+            SELECT DISTINCT ON (id) id, priority, score FROM (
+                SELECT id, 1 AS priority, word_similarity($1, name) AS score
+                FROM tags
+                WHERE $1 <% name
+                UNION ALL
+                SELECT tag_id, 1, word_similarity($1, alias)
+                FROM tag_aliases
+                WHERE $1 <% alias
+                UNION ALL
+                SELECT id, 2, ts_rank(to_tsvector('english', COALESCE(description, '')), plainto_tsquery('english', $1))
+                FROM tags
+                WHERE to_tsvector('english', COALESCE(description, '')) @@ plainto_tsquery('english', $1)
+            ) candidates
+            ORDER BY id, priority, score DESC
+        ) matches
+        JOIN tags ON tags.id = matches.id
+        WHERE tags.deleted = FALSE
+        ORDER BY matches.priority, matches.score DESC, tags.name
+        LIMIT 10`
+	args := []interface{}{term}
+	return qb.queryTags(query, args)
+}
+
 func (qb *TagQueryBuilder) GetRawAliases(id uuid.UUID) (TagAliases, error) {
 	joins := TagAliases{}
 	err := qb.dbi.FindJoins(tagAliasTable, id, &joins)
@@ -394,3 +424,112 @@ func (qb *TagQueryBuilder) ApplyEdit(edit Edit, operation OperationEnum, tag *Ta
 		return nil, errors.New("Unsupported operation: " + operation.String())
 	}
 }
+
+func (qb *TagQueryBuilder) getSnapshot(id uuid.UUID) (*TagSnapshot, error) {
+	tag, err := qb.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, errors.New("Tag not found: " + id.String())
+	}
+
+	aliases, err := qb.GetRawAliases(id)
+	if err != nil {
+		return nil, err
+	}
+	sceneTags := ScenesTags{}
+	if err := qb.dbi.FindJoins(tagSceneTable, id, &sceneTags); err != nil {
+		return nil, err
+	}
+	redirectSourceIDs, err := findRedirectSourceIDs(qb.dbi, tagRedirectTable, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TagSnapshot{
+		Tag:               *tag,
+		Aliases:           aliases,
+		SceneTags:         sceneTags,
+		RedirectSourceIDs: redirectSourceIDs,
+	}, nil
+}
+
+// GetEditSnapshot returns the current state of the tags affected by the
+// edit, so that the edit can be reverted once applied.
+func (qb *TagQueryBuilder) GetEditSnapshot(edit Edit, tag *Tag) (*TagEditSnapshot, error) {
+	data, err := edit.GetTagData()
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := qb.getSnapshot(tag.ID)
+	if err != nil {
+		return nil, err
+	}
+	ret := &TagEditSnapshot{Target: target}
+
+	for _, v := range data.MergeSources {
+		sourceUUID, _ := uuid.FromString(v)
+		source, err := qb.getSnapshot(sourceUUID)
+		if err != nil {
+			return nil, err
+		}
+		ret.MergeSources = append(ret.MergeSources, source)
+	}
+
+	return ret, nil
+}
+
+func (qb *TagQueryBuilder) restoreSnapshot(snapshot TagSnapshot) error {
+	tag := snapshot.Tag
+	tag.UpdatedAt = SQLiteTimestamp{Timestamp: time.Now()}
+	if _, err := qb.dbi.Update(tag, true); err != nil {
+		return err
+	}
+	if err := qb.UpdateAliases(tag.ID, snapshot.Aliases); err != nil {
+		return err
+	}
+	return restoreRedirects(qb.dbi, tagRedirectTable, tag.ID, snapshot.RedirectSourceIDs)
+}
+
+// RevertEdit undoes the changes of an applied edit to the tag, restoring
+// the tag and any merge sources from the snapshot stored with the edit.
+func (qb *TagQueryBuilder) RevertEdit(edit Edit, operation OperationEnum, tag *Tag) error {
+	if operation == OperationEnumCreate {
+		if _, err := qb.SoftDelete(*tag); err != nil {
+			return err
+		}
+		return qb.DeleteSceneTags(tag.ID)
+	}
+
+	var snapshot TagEditSnapshot
+	if err := edit.GetSnapshot(&snapshot); err != nil {
+		return err
+	}
+	if snapshot.Target == nil {
+		return errors.New("Edit snapshot does not contain the tag")
+	}
+
+	if err := qb.restoreSnapshot(*snapshot.Target); err != nil {
+		return err
+	}
+
+	if operation == OperationEnumDestroy {
+		return qb.dbi.InsertJoinsWithoutConflict(sceneTagTable, &snapshot.Target.SceneTags)
+	}
+
+	for _, source := range snapshot.MergeSources {
+		if err := qb.restoreSnapshot(*source); err != nil {
+			return err
+		}
+		if err := qb.dbi.InsertJoinsWithoutConflict(sceneTagTable, &source.SceneTags); err != nil {
+			return err
+		}
+		if err := deleteMergedSceneJoins(qb.dbi, sceneTagTable, "tag_id", tag.ID, source.SceneTags.SceneIDs(), snapshot.Target.SceneTags.SceneIDs()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
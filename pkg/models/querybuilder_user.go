@@ -1,9 +1,12 @@
 package models
 
 import (
+	"time"
+
 	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/stashapp/stashdb/pkg/database"
+	"github.com/stashapp/stashdb/pkg/utils"
 )
 
 // UserFinderUpdater is an interface to find and update User objects.
@@ -17,6 +20,28 @@ type UserFinderUpdater interface {
 	UpdateFull(updatedUser User) (*User, error)
 }
 
+// subqueries counting the edits and votes of each user by outcome of the
+// edit, for use in queries on the users table
+const (
+	userSuccessfulEditsQuery = `(SELECT COUNT(*) FROM edits
+        WHERE edits.user_id = users.id
+        AND edits.status IN ('ACCEPTED', 'IMMEDIATE_ACCEPTED'))`
+	userUnsuccessfulEditsQuery = `(SELECT COUNT(*) FROM edits
+        WHERE edits.user_id = users.id
+        AND edits.status IN ('REJECTED', 'IMMEDIATE_REJECTED'))`
+	// a vote is successful if it agrees with the outcome of the edit
+	userSuccessfulVotesQuery = `(SELECT COUNT(*) FROM edit_votes
+        JOIN edits ON edits.id = edit_votes.edit_id
+        WHERE edit_votes.user_id = users.id AND edit_votes.outdated = FALSE
+        AND ((edit_votes.vote = 'ACCEPT' AND edits.status IN ('ACCEPTED', 'IMMEDIATE_ACCEPTED'))
+        OR (edit_votes.vote = 'REJECT' AND edits.status IN ('REJECTED', 'IMMEDIATE_REJECTED'))))`
+	userUnsuccessfulVotesQuery = `(SELECT COUNT(*) FROM edit_votes
+        JOIN edits ON edits.id = edit_votes.edit_id
+        WHERE edit_votes.user_id = users.id AND edit_votes.outdated = FALSE
+        AND ((edit_votes.vote = 'ACCEPT' AND edits.status IN ('REJECTED', 'IMMEDIATE_REJECTED'))
+        OR (edit_votes.vote = 'REJECT' AND edits.status IN ('ACCEPTED', 'IMMEDIATE_ACCEPTED'))))`
+)
+
 type UserQueryBuilder struct {
 	dbi database.DBI
 }
@@ -110,6 +135,11 @@ func (qb *UserQueryBuilder) Query(userFilter *UserFilterType, findFilter *QueryS
 		query.AddArg(thisArgs...)
 	}
 
+	addSubqueryCriterion(query, userSuccessfulEditsQuery, userFilter.SuccessfulEdits)
+	addSubqueryCriterion(query, userUnsuccessfulEditsQuery, userFilter.UnsuccessfulEdits)
+	addSubqueryCriterion(query, userSuccessfulVotesQuery, userFilter.SuccessfulVotes)
+	addSubqueryCriterion(query, userUnsuccessfulVotesQuery, userFilter.UnsuccessfulVotes)
+
 	query.SortAndPagination = qb.getUserSort(findFilter) + getPagination(findFilter)
 	var studios Users
 	countResult, err := qb.dbi.Query(*query, &studios)
@@ -141,9 +171,49 @@ func (qb *UserQueryBuilder) queryUsers(query string, args []interface{}) (Users,
 	return output, err
 }
 
+// UpdateAPICalls sets the number of API calls of the user within the rate
+// window, and the time of the last call.
+func (qb *UserQueryBuilder) UpdateAPICalls(id uuid.UUID, calls int, lastCall time.Time) error {
+	query := "UPDATE " + userDBTable.Name() + " SET api_calls = ?, last_api_call = ? WHERE id = ?"
+	args := []interface{}{calls, lastCall, id}
+	return qb.dbi.RawQuery(userDBTable, query, args, nil)
+}
+
 func (qb *UserQueryBuilder) GetRoles(id uuid.UUID) (UserRoles, error) {
 	joins := UserRoles{}
 	err := qb.dbi.FindJoins(userRolesTable, id, &joins)
 
 	return joins, err
 }
+
+// GetAllEditStats returns the edit and vote statistics of each of the users.
+func (qb *UserQueryBuilder) GetAllEditStats(ids []uuid.UUID) ([]*UserEditStats, []error) {
+	query := `
+		SELECT users.id AS user_id,
+		` + userSuccessfulEditsQuery + ` AS successful_edits,
+		` + userUnsuccessfulEditsQuery + ` AS unsuccessful_edits,
+		` + userSuccessfulVotesQuery + ` AS successful_votes,
+		` + userUnsuccessfulVotesQuery + ` AS unsuccessful_votes
+		FROM users
+		WHERE users.id IN (?)
+	`
+	query, args, _ := sqlx.In(query, ids)
+	output := UserEditStatsList{}
+	if err := qb.dbi.RawQuery(userEditStatsTable, query, args, &output); err != nil {
+		return nil, utils.DuplicateError(err, len(ids))
+	}
+
+	m := make(map[uuid.UUID]*UserEditStats)
+	for _, stats := range output {
+		m[stats.UserID] = stats
+	}
+
+	result := make([]*UserEditStats, len(ids))
+	for i, id := range ids {
+		result[i] = m[id]
+		if result[i] == nil {
+			result[i] = &UserEditStats{UserID: id}
+		}
+	}
+	return result, nil
+}
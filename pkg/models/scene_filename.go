@@ -0,0 +1,146 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	sceneFilenameExtension = regexp.MustCompile(`(?i)\.(mp4|m4v|mkv|avi|wmv|mov|webm|flv|mpe?g|ts)$`)
+	// YYYY-MM-DD, also separated by dots or underscores
+	sceneFilenameLongDate = regexp.MustCompile(`(?:^|[^0-9])((?:19|20)[0-9]{2})[-._]([0-9]{2})[-._]([0-9]{2})(?:[^0-9]|$)`)
+	// YY.MM.DD
+	sceneFilenameShortDate  = regexp.MustCompile(`(?:^|[^0-9])([0-9]{2})\.([0-9]{2})\.([0-9]{2})(?:[^0-9]|$)`)
+	sceneFilenameSeparator  = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+	sceneFilenameResolution = regexp.MustCompile(`(?i)^[0-9]{3,4}p$`)
+)
+
+// sceneFilenameNoise are the release tags of filenames that do not describe
+// the scene.
+var sceneFilenameNoise = map[string]bool{
+	"4k":     true,
+	"uhd":    true,
+	"hd":     true,
+	"sd":     true,
+	"hdr":    true,
+	"x264":   true,
+	"x265":   true,
+	"h264":   true,
+	"h265":   true,
+	"hevc":   true,
+	"web":    true,
+	"webrip": true,
+	"webdl":  true,
+}
+
+// SceneFilename is a scene filename split into its parts.
+type SceneFilename struct {
+	// Studio is the studio name or abbreviation preceding the date
+	Studio string
+	// Date is the release date, formatted as YYYY-MM-DD
+	Date string
+	// Tokens are the remaining words, typically performer names and title
+	Tokens []string
+}
+
+// ParseSceneFilename parses a filename such as
+// Studio.20.05.14.Performer.Name.XXX.1080p.mp4 into its studio, date and
+// remaining words. The studio is only recognised before a date. Returns nil
+// if the term does not look like a filename.
+func ParseSceneFilename(term string) *SceneFilename {
+	term = strings.TrimSpace(term)
+	if term == "" || strings.ContainsAny(term, " \t") {
+		return nil
+	}
+
+	name := sceneFilenameExtension.ReplaceAllString(term, "")
+	if name == term && !strings.ContainsAny(term, "._") {
+		return nil
+	}
+
+	ret := &SceneFilename{}
+	before := name
+	after := ""
+	if date, start, end := parseSceneFilenameDate(name); date != "" {
+		ret.Date = date
+		before = name[:start]
+		after = name[end:]
+	}
+
+	words := sceneFilenameWords(before)
+	if ret.Date != "" {
+		ret.Studio = strings.Join(words, "")
+		words = nil
+	}
+	words = append(words, sceneFilenameWords(after)...)
+
+	for _, word := range words {
+		lower := strings.ToLower(word)
+		// everything after the XXX tag describes the release
+		if lower == "xxx" {
+			break
+		}
+		if sceneFilenameNoise[lower] || sceneFilenameResolution.MatchString(word) {
+			continue
+		}
+		ret.Tokens = append(ret.Tokens, word)
+	}
+
+	return ret
+}
+
+// parseSceneFilenameDate returns the first valid date in the name, along with
+// the start and end of the date in the name.
+func parseSceneFilenameDate(name string) (string, int, int) {
+	if date, start, end := findSceneFilenameDate(name, sceneFilenameLongDate, func(year int) int {
+		return year
+	}); date != "" {
+		return date, start, end
+	}
+
+	return findSceneFilenameDate(name, sceneFilenameShortDate, func(year int) int {
+		// two digit years are assumed to be in the last hundred years
+		year += 2000
+		if year > time.Now().Year() {
+			year -= 100
+		}
+		return year
+	})
+}
+
+// findSceneFilenameDate returns the first match of the date expression in the
+// name that is a valid date, along with its start and end in the name.
+func findSceneFilenameDate(name string, expression *regexp.Regexp, toYear func(int) int) (string, int, int) {
+	offset := 0
+	for {
+		match := expression.FindStringSubmatchIndex(name[offset:])
+		if match == nil {
+			return "", 0, 0
+		}
+		for i := range match {
+			match[i] += offset
+		}
+
+		year, _ := strconv.Atoi(name[match[2]:match[3]])
+		date := fmt.Sprintf("%04d-%s-%s", toYear(year), name[match[4]:match[5]], name[match[6]:match[7]])
+		if _, err := time.Parse("2006-01-02", date); err == nil {
+			return date, match[2], match[7]
+		}
+
+		// matches may overlap, so continue after the first number
+		offset = match[3]
+	}
+}
+
+func sceneFilenameWords(s string) []string {
+	var ret []string
+	for _, word := range sceneFilenameSeparator.Split(s, -1) {
+		if word != "" {
+			ret = append(ret, word)
+		}
+	}
+	return ret
+}
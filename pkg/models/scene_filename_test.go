@@ -0,0 +1,49 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSceneFilename(t *testing.T) {
+	tests := []struct {
+		term     string
+		expected *SceneFilename
+	}{
+		{
+			"Studio.20.05.14.Performer.Name.XXX.1080p.mp4",
+			&SceneFilename{Studio: "Studio", Date: "2020-05-14", Tokens: []string{"Performer", "Name"}},
+		},
+		{
+			"Reality.Kings_2019-02-03_Jane_Doe_Scene_Title_2160p.mkv",
+			&SceneFilename{Studio: "RealityKings", Date: "2019-02-03", Tokens: []string{"Jane", "Doe", "Scene", "Title"}},
+		},
+		{
+			"Jane.Doe.Scene.Title.x264.mp4",
+			&SceneFilename{Tokens: []string{"Jane", "Doe", "Scene", "Title"}},
+		},
+		{
+			// invalid dates are words
+			"Studio.20.13.45.Jane.Doe",
+			&SceneFilename{Tokens: []string{"Studio", "20", "13", "45", "Jane", "Doe"}},
+		},
+		{
+			// numbers preceding the date
+			"Studio12.20.05.14.Jane.Doe.mp4",
+			&SceneFilename{Studio: "Studio12", Date: "2020-05-14", Tokens: []string{"Jane", "Doe"}},
+		},
+		{
+			"95.01.31.Jane.Doe.avi",
+			&SceneFilename{Date: "1995-01-31", Tokens: []string{"Jane", "Doe"}},
+		},
+		{"scene search title 2019-02-03", nil},
+		{"janedoe", nil},
+	}
+
+	for _, test := range tests {
+		got := ParseSceneFilename(test.term)
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("ParseSceneFilename(%q): got %+v want %+v", test.term, got, test.expected)
+		}
+	}
+}
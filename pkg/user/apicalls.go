@@ -0,0 +1,191 @@
+package user
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stashdb/pkg/models"
+)
+
+// number of buckets the rolling window of API calls is divided into
+const apiCallBuckets = 60
+
+// APICallCounter counts the API calls of each user over a rolling window.
+// Counts are kept in memory and written to the database in batches by Flush.
+type APICallCounter struct {
+	mu    sync.Mutex
+	users map[uuid.UUID]*apiCalls
+}
+
+type apiCalls struct {
+	// calls per bucket, buckets[head] being the most recent
+	buckets     [apiCallBuckets]int
+	head        int
+	bucketStart time.Time
+	bucketSize  time.Duration
+	total       int
+	lastCall    time.Time
+	// set when the counts have changed since the last flush
+	dirty bool
+	// incremented on each change of the counts
+	version int
+}
+
+func NewAPICallCounter() *APICallCounter {
+	return &APICallCounter{
+		users: make(map[uuid.UUID]*apiCalls),
+	}
+}
+
+// Record counts an API call of the user at the provided time. If the user
+// has already made limit calls within the rolling window, the call is
+// refused and not counted, and the time until the user may call the API
+// again is returned. A limit of zero disables the limit.
+func (c *APICallCounter) Record(u *models.User, limit int, window time.Duration, now time.Time) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	calls := c.users[u.ID]
+	if calls == nil {
+		calls = newAPICalls(u, window, now)
+		c.users[u.ID] = calls
+	}
+
+	calls.advance(now)
+	if limit > 0 && calls.total >= limit {
+		return calls.retryAfter(limit, now), false
+	}
+
+	calls.buckets[calls.head]++
+	calls.total++
+	calls.lastCall = now
+	calls.changed()
+
+	return 0, true
+}
+
+// APICallCount is the number of API calls a user has made within the
+// rolling window.
+type APICallCount struct {
+	UserID   uuid.UUID
+	Calls    int
+	LastCall time.Time
+}
+
+// Flush passes the call counts changed since the last flush to write, which
+// should store them in the database. The counts are only marked as written
+// if write succeeds, so that they are passed again on the next flush
+// otherwise. Users that have not called the API within the rolling window
+// are no longer tracked once their count of zero is written.
+func (c *APICallCounter) Flush(now time.Time, write func(counts []APICallCount) error) error {
+	type flushedCalls struct {
+		calls   *apiCalls
+		version int
+	}
+
+	// collect the changes first, so that requests are not held up by the
+	// database writes
+	var counts []APICallCount
+	var flushed []flushedCalls
+	c.mu.Lock()
+	for id, calls := range c.users {
+		calls.advance(now)
+		if calls.dirty {
+			counts = append(counts, APICallCount{id, calls.total, calls.lastCall})
+			flushed = append(flushed, flushedCalls{calls, calls.version})
+		} else if calls.total == 0 {
+			delete(c.users, id)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	if err := write(counts); err != nil {
+		return err
+	}
+
+	// counts that changed during the write are passed again next time
+	c.mu.Lock()
+	for _, f := range flushed {
+		if f.calls.version == f.version {
+			f.calls.dirty = false
+		}
+	}
+	c.mu.Unlock()
+
+	return nil
+}
+
+// newAPICalls starts counting the calls of the user, taking into account
+// the calls stored in the database if the last one falls within the window.
+func newAPICalls(u *models.User, window time.Duration, now time.Time) *apiCalls {
+	bucketSize := window / apiCallBuckets
+	if bucketSize <= 0 {
+		bucketSize = 1
+	}
+
+	ret := &apiCalls{
+		bucketStart: now.Truncate(bucketSize),
+		bucketSize:  bucketSize,
+		lastCall:    u.LastAPICall.Timestamp,
+	}
+
+	// the stored calls are assumed to have been made at the time of the
+	// last call
+	age := int(ret.bucketStart.Sub(u.LastAPICall.Timestamp.Truncate(bucketSize)) / bucketSize)
+	if u.APICalls > 0 && age >= 0 && age < apiCallBuckets {
+		ret.head = age
+		ret.buckets[0] = u.APICalls
+		ret.total = u.APICalls
+	}
+
+	return ret
+}
+
+// advance moves the window forward to the provided time, dropping the calls
+// of the buckets that fall out of the window.
+func (a *apiCalls) advance(now time.Time) {
+	total := a.total
+	for n := 0; n < apiCallBuckets && !now.Before(a.bucketStart.Add(a.bucketSize)); n++ {
+		a.head = (a.head + 1) % apiCallBuckets
+		a.total -= a.buckets[a.head]
+		a.buckets[a.head] = 0
+		a.bucketStart = a.bucketStart.Add(a.bucketSize)
+	}
+
+	// the window is empty if the last call was older than the window
+	if !now.Before(a.bucketStart.Add(a.bucketSize)) {
+		a.bucketStart = now.Truncate(a.bucketSize)
+	}
+
+	if a.total != total {
+		a.changed()
+	}
+}
+
+// changed marks the counts as changed since the last flush.
+func (a *apiCalls) changed() {
+	a.dirty = true
+	a.version++
+}
+
+// retryAfter returns the time until enough of the oldest buckets fall out
+// of the window for the number of calls to drop below the limit.
+func (a *apiCalls) retryAfter(limit int, now time.Time) time.Duration {
+	excess := a.total - limit + 1
+	removed := 0
+	for j := 0; j < apiCallBuckets; j++ {
+		removed += a.buckets[(a.head+1+j)%apiCallBuckets]
+		if removed >= excess {
+			bucketEnd := a.bucketStart.Add(time.Duration(j+1) * a.bucketSize)
+			return bucketEnd.Sub(now)
+		}
+	}
+
+	return a.bucketStart.Add(apiCallBuckets * a.bucketSize).Sub(now)
+}
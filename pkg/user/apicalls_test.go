@@ -0,0 +1,147 @@
+package user_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/stashapp/stashdb/pkg/models"
+	. "github.com/stashapp/stashdb/pkg/user"
+)
+
+var apiCallTime = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const apiCallWindow = time.Minute
+
+func newAPICallUser() *models.User {
+	id, _ := uuid.NewV4()
+	return &models.User{ID: id}
+}
+
+func recordAPICalls(t *testing.T, c *APICallCounter, u *models.User, limit int, n int, now time.Time) {
+	for i := 0; i < n; i++ {
+		if _, ok := c.Record(u, limit, apiCallWindow, now); !ok {
+			t.Fatalf("call %d refused", i)
+		}
+	}
+}
+
+func TestAPICallLimit(t *testing.T) {
+	c := NewAPICallCounter()
+	u := newAPICallUser()
+
+	recordAPICalls(t, c, u, 3, 3, apiCallTime)
+
+	retryAfter, ok := c.Record(u, 3, apiCallWindow, apiCallTime)
+	if ok {
+		t.Fatal("call over limit allowed")
+	}
+	if retryAfter != apiCallWindow {
+		t.Errorf("retry after: got %v want %v", retryAfter, apiCallWindow)
+	}
+
+	// calls fall out of the window once it has elapsed
+	recordAPICalls(t, c, u, 3, 3, apiCallTime.Add(apiCallWindow))
+}
+
+func TestAPICallRollingWindow(t *testing.T) {
+	c := NewAPICallCounter()
+	u := newAPICallUser()
+
+	recordAPICalls(t, c, u, 3, 2, apiCallTime)
+	recordAPICalls(t, c, u, 3, 1, apiCallTime.Add(30*time.Second))
+
+	now := apiCallTime.Add(31 * time.Second)
+	retryAfter, ok := c.Record(u, 3, apiCallWindow, now)
+	if ok {
+		t.Fatal("call over limit allowed")
+	}
+
+	// the first calls leave the window first
+	expected := 29 * time.Second
+	if retryAfter != expected {
+		t.Errorf("retry after: got %v want %v", retryAfter, expected)
+	}
+
+	recordAPICalls(t, c, u, 3, 2, apiCallTime.Add(apiCallWindow))
+	if _, ok := c.Record(u, 3, apiCallWindow, apiCallTime.Add(apiCallWindow)); ok {
+		t.Error("call over limit allowed")
+	}
+}
+
+func TestAPICallNoLimit(t *testing.T) {
+	c := NewAPICallCounter()
+	recordAPICalls(t, c, newAPICallUser(), 0, 100, apiCallTime)
+}
+
+func TestAPICallStoredCalls(t *testing.T) {
+	c := NewAPICallCounter()
+	u := newAPICallUser()
+	u.APICalls = 3
+	u.LastAPICall = models.SQLiteTimestamp{Timestamp: apiCallTime.Add(-10 * time.Second)}
+
+	retryAfter, ok := c.Record(u, 3, apiCallWindow, apiCallTime)
+	if ok {
+		t.Fatal("call over limit allowed")
+	}
+
+	expected := 50 * time.Second
+	if retryAfter != expected {
+		t.Errorf("retry after: got %v want %v", retryAfter, expected)
+	}
+
+	// stored calls older than the window are ignored
+	c = NewAPICallCounter()
+	u.LastAPICall = models.SQLiteTimestamp{Timestamp: apiCallTime.Add(-apiCallWindow)}
+	recordAPICalls(t, c, u, 3, 3, apiCallTime)
+}
+
+func flushAPICalls(t *testing.T, c *APICallCounter, now time.Time) []APICallCount {
+	var ret []APICallCount
+	err := c.Flush(now, func(counts []APICallCount) error {
+		ret = counts
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("flush: %s", err.Error())
+	}
+	return ret
+}
+
+func TestAPICallFlush(t *testing.T) {
+	c := NewAPICallCounter()
+	u := newAPICallUser()
+
+	recordAPICalls(t, c, u, 0, 2, apiCallTime)
+
+	// counts are kept for the next flush if the write fails
+	writeErr := errors.New("write failed")
+	err := c.Flush(apiCallTime, func(counts []APICallCount) error {
+		return writeErr
+	})
+	if err != writeErr {
+		t.Fatalf("flush error: got %v want %v", err, writeErr)
+	}
+
+	counts := flushAPICalls(t, c, apiCallTime)
+	if len(counts) != 1 || counts[0].UserID != u.ID || counts[0].Calls != 2 {
+		t.Fatalf("counts: got %v want 2 calls", counts)
+	}
+
+	if counts := flushAPICalls(t, c, apiCallTime); len(counts) != 0 {
+		t.Errorf("unchanged counts flushed: %v", counts)
+	}
+
+	// counts dropping out of the window are written as zero
+	now := apiCallTime.Add(apiCallWindow)
+	counts = flushAPICalls(t, c, now)
+	if len(counts) != 1 || counts[0].Calls != 0 {
+		t.Fatalf("counts: got %v want 0 calls", counts)
+	}
+
+	if counts := flushAPICalls(t, c, now); len(counts) != 0 {
+		t.Errorf("unchanged counts flushed: %v", counts)
+	}
+}